@@ -0,0 +1,158 @@
+// internal/api/attribute_handler.go
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"phone-accessories/internal/models"
+	"phone-accessories/internal/service"
+)
+
+type AttributeHandler struct {
+	service service.AttributeService
+}
+
+func NewAttributeHandler(service service.AttributeService) *AttributeHandler {
+	return &AttributeHandler{service: service}
+}
+
+// GetSchema godoc
+// @Summary      Get category attribute schema
+// @Description  Get the attribute definitions that apply to products of a category, including those inherited from its parent categories
+// @Tags         categories
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "Category ID"
+// @Success      200  {array}   models.AttributeDefinition
+// @Failure      404  {object}  ErrorResponse
+// @Failure      500  {object}  ErrorResponse
+// @Router       /categories/{id}/attributes [get]
+func (h *AttributeHandler) GetSchema(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid category ID"})
+		return
+	}
+
+	schema, err := h.service.GetSchema(uint(id))
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusInternalServerError), ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, schema)
+}
+
+// CreateAttribute godoc
+// @Summary      Define category attribute
+// @Description  Add an attribute definition to a category; it applies to the category's subcategories too, unless they redefine the key
+// @Tags         categories
+// @Accept       json
+// @Produce      json
+// @Param        id         path      int                         true  "Category ID"
+// @Param        attribute  body      models.AttributeDefinition  true  "Attribute definition"
+// @Success      201        {object}  models.AttributeDefinition
+// @Failure      400        {object}  ErrorResponse
+// @Failure      404        {object}  ErrorResponse
+// @Failure      500        {object}  ErrorResponse
+// @Router       /categories/{id}/attributes [post]
+func (h *AttributeHandler) CreateAttribute(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid category ID"})
+		return
+	}
+
+	var definition models.AttributeDefinition
+	if err := c.ShouldBindJSON(&definition); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid attribute data"})
+		return
+	}
+	definition.CategoryID = uint(id)
+
+	if err := h.service.CreateAttribute(&definition); err != nil {
+		c.JSON(errorStatus(err, http.StatusBadRequest), ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, definition)
+}
+
+// UpdateAttribute godoc
+// @Summary      Update category attribute
+// @Description  Update an attribute definition of a category
+// @Tags         categories
+// @Accept       json
+// @Produce      json
+// @Param        id           path      int                         true  "Category ID"
+// @Param        attributeId  path      int                         true  "Attribute definition ID"
+// @Param        attribute    body      models.AttributeDefinition  true  "Attribute definition"
+// @Success      200          {object}  models.AttributeDefinition
+// @Failure      400          {object}  ErrorResponse
+// @Failure      404          {object}  ErrorResponse
+// @Failure      500          {object}  ErrorResponse
+// @Router       /categories/{id}/attributes/{attributeId} [put]
+func (h *AttributeHandler) UpdateAttribute(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid category ID"})
+		return
+	}
+	attributeID, err := strconv.ParseUint(c.Param("attributeId"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid attribute ID"})
+		return
+	}
+
+	var definition models.AttributeDefinition
+	if err := c.ShouldBindJSON(&definition); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid attribute data"})
+		return
+	}
+
+	// Ensure the IDs in the path match the definition
+	definition.ID = uint(attributeID)
+	definition.CategoryID = uint(id)
+
+	if err := h.service.UpdateAttribute(&definition); err != nil {
+		c.JSON(errorStatus(err, http.StatusBadRequest), ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, definition)
+}
+
+// DeleteAttribute godoc
+// @Summary      Delete category attribute
+// @Description  Remove an attribute definition from a category
+// @Tags         categories
+// @Accept       json
+// @Produce      json
+// @Param        id           path      int  true  "Category ID"
+// @Param        attributeId  path      int  true  "Attribute definition ID"
+// @Success      204          {object}  nil
+// @Failure      404          {object}  ErrorResponse
+// @Failure      500          {object}  ErrorResponse
+// @Router       /categories/{id}/attributes/{attributeId} [delete]
+func (h *AttributeHandler) DeleteAttribute(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid category ID"})
+		return
+	}
+	attributeID, err := strconv.ParseUint(c.Param("attributeId"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid attribute ID"})
+		return
+	}
+
+	if err := h.service.DeleteAttribute(uint(id), uint(attributeID)); err != nil {
+		c.JSON(errorStatus(err, http.StatusInternalServerError), ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,165 @@
+// internal/api/brand_handler.go
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"phone-accessories/internal/models"
+	"phone-accessories/internal/service"
+)
+
+type BrandHandler struct {
+	service service.BrandService
+}
+
+func NewBrandHandler(service service.BrandService) *BrandHandler {
+	return &BrandHandler{service: service}
+}
+
+// ListBrands godoc
+// @Summary      List brands
+// @Description  Get all brands, optionally only active ones
+// @Tags         brands
+// @Accept       json
+// @Produce      json
+// @Param        active  query     bool    false  "Filter by active flag"
+// @Param        q       query     string  false  "Search by brand name"
+// @Success      200     {array}   models.Brand
+// @Failure      400     {object}  ErrorResponse
+// @Failure      500     {object}  ErrorResponse
+// @Router       /brands [get]
+func (h *BrandHandler) ListBrands(c *gin.Context) {
+	var filter models.BrandFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid filter parameters"})
+		return
+	}
+
+	brands, err := h.service.ListBrands(filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, brands)
+}
+
+// GetBrand godoc
+// @Summary      Get brand by ID
+// @Description  Get detailed information about a brand
+// @Tags         brands
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "Brand ID"
+// @Success      200  {object}  models.Brand
+// @Failure      404  {object}  ErrorResponse
+// @Failure      500  {object}  ErrorResponse
+// @Router       /brands/{id} [get]
+func (h *BrandHandler) GetBrand(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid brand ID"})
+		return
+	}
+
+	brand, err := h.service.GetBrandByID(uint(id))
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusInternalServerError), ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, brand)
+}
+
+// CreateBrand godoc
+// @Summary      Create brand
+// @Description  Add a new brand
+// @Tags         brands
+// @Accept       json
+// @Produce      json
+// @Param        brand  body      models.Brand  true  "Brand information"
+// @Success      201    {object}  models.Brand
+// @Failure      400    {object}  ErrorResponse
+// @Failure      500    {object}  ErrorResponse
+// @Router       /brands [post]
+func (h *BrandHandler) CreateBrand(c *gin.Context) {
+	var brand models.Brand
+	if err := c.ShouldBindJSON(&brand); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid brand data"})
+		return
+	}
+
+	if err := h.service.CreateBrand(&brand); err != nil {
+		c.JSON(errorStatus(err, http.StatusBadRequest), ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, brand)
+}
+
+// UpdateBrand godoc
+// @Summary      Update brand
+// @Description  Update an existing brand
+// @Tags         brands
+// @Accept       json
+// @Produce      json
+// @Param        id     path      int           true  "Brand ID"
+// @Param        brand  body      models.Brand  true  "Brand information"
+// @Success      200    {object}  models.Brand
+// @Failure      400    {object}  ErrorResponse
+// @Failure      404    {object}  ErrorResponse
+// @Failure      500    {object}  ErrorResponse
+// @Router       /brands/{id} [put]
+func (h *BrandHandler) UpdateBrand(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid brand ID"})
+		return
+	}
+
+	var brand models.Brand
+	if err := c.ShouldBindJSON(&brand); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid brand data"})
+		return
+	}
+
+	// Ensure the ID in the path matches the brand
+	brand.ID = uint(id)
+
+	if err := h.service.UpdateBrand(&brand); err != nil {
+		c.JSON(errorStatus(err, http.StatusBadRequest), ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, brand)
+}
+
+// DeleteBrand godoc
+// @Summary      Delete brand
+// @Description  Delete a brand that no device model or product refers to
+// @Tags         brands
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "Brand ID"
+// @Success      204  {object}  nil
+// @Failure      404  {object}  ErrorResponse
+// @Failure      409  {object}  ErrorResponse
+// @Failure      500  {object}  ErrorResponse
+// @Router       /brands/{id} [delete]
+func (h *BrandHandler) DeleteBrand(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid brand ID"})
+		return
+	}
+
+	if err := h.service.DeleteBrand(uint(id)); err != nil {
+		c.JSON(errorStatus(err, http.StatusInternalServerError), ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -37,6 +37,52 @@ func (h *CategoryHandler) ListCategories(c *gin.Context) {
 	c.JSON(http.StatusOK, categories)
 }
 
+// GetCategoryTree godoc
+// @Summary      Get category tree
+// @Description  Get all product categories nested under their parents
+// @Tags         categories
+// @Accept       json
+// @Produce      json
+// @Success      200  {array}   models.CategoryNode
+// @Failure      500  {object}  ErrorResponse
+// @Router       /categories/tree [get]
+func (h *CategoryHandler) GetCategoryTree(c *gin.Context) {
+	tree, err := h.service.GetCategoryTree()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, tree)
+}
+
+// GetBreadcrumbs godoc
+// @Summary      Get category breadcrumbs
+// @Description  Get the path of categories from the root down to a category
+// @Tags         categories
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "Category ID"
+// @Success      200  {array}   models.Category
+// @Failure      404  {object}  ErrorResponse
+// @Failure      500  {object}  ErrorResponse
+// @Router       /categories/{id}/breadcrumbs [get]
+func (h *CategoryHandler) GetBreadcrumbs(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid category ID"})
+		return
+	}
+
+	breadcrumbs, err := h.service.GetBreadcrumbs(uint(id))
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusInternalServerError), ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, breadcrumbs)
+}
+
 // GetCategory godoc
 // @Summary      Get category by ID
 // @Description  Get detailed information about a category
@@ -64,6 +110,33 @@ func (h *CategoryHandler) GetCategory(c *gin.Context) {
 	c.JSON(http.StatusOK, category)
 }
 
+// GetCategoryBySlug godoc
+// @Summary      Get category by slug
+// @Description  Get detailed information about a category from its URL slug. An old slug of a renamed category answers 301 with the current slug in the Location header.
+// @Tags         categories
+// @Accept       json
+// @Produce      json
+// @Param        slug  path      string  true  "Category slug"
+// @Success      200   {object}  models.Category
+// @Success      301   {object}  SlugRedirectResponse
+// @Failure      404   {object}  ErrorResponse
+// @Failure      500   {object}  ErrorResponse
+// @Router       /categories/slug/{slug} [get]
+func (h *CategoryHandler) GetCategoryBySlug(c *gin.Context) {
+	category, err := h.service.GetCategoryBySlug(c.Param("slug"))
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusInternalServerError), ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	if category.Slug != c.Param("slug") {
+		redirectToSlug(c, category.Slug)
+		return
+	}
+
+	c.JSON(http.StatusOK, category)
+}
+
 // CreateCategory godoc
 // @Summary      Create category
 // @Description  Add a new product category
@@ -92,7 +165,7 @@ func (h *CategoryHandler) CreateCategory(c *gin.Context) {
 
 // UpdateCategory godoc
 // @Summary      Update category
-// @Description  Update an existing product category
+// @Description  Update an existing product category. Send the version the edit is based on to have it rejected with 409 when the category changed in the meantime; without a version the update is not checked.
 // @Tags         categories
 // @Accept       json
 // @Produce      json
@@ -101,6 +174,7 @@ func (h *CategoryHandler) CreateCategory(c *gin.Context) {
 // @Success      200       {object}  models.Category
 // @Failure      400       {object}  ErrorResponse
 // @Failure      404       {object}  ErrorResponse
+// @Failure      409       {object}  ErrorResponse
 // @Failure      500       {object}  ErrorResponse
 // @Router       /categories/{id} [put]
 func (h *CategoryHandler) UpdateCategory(c *gin.Context) {
@@ -120,7 +194,7 @@ func (h *CategoryHandler) UpdateCategory(c *gin.Context) {
 	category.ID = uint(id)
 
 	if err := h.service.UpdateCategory(&category); err != nil {
-		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		c.JSON(errorStatus(err, http.StatusBadRequest), ErrorResponse{Error: err.Error()})
 		return
 	}
 
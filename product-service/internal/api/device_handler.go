@@ -0,0 +1,220 @@
+// internal/api/device_handler.go
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"phone-accessories/internal/models"
+	"phone-accessories/internal/service"
+)
+
+type DeviceHandler struct {
+	service service.DeviceService
+}
+
+func NewDeviceHandler(service service.DeviceService) *DeviceHandler {
+	return &DeviceHandler{service: service}
+}
+
+// ListDevices godoc
+// @Summary      List device models
+// @Description  Get paginated device models, optionally for a single brand
+// @Tags         devices
+// @Accept       json
+// @Produce      json
+// @Param        brandId   query     int     false  "Filter by brand ID"
+// @Param        q         query     string  false  "Search by model name"
+// @Param        page      query     int     false  "Page number"
+// @Param        pageSize  query     int     false  "Items per page"
+// @Success      200       {object}  models.PaginatedResponse
+// @Failure      400       {object}  ErrorResponse
+// @Failure      500       {object}  ErrorResponse
+// @Router       /devices [get]
+func (h *DeviceHandler) ListDevices(c *gin.Context) {
+	var filter models.DeviceFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid filter parameters"})
+		return
+	}
+
+	result, err := h.service.ListDevices(filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// GetDevice godoc
+// @Summary      Get device model by ID
+// @Description  Get detailed information about a device model
+// @Tags         devices
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "Device model ID"
+// @Success      200  {object}  models.DeviceModel
+// @Failure      404  {object}  ErrorResponse
+// @Failure      500  {object}  ErrorResponse
+// @Router       /devices/{id} [get]
+func (h *DeviceHandler) GetDevice(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid device ID"})
+		return
+	}
+
+	device, err := h.service.GetDeviceByID(uint(id))
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusInternalServerError), ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, device)
+}
+
+// CreateDevice godoc
+// @Summary      Create device model
+// @Description  Add a new device model for a brand
+// @Tags         devices
+// @Accept       json
+// @Produce      json
+// @Param        device  body      models.DeviceModel  true  "Device model information"
+// @Success      201     {object}  models.DeviceModel
+// @Failure      400     {object}  ErrorResponse
+// @Failure      404     {object}  ErrorResponse
+// @Failure      500     {object}  ErrorResponse
+// @Router       /devices [post]
+func (h *DeviceHandler) CreateDevice(c *gin.Context) {
+	var device models.DeviceModel
+	if err := c.ShouldBindJSON(&device); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid device data"})
+		return
+	}
+
+	if err := h.service.CreateDevice(&device); err != nil {
+		c.JSON(errorStatus(err, http.StatusBadRequest), ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, device)
+}
+
+// UpdateDevice godoc
+// @Summary      Update device model
+// @Description  Update an existing device model
+// @Tags         devices
+// @Accept       json
+// @Produce      json
+// @Param        id      path      int                 true  "Device model ID"
+// @Param        device  body      models.DeviceModel  true  "Device model information"
+// @Success      200     {object}  models.DeviceModel
+// @Failure      400     {object}  ErrorResponse
+// @Failure      404     {object}  ErrorResponse
+// @Failure      500     {object}  ErrorResponse
+// @Router       /devices/{id} [put]
+func (h *DeviceHandler) UpdateDevice(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid device ID"})
+		return
+	}
+
+	var device models.DeviceModel
+	if err := c.ShouldBindJSON(&device); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid device data"})
+		return
+	}
+
+	// Ensure the ID in the path matches the device
+	device.ID = uint(id)
+
+	if err := h.service.UpdateDevice(&device); err != nil {
+		c.JSON(errorStatus(err, http.StatusBadRequest), ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, device)
+}
+
+// DeleteDevice godoc
+// @Summary      Delete device model
+// @Description  Delete a device model and its links to compatible products
+// @Tags         devices
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "Device model ID"
+// @Success      204  {object}  nil
+// @Failure      404  {object}  ErrorResponse
+// @Failure      500  {object}  ErrorResponse
+// @Router       /devices/{id} [delete]
+func (h *DeviceHandler) DeleteDevice(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid device ID"})
+		return
+	}
+
+	if err := h.service.DeleteDevice(uint(id)); err != nil {
+		c.JSON(errorStatus(err, http.StatusInternalServerError), ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ListDeviceProducts godoc
+// @Summary      List products compatible with a device
+// @Description  Get paginated products that fit a device model, with the same filters as the product list
+// @Tags         devices
+// @Accept       json
+// @Produce      json
+// @Param        id          path      int     true   "Device model ID"
+// @Param        categoryId  query     int     false  "Filter by category ID"
+// @Param        priceList   query     int     false  "Resolve prices in this price list"
+// @Param        currency    query     string  false  "Resolve prices in this currency, through its default price list and exchange rates"
+// @Param        minPrice    query     string  false  "Filter by minimum price, as a decimal amount in the resolved currency"
+// @Param        maxPrice    query     string  false  "Filter by maximum price, as a decimal amount in the resolved currency"
+// @Param        q           query     string  false  "Search query"
+// @Param        inStock     query     bool    false  "Filter by stock availability"
+// @Param        onSale      query     bool    false  "Filter by whether a promotion currently puts the product on sale"
+// @Param        attr        query     string  false  "Filter by attribute, as attr.key=value1,value2 or attr.key[gte]=20 (also gt, lt, lte)"
+// @Param        sortBy      query     string  false  "Sort field"
+// @Param        sortDir     query     string  false  "Sort direction (asc or desc)"
+// @Param        page        query     int     false  "Page number"
+// @Param        pageSize    query     int     false  "Items per page"
+// @Success      200         {object}  models.PaginatedResponse
+// @Failure      400         {object}  ErrorResponse
+// @Failure      404         {object}  ErrorResponse
+// @Failure      500         {object}  ErrorResponse
+// @Router       /devices/{id}/products [get]
+func (h *DeviceHandler) ListDeviceProducts(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid device ID"})
+		return
+	}
+
+	var filter models.ProductFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid filter parameters"})
+		return
+	}
+	attributes, err := parseAttributeFilters(c.Request.URL.Query())
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+	filter.Attributes = attributes
+
+	result, err := h.service.ListDeviceProducts(uint(id), filter)
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusInternalServerError), ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,167 @@
+// internal/api/discount_handler.go
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"phone-accessories/internal/models"
+	"phone-accessories/internal/service"
+)
+
+type DiscountHandler struct {
+	service service.DiscountService
+}
+
+func NewDiscountHandler(service service.DiscountService) *DiscountHandler {
+	return &DiscountHandler{service: service}
+}
+
+// ListDiscountRules godoc
+// @Summary      List discount rules
+// @Description  Get discount rules in the order they are applied, optionally only those valid now with uses left
+// @Tags         discounts
+// @Accept       json
+// @Produce      json
+// @Param        active   query     bool  false  "Filter by active flag"
+// @Param        current  query     bool  false  "Only rules valid now with uses left"
+// @Success      200      {array}   models.DiscountRule
+// @Failure      400      {object}  ErrorResponse
+// @Failure      500      {object}  ErrorResponse
+// @Router       /discounts [get]
+func (h *DiscountHandler) ListDiscountRules(c *gin.Context) {
+	var filter models.DiscountRuleFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid filter parameters"})
+		return
+	}
+
+	rules, err := h.service.ListDiscountRules(filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, rules)
+}
+
+// GetDiscountRule godoc
+// @Summary      Get discount rule by ID
+// @Description  Get detailed information about a discount rule, including how often it has been used
+// @Tags         discounts
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "Discount rule ID"
+// @Success      200  {object}  models.DiscountRule
+// @Failure      404  {object}  ErrorResponse
+// @Failure      500  {object}  ErrorResponse
+// @Router       /discounts/{id} [get]
+func (h *DiscountHandler) GetDiscountRule(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid discount rule ID"})
+		return
+	}
+
+	rule, err := h.service.GetDiscountRuleByID(uint(id))
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusInternalServerError), ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, rule)
+}
+
+// CreateDiscountRule godoc
+// @Summary      Create discount rule
+// @Description  Create a cart discount: a percentage or fixed amount off, or buy X get Y free, over the whole cart or a product, category or brand, valid between startsAt and endsAt. A minimum basket, a coupon code and a usage limit are optional; rules without a coupon code apply to every cart they match.
+// @Tags         discounts
+// @Accept       json
+// @Produce      json
+// @Param        rule  body      models.DiscountRule  true  "Discount rule information"
+// @Success      201   {object}  models.DiscountRule
+// @Failure      400   {object}  ErrorResponse
+// @Failure      404   {object}  ErrorResponse
+// @Failure      409   {object}  ErrorResponse
+// @Failure      500   {object}  ErrorResponse
+// @Router       /discounts [post]
+func (h *DiscountHandler) CreateDiscountRule(c *gin.Context) {
+	var rule models.DiscountRule
+	if err := c.ShouldBindJSON(&rule); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid discount rule data"})
+		return
+	}
+
+	if err := h.service.CreateDiscountRule(&rule); err != nil {
+		c.JSON(errorStatus(err, http.StatusBadRequest), ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, rule)
+}
+
+// UpdateDiscountRule godoc
+// @Summary      Update discount rule
+// @Description  Update an existing discount rule; its usage count is kept
+// @Tags         discounts
+// @Accept       json
+// @Produce      json
+// @Param        id    path      int                  true  "Discount rule ID"
+// @Param        rule  body      models.DiscountRule  true  "Discount rule information"
+// @Success      200   {object}  models.DiscountRule
+// @Failure      400   {object}  ErrorResponse
+// @Failure      404   {object}  ErrorResponse
+// @Failure      409   {object}  ErrorResponse
+// @Failure      500   {object}  ErrorResponse
+// @Router       /discounts/{id} [put]
+func (h *DiscountHandler) UpdateDiscountRule(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid discount rule ID"})
+		return
+	}
+
+	var rule models.DiscountRule
+	if err := c.ShouldBindJSON(&rule); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid discount rule data"})
+		return
+	}
+
+	// Ensure the ID in the path matches the discount rule
+	rule.ID = uint(id)
+
+	if err := h.service.UpdateDiscountRule(&rule); err != nil {
+		c.JSON(errorStatus(err, http.StatusBadRequest), ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, rule)
+}
+
+// DeleteDiscountRule godoc
+// @Summary      Delete discount rule
+// @Description  Delete a discount rule; set isActive to false instead to keep its history
+// @Tags         discounts
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "Discount rule ID"
+// @Success      204  {object}  nil
+// @Failure      404  {object}  ErrorResponse
+// @Failure      500  {object}  ErrorResponse
+// @Router       /discounts/{id} [delete]
+func (h *DiscountHandler) DeleteDiscountRule(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid discount rule ID"})
+		return
+	}
+
+	if err := h.service.DeleteDiscountRule(uint(id)); err != nil {
+		c.JSON(errorStatus(err, http.StatusInternalServerError), ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -1,7 +1,82 @@
 // internal/api/error.go
 package api
 
+import (
+	"errors"
+	"net/http"
+
+	"phone-accessories/internal/models"
+)
+
 // ErrorResponse represents an error response
 type ErrorResponse struct {
-	Error string `json:"error"`
-}
\ No newline at end of file
+	Error   string      `json:"error"`
+	Details interface{} `json:"details,omitempty"`
+}
+
+// errorStatus maps well-known domain errors to an HTTP status code, falling
+// back to the given status for anything else
+func errorStatus(err error, fallback int) int {
+	switch {
+	case errors.Is(err, models.ErrProductNotFound),
+		errors.Is(err, models.ErrCategoryNotFound),
+		errors.Is(err, models.ErrLocationNotFound),
+		errors.Is(err, models.ErrSupplierNotFound),
+		errors.Is(err, models.ErrPurchaseOrderNotFound),
+		errors.Is(err, models.ErrStockTakeNotFound),
+		errors.Is(err, models.ErrMediaNotFound),
+		errors.Is(err, models.ErrRelationNotFound),
+		errors.Is(err, models.ErrPriceListNotFound),
+		errors.Is(err, models.ErrProductPriceNotFound),
+		errors.Is(err, models.ErrExchangeRateNotFound),
+		errors.Is(err, models.ErrPromotionNotFound),
+		errors.Is(err, models.ErrDiscountRuleNotFound),
+		errors.Is(err, models.ErrBrandNotFound),
+		errors.Is(err, models.ErrDeviceNotFound),
+		errors.Is(err, models.ErrAttributeNotFound),
+		errors.Is(err, models.ErrReservationNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, models.ErrInsufficientStock),
+		errors.Is(err, models.ErrProductHasVariants),
+		errors.Is(err, models.ErrProductIsBundle),
+		errors.Is(err, models.ErrProductInBundle),
+		errors.Is(err, models.ErrVersionConflict),
+		errors.Is(err, models.ErrCategoryCycle),
+		errors.Is(err, models.ErrRelationExists),
+		errors.Is(err, models.ErrExchangeRateExists),
+		errors.Is(err, models.ErrCouponCodeExists),
+		errors.Is(err, models.ErrDiscountExhausted),
+		errors.Is(err, models.ErrDiscountAlreadyRedeemed),
+		errors.Is(err, models.ErrBrandInUse),
+		errors.Is(err, models.ErrPurchaseOrderStatus),
+		errors.Is(err, models.ErrStockTakeClosed),
+		errors.Is(err, models.ErrStockTakeChanged),
+		errors.Is(err, models.ErrReservationNotHeld),
+		errors.Is(err, models.ErrReservationExpired),
+		errors.Is(err, models.ErrLocationInactive):
+		return http.StatusConflict
+	case errors.Is(err, models.ErrInvalidPriceQuery):
+		return http.StatusBadRequest
+	case errors.Is(err, models.ErrInvalidAttributes),
+		errors.Is(err, models.ErrCouponInvalid),
+		errors.Is(err, models.ErrCouponNotApplicable):
+		return http.StatusUnprocessableEntity
+	case errors.Is(err, models.ErrMediaTooLarge):
+		return http.StatusRequestEntityTooLarge
+	case errors.Is(err, models.ErrUnsupportedMediaType):
+		return http.StatusUnsupportedMediaType
+	default:
+		return fallback
+	}
+}
+
+// errorResponse builds the body for an error response, listing the invalid
+// fields when the error carries them
+func errorResponse(err error) ErrorResponse {
+	response := ErrorResponse{Error: err.Error()}
+	var attributeErr *models.AttributeValidationError
+	if errors.As(err, &attributeErr) {
+		response.Details = attributeErr.Fields
+	}
+	return response
+}
@@ -0,0 +1,152 @@
+// internal/api/inventory_handler.go
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"phone-accessories/internal/models"
+	"phone-accessories/internal/service"
+)
+
+type InventoryHandler struct {
+	service service.InventoryService
+}
+
+func NewInventoryHandler(service service.InventoryService) *InventoryHandler {
+	return &InventoryHandler{service: service}
+}
+
+type StockTransferRequest struct {
+	ProductID      uint   `json:"productId" binding:"required"`
+	FromLocationID uint   `json:"fromLocationId" binding:"required"`
+	ToLocationID   uint   `json:"toLocationId" binding:"required"`
+	Quantity       int    `json:"quantity" binding:"required,min=1"`
+	Actor          string `json:"actor"`
+	Note           string `json:"note"`
+}
+
+type StockTransferResponse struct {
+	Transfer  models.StockTransfer   `json:"transfer"`
+	Movements []models.StockMovement `json:"movements"`
+}
+
+// TransferStock godoc
+// @Summary      Transfer stock between locations
+// @Description  Move a quantity of a product from one location to another in a single transaction
+// @Tags         inventory
+// @Accept       json
+// @Produce      json
+// @Param        transfer  body      StockTransferRequest  true  "Transfer information"
+// @Success      201       {object}  StockTransferResponse
+// @Failure      400       {object}  ErrorResponse
+// @Failure      404       {object}  ErrorResponse
+// @Failure      409       {object}  ErrorResponse
+// @Failure      500       {object}  ErrorResponse
+// @Router       /inventory/transfers [post]
+func (h *InventoryHandler) TransferStock(c *gin.Context) {
+	var req StockTransferRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid transfer data"})
+		return
+	}
+
+	transfer := models.StockTransfer{
+		ProductID:      req.ProductID,
+		FromLocationID: req.FromLocationID,
+		ToLocationID:   req.ToLocationID,
+		Quantity:       req.Quantity,
+		Actor:          req.Actor,
+		Note:           req.Note,
+	}
+	movements, err := h.service.TransferStock(&transfer)
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusBadRequest), ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, StockTransferResponse{Transfer: transfer, Movements: movements})
+}
+
+type StockAdjustmentLineRequest struct {
+	ProductID  uint   `json:"productId"`
+	SKU        string `json:"sku"`
+	LocationID uint   `json:"locationId"`
+	Delta      int    `json:"delta"`
+	Reason     string `json:"reason"`
+}
+
+type StockAdjustmentRequest struct {
+	ReferenceID string                       `json:"referenceId"`
+	Actor       string                       `json:"actor"`
+	Lines       []StockAdjustmentLineRequest `json:"lines" binding:"required,min=1"`
+}
+
+type StockAdjustmentResponse struct {
+	Lines []models.StockAdjustmentResult `json:"lines"`
+}
+
+// AdjustStock godoc
+// @Summary      Adjust stock in batch
+// @Description  Apply a list of stock adjustments in one transaction; if any line is invalid, none are applied and every failing line is reported. Reasons are as for a single stock update; transfer and count are rejected
+// @Tags         inventory
+// @Accept       json
+// @Produce      json
+// @Param        adjustment  body      StockAdjustmentRequest  true  "Adjustment lines"
+// @Success      200         {object}  StockAdjustmentResponse
+// @Failure      400         {object}  ErrorResponse
+// @Failure      422         {object}  ErrorResponse
+// @Failure      500         {object}  ErrorResponse
+// @Router       /inventory/adjustments [post]
+func (h *InventoryHandler) AdjustStock(c *gin.Context) {
+	var req StockAdjustmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid stock adjustment data"})
+		return
+	}
+
+	lines := make([]models.StockAdjustmentLine, 0, len(req.Lines))
+	for _, line := range req.Lines {
+		lines = append(lines, models.StockAdjustmentLine{
+			ProductID:  line.ProductID,
+			SKU:        line.SKU,
+			LocationID: line.LocationID,
+			Delta:      line.Delta,
+			Reason:     models.StockReason(line.Reason),
+		})
+	}
+
+	results, err := h.service.AdjustStock(lines, req.ReferenceID, req.Actor)
+	if err != nil {
+		var adjustmentErr *models.StockAdjustmentError
+		if errors.As(err, &adjustmentErr) {
+			c.JSON(http.StatusUnprocessableEntity, ErrorResponse{Error: err.Error(), Details: adjustmentErr.Lines})
+			return
+		}
+		c.JSON(errorStatus(err, http.StatusBadRequest), ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, StockAdjustmentResponse{Lines: results})
+}
+
+// ListLowStock godoc
+// @Summary      List low-stock products
+// @Description  Get the products whose stock level is under their reorder point, with suggested reorder quantities
+// @Tags         inventory
+// @Accept       json
+// @Produce      json
+// @Success      200  {array}   models.LowStockItem
+// @Failure      500  {object}  ErrorResponse
+// @Router       /inventory/low-stock [get]
+func (h *InventoryHandler) ListLowStock(c *gin.Context) {
+	items, err := h.service.ListLowStock()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, items)
+}
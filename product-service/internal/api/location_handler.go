@@ -0,0 +1,155 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"phone-accessories/internal/models"
+	"phone-accessories/internal/service"
+)
+
+type LocationHandler struct {
+	service service.LocationService
+}
+
+func NewLocationHandler(service service.LocationService) *LocationHandler {
+	return &LocationHandler{service: service}
+}
+
+// ListLocations godoc
+// @Summary      List locations
+// @Description  Get all stock locations
+// @Tags         locations
+// @Accept       json
+// @Produce      json
+// @Success      200  {array}   models.Location
+// @Failure      500  {object}  ErrorResponse
+// @Router       /locations [get]
+func (h *LocationHandler) ListLocations(c *gin.Context) {
+	locations, err := h.service.ListLocations()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, locations)
+}
+
+// GetLocation godoc
+// @Summary      Get location by ID
+// @Description  Get detailed information about a location
+// @Tags         locations
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "Location ID"
+// @Success      200  {object}  models.Location
+// @Failure      404  {object}  ErrorResponse
+// @Failure      500  {object}  ErrorResponse
+// @Router       /locations/{id} [get]
+func (h *LocationHandler) GetLocation(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid location ID"})
+		return
+	}
+
+	location, err := h.service.GetLocationByID(uint(id))
+	if err != nil {
+		c.JSON(http.StatusNotFound, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, location)
+}
+
+// CreateLocation godoc
+// @Summary      Create location
+// @Description  Add a new stock location
+// @Tags         locations
+// @Accept       json
+// @Produce      json
+// @Param        location  body      models.Location  true  "Location information"
+// @Success      201       {object}  models.Location
+// @Failure      400       {object}  ErrorResponse
+// @Failure      500       {object}  ErrorResponse
+// @Router       /locations [post]
+func (h *LocationHandler) CreateLocation(c *gin.Context) {
+	var location models.Location
+	if err := c.ShouldBindJSON(&location); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid location data"})
+		return
+	}
+
+	if err := h.service.CreateLocation(&location); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, location)
+}
+
+// UpdateLocation godoc
+// @Summary      Update location
+// @Description  Update an existing stock location
+// @Tags         locations
+// @Accept       json
+// @Produce      json
+// @Param        id        path      int              true  "Location ID"
+// @Param        location  body      models.Location  true  "Location information"
+// @Success      200       {object}  models.Location
+// @Failure      400       {object}  ErrorResponse
+// @Failure      404       {object}  ErrorResponse
+// @Failure      500       {object}  ErrorResponse
+// @Router       /locations/{id} [put]
+func (h *LocationHandler) UpdateLocation(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid location ID"})
+		return
+	}
+
+	var location models.Location
+	if err := c.ShouldBindJSON(&location); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid location data"})
+		return
+	}
+
+	// Ensure the ID in the path matches the location
+	location.ID = uint(id)
+
+	if err := h.service.UpdateLocation(&location); err != nil {
+		c.JSON(errorStatus(err, http.StatusBadRequest), ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, location)
+}
+
+// DeleteLocation godoc
+// @Summary      Delete location
+// @Description  Delete an existing stock location
+// @Tags         locations
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "Location ID"
+// @Success      204  {object}  nil
+// @Failure      400  {object}  ErrorResponse
+// @Failure      404  {object}  ErrorResponse
+// @Failure      500  {object}  ErrorResponse
+// @Router       /locations/{id} [delete]
+func (h *LocationHandler) DeleteLocation(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid location ID"})
+		return
+	}
+
+	if err := h.service.DeleteLocation(uint(id)); err != nil {
+		c.JSON(errorStatus(err, http.StatusBadRequest), ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,197 @@
+// internal/api/media_handler.go
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"phone-accessories/internal/models"
+	"phone-accessories/internal/service"
+)
+
+// multipartOverhead leaves room for the form fields and boundaries around the uploaded file
+const multipartOverhead = 1 << 20
+
+type MediaHandler struct {
+	service service.MediaService
+}
+
+func NewMediaHandler(service service.MediaService) *MediaHandler {
+	return &MediaHandler{service: service}
+}
+
+// ListMedia godoc
+// @Summary      List product media
+// @Description  Get the images of a product's gallery in display order
+// @Tags         media
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "Product ID"
+// @Success      200  {array}   models.ProductMedia
+// @Failure      404  {object}  ErrorResponse
+// @Failure      500  {object}  ErrorResponse
+// @Router       /products/{id}/media [get]
+func (h *MediaHandler) ListMedia(c *gin.Context) {
+	productID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid product ID"})
+		return
+	}
+
+	media, err := h.service.ListMedia(uint(productID))
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusInternalServerError), ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, media)
+}
+
+// UploadMedia godoc
+// @Summary      Upload product media
+// @Description  Upload a JPEG, PNG, GIF or WebP image to the end of a product's gallery
+// @Tags         media
+// @Accept       multipart/form-data
+// @Produce      json
+// @Param        id         path      int     true   "Product ID"
+// @Param        file       formData  file    true   "Image file"
+// @Param        altText    formData  string  false  "Alternative text"
+// @Param        variantId  formData  int     false  "Variant the image shows"
+// @Param        isPrimary  formData  bool    false  "Make this the product's primary image"
+// @Success      201        {object}  models.ProductMedia
+// @Failure      400        {object}  ErrorResponse
+// @Failure      404        {object}  ErrorResponse
+// @Failure      413        {object}  ErrorResponse
+// @Failure      415        {object}  ErrorResponse
+// @Failure      500        {object}  ErrorResponse
+// @Router       /products/{id}/media [post]
+func (h *MediaHandler) UploadMedia(c *gin.Context) {
+	productID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid product ID"})
+		return
+	}
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, h.service.MaxUploadSize()+multipartOverhead)
+	header, err := c.FormFile("file")
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			c.JSON(http.StatusRequestEntityTooLarge, ErrorResponse{Error: models.ErrMediaTooLarge.Error()})
+			return
+		}
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "An image file is required"})
+		return
+	}
+
+	media := models.ProductMedia{
+		ProductID: uint(productID),
+		AltText:   c.PostForm("altText"),
+	}
+	if variantID := c.PostForm("variantId"); variantID != "" {
+		id, err := strconv.ParseUint(variantID, 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid variant ID"})
+			return
+		}
+		variant := uint(id)
+		media.VariantID = &variant
+	}
+	if isPrimary := c.PostForm("isPrimary"); isPrimary != "" {
+		media.IsPrimary, err = strconv.ParseBool(isPrimary)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid isPrimary value"})
+			return
+		}
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+		return
+	}
+	defer file.Close()
+
+	if err := h.service.UploadMedia(&media, file, header.Size); err != nil {
+		c.JSON(errorStatus(err, http.StatusBadRequest), ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, media)
+}
+
+// UpdateMedia godoc
+// @Summary      Update product media
+// @Description  Change the alt text, position, primary flag or variant of a gallery image; a variantId of 0 detaches it from its variant
+// @Tags         media
+// @Accept       json
+// @Produce      json
+// @Param        id       path      int                        true  "Product ID"
+// @Param        mediaId  path      int                        true  "Media ID"
+// @Param        media    body      models.ProductMediaUpdate  true  "Media details"
+// @Success      200      {object}  models.ProductMedia
+// @Failure      400      {object}  ErrorResponse
+// @Failure      404      {object}  ErrorResponse
+// @Failure      500      {object}  ErrorResponse
+// @Router       /products/{id}/media/{mediaId} [patch]
+func (h *MediaHandler) UpdateMedia(c *gin.Context) {
+	productID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid product ID"})
+		return
+	}
+	mediaID, err := strconv.ParseUint(c.Param("mediaId"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid media ID"})
+		return
+	}
+
+	var update models.ProductMediaUpdate
+	if err := c.ShouldBindJSON(&update); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid media data"})
+		return
+	}
+
+	media, err := h.service.UpdateMedia(uint(productID), uint(mediaID), update)
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusBadRequest), ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, media)
+}
+
+// DeleteMedia godoc
+// @Summary      Delete product media
+// @Description  Remove an image from a product's gallery and from storage
+// @Tags         media
+// @Accept       json
+// @Produce      json
+// @Param        id       path      int  true  "Product ID"
+// @Param        mediaId  path      int  true  "Media ID"
+// @Success      204      {object}  nil
+// @Failure      404      {object}  ErrorResponse
+// @Failure      500      {object}  ErrorResponse
+// @Router       /products/{id}/media/{mediaId} [delete]
+func (h *MediaHandler) DeleteMedia(c *gin.Context) {
+	productID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid product ID"})
+		return
+	}
+	mediaID, err := strconv.ParseUint(c.Param("mediaId"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid media ID"})
+		return
+	}
+
+	if err := h.service.DeleteMedia(uint(productID), uint(mediaID)); err != nil {
+		c.JSON(errorStatus(err, http.StatusInternalServerError), ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,350 @@
+// internal/api/price_list_handler.go
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"phone-accessories/internal/models"
+	"phone-accessories/internal/service"
+)
+
+type PriceListHandler struct {
+	service service.PriceListService
+}
+
+func NewPriceListHandler(service service.PriceListService) *PriceListHandler {
+	return &PriceListHandler{service: service}
+}
+
+// ProductPriceRequest sets the price of a product in a price list
+type ProductPriceRequest struct {
+	// Price is a decimal amount in the price list's currency
+	Price string `json:"price" binding:"required" example:"89.900"`
+}
+
+// ListPriceLists godoc
+// @Summary      List price lists
+// @Description  Get all price lists, optionally of one currency or only active ones
+// @Tags         price-lists
+// @Accept       json
+// @Produce      json
+// @Param        currency  query     string  false  "Filter by currency"
+// @Param        active    query     bool    false  "Filter by active flag"
+// @Success      200       {array}   models.PriceList
+// @Failure      400       {object}  ErrorResponse
+// @Failure      500       {object}  ErrorResponse
+// @Router       /price-lists [get]
+func (h *PriceListHandler) ListPriceLists(c *gin.Context) {
+	var filter models.PriceListFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid filter parameters"})
+		return
+	}
+
+	lists, err := h.service.ListPriceLists(filter)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, lists)
+}
+
+// GetPriceList godoc
+// @Summary      Get price list by ID
+// @Description  Get detailed information about a price list
+// @Tags         price-lists
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "Price list ID"
+// @Success      200  {object}  models.PriceList
+// @Failure      404  {object}  ErrorResponse
+// @Failure      500  {object}  ErrorResponse
+// @Router       /price-lists/{id} [get]
+func (h *PriceListHandler) GetPriceList(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid price list ID"})
+		return
+	}
+
+	list, err := h.service.GetPriceListByID(uint(id))
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusInternalServerError), ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, list)
+}
+
+// CreatePriceList godoc
+// @Summary      Create price list
+// @Description  Add a new price list in a currency. Marking it as default makes it the list used for that currency when no list is named.
+// @Tags         price-lists
+// @Accept       json
+// @Produce      json
+// @Param        priceList  body      models.PriceList  true  "Price list information"
+// @Success      201        {object}  models.PriceList
+// @Failure      400        {object}  ErrorResponse
+// @Failure      500        {object}  ErrorResponse
+// @Router       /price-lists [post]
+func (h *PriceListHandler) CreatePriceList(c *gin.Context) {
+	var list models.PriceList
+	if err := c.ShouldBindJSON(&list); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid price list data"})
+		return
+	}
+
+	if err := h.service.CreatePriceList(&list); err != nil {
+		c.JSON(errorStatus(err, http.StatusBadRequest), ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, list)
+}
+
+// UpdatePriceList godoc
+// @Summary      Update price list
+// @Description  Update an existing price list. Its currency can only change while it has no prices.
+// @Tags         price-lists
+// @Accept       json
+// @Produce      json
+// @Param        id         path      int               true  "Price list ID"
+// @Param        priceList  body      models.PriceList  true  "Price list information"
+// @Success      200        {object}  models.PriceList
+// @Failure      400        {object}  ErrorResponse
+// @Failure      404        {object}  ErrorResponse
+// @Failure      500        {object}  ErrorResponse
+// @Router       /price-lists/{id} [put]
+func (h *PriceListHandler) UpdatePriceList(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid price list ID"})
+		return
+	}
+
+	var list models.PriceList
+	if err := c.ShouldBindJSON(&list); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid price list data"})
+		return
+	}
+
+	// Ensure the ID in the path matches the price list
+	list.ID = uint(id)
+
+	if err := h.service.UpdatePriceList(&list); err != nil {
+		c.JSON(errorStatus(err, http.StatusBadRequest), ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, list)
+}
+
+// DeletePriceList godoc
+// @Summary      Delete price list
+// @Description  Delete a price list and the prices in it
+// @Tags         price-lists
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "Price list ID"
+// @Success      204  {object}  nil
+// @Failure      404  {object}  ErrorResponse
+// @Failure      500  {object}  ErrorResponse
+// @Router       /price-lists/{id} [delete]
+func (h *PriceListHandler) DeletePriceList(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid price list ID"})
+		return
+	}
+
+	if err := h.service.DeletePriceList(uint(id)); err != nil {
+		c.JSON(errorStatus(err, http.StatusInternalServerError), ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ListProductPrices godoc
+// @Summary      List prices in a price list
+// @Description  Get the product and variant prices set in a price list
+// @Tags         price-lists
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "Price list ID"
+// @Success      200  {array}   models.ProductPrice
+// @Failure      404  {object}  ErrorResponse
+// @Failure      500  {object}  ErrorResponse
+// @Router       /price-lists/{id}/prices [get]
+func (h *PriceListHandler) ListProductPrices(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid price list ID"})
+		return
+	}
+
+	prices, err := h.service.ListProductPrices(uint(id))
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusInternalServerError), ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, prices)
+}
+
+// SetProductPrice godoc
+// @Summary      Set a price in a price list
+// @Description  Set the price of a product or variant in a price list, replacing any price it had there
+// @Tags         price-lists
+// @Accept       json
+// @Produce      json
+// @Param        id         path      int                  true  "Price list ID"
+// @Param        productId  path      int                  true  "Product or variant ID"
+// @Param        price      body      ProductPriceRequest  true  "Price in the list's currency"
+// @Success      200        {object}  models.ProductPrice
+// @Failure      400        {object}  ErrorResponse
+// @Failure      404        {object}  ErrorResponse
+// @Failure      500        {object}  ErrorResponse
+// @Router       /price-lists/{id}/prices/{productId} [put]
+func (h *PriceListHandler) SetProductPrice(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid price list ID"})
+		return
+	}
+	productID, err := strconv.ParseUint(c.Param("productId"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid product ID"})
+		return
+	}
+
+	var request ProductPriceRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid price data"})
+		return
+	}
+
+	price, err := h.service.SetProductPrice(uint(id), uint(productID), request.Price)
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusBadRequest), ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, price)
+}
+
+// DeleteProductPrice godoc
+// @Summary      Remove a price from a price list
+// @Description  Remove the price of a product or variant from a price list, so that its converted base price applies again
+// @Tags         price-lists
+// @Accept       json
+// @Produce      json
+// @Param        id         path      int  true  "Price list ID"
+// @Param        productId  path      int  true  "Product or variant ID"
+// @Success      204        {object}  nil
+// @Failure      404        {object}  ErrorResponse
+// @Failure      500        {object}  ErrorResponse
+// @Router       /price-lists/{id}/prices/{productId} [delete]
+func (h *PriceListHandler) DeleteProductPrice(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid price list ID"})
+		return
+	}
+	productID, err := strconv.ParseUint(c.Param("productId"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid product ID"})
+		return
+	}
+
+	if err := h.service.DeleteProductPrice(uint(id), uint(productID)); err != nil {
+		c.JSON(errorStatus(err, http.StatusInternalServerError), ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ListExchangeRates godoc
+// @Summary      List exchange rates
+// @Description  Get the exchange rates, newest first for each currency pair
+// @Tags         exchange-rates
+// @Accept       json
+// @Produce      json
+// @Param        currency  query     string  false  "Only rates from or into this currency"
+// @Success      200       {array}   models.ExchangeRate
+// @Failure      400       {object}  ErrorResponse
+// @Failure      500       {object}  ErrorResponse
+// @Router       /exchange-rates [get]
+func (h *PriceListHandler) ListExchangeRates(c *gin.Context) {
+	var filter models.ExchangeRateFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid filter parameters"})
+		return
+	}
+
+	rates, err := h.service.ListExchangeRates(filter)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, rates)
+}
+
+// CreateExchangeRate godoc
+// @Summary      Create exchange rate
+// @Description  Record the rate one unit of the base currency converts into the quote currency at, from its effective date on (today when omitted)
+// @Tags         exchange-rates
+// @Accept       json
+// @Produce      json
+// @Param        rate  body      models.ExchangeRate  true  "Exchange rate"
+// @Success      201   {object}  models.ExchangeRate
+// @Failure      400   {object}  ErrorResponse
+// @Failure      409   {object}  ErrorResponse
+// @Failure      500   {object}  ErrorResponse
+// @Router       /exchange-rates [post]
+func (h *PriceListHandler) CreateExchangeRate(c *gin.Context) {
+	var rate models.ExchangeRate
+	if err := c.ShouldBindJSON(&rate); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid exchange rate data"})
+		return
+	}
+
+	if err := h.service.CreateExchangeRate(&rate); err != nil {
+		c.JSON(errorStatus(err, http.StatusBadRequest), ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, rate)
+}
+
+// DeleteExchangeRate godoc
+// @Summary      Delete exchange rate
+// @Description  Delete an exchange rate, so that the previous rate of the pair applies again
+// @Tags         exchange-rates
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "Exchange rate ID"
+// @Success      204  {object}  nil
+// @Failure      404  {object}  ErrorResponse
+// @Failure      500  {object}  ErrorResponse
+// @Router       /exchange-rates/{id} [delete]
+func (h *PriceListHandler) DeleteExchangeRate(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid exchange rate ID"})
+		return
+	}
+
+	if err := h.service.DeleteExchangeRate(uint(id)); err != nil {
+		c.JSON(errorStatus(err, http.StatusInternalServerError), ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
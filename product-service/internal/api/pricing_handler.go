@@ -0,0 +1,186 @@
+// internal/api/pricing_handler.go
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"phone-accessories/internal/models"
+	"phone-accessories/internal/service"
+)
+
+type PricingHandler struct {
+	service service.PricingService
+}
+
+func NewPricingHandler(service service.PricingService) *PricingHandler {
+	return &PricingHandler{service: service}
+}
+
+// PriceTiersRequest replaces the quantity tiers of a product; an empty list
+// removes them
+type PriceTiersRequest struct {
+	Tiers []models.PriceTier `json:"tiers"`
+}
+
+// QuoteItemRequest is one line of a quote request
+type QuoteItemRequest struct {
+	ProductID uint `json:"productId" binding:"required"`
+	Quantity  int  `json:"quantity" binding:"required,min=1"`
+}
+
+// QuoteRequest asks for the prices of a cart, optionally with a coupon code,
+// in a price list or currency like product reads
+type QuoteRequest struct {
+	Items      []QuoteItemRequest `json:"items" binding:"required,min=1,dive"`
+	CouponCode string             `json:"couponCode"`
+	PriceList  *uint              `json:"priceList"`
+	Currency   string             `json:"currency"`
+}
+
+// RedemptionRequest prices the cart of an order and uses up its discounts
+type RedemptionRequest struct {
+	Reference  string             `json:"reference" binding:"required"`
+	Items      []QuoteItemRequest `json:"items" binding:"required,min=1,dive"`
+	CouponCode string             `json:"couponCode"`
+	PriceList  *uint              `json:"priceList"`
+	Currency   string             `json:"currency"`
+}
+
+// ListPriceTiers godoc
+// @Summary      List a product's quantity tiers
+// @Description  Get the quantity break tiers of a product, lowest quantity first
+// @Tags         pricing
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "Product ID"
+// @Success      200  {array}   models.PriceTier
+// @Failure      404  {object}  ErrorResponse
+// @Failure      500  {object}  ErrorResponse
+// @Router       /products/{id}/price-tiers [get]
+func (h *PricingHandler) ListPriceTiers(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid product ID"})
+		return
+	}
+
+	tiers, err := h.service.ListPriceTiers(uint(id))
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusInternalServerError), ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, tiers)
+}
+
+// SetPriceTiers godoc
+// @Summary      Set a product's quantity tiers
+// @Description  Replace the quantity break tiers of a product, such as 1-9, 10-49 and 50 and up. Tiers must not overlap and their unit prices must be in the product's currency; leave maxQuantity out for the last tier to make it open-ended.
+// @Tags         pricing
+// @Accept       json
+// @Produce      json
+// @Param        id     path      int                true  "Product ID"
+// @Param        tiers  body      PriceTiersRequest  true  "Quantity tiers"
+// @Success      200    {array}   models.PriceTier
+// @Failure      400    {object}  ErrorResponse
+// @Failure      404    {object}  ErrorResponse
+// @Failure      500    {object}  ErrorResponse
+// @Router       /products/{id}/price-tiers [put]
+func (h *PricingHandler) SetPriceTiers(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid product ID"})
+		return
+	}
+
+	var req PriceTiersRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid price tier data"})
+		return
+	}
+	if req.Tiers == nil {
+		req.Tiers = []models.PriceTier{}
+	}
+
+	tiers, err := h.service.SetPriceTiers(uint(id), req.Tiers)
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusBadRequest), ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, tiers)
+}
+
+// Quote godoc
+// @Summary      Quote a cart
+// @Description  Price a cart from the catalogue, so that carts never rely on prices sent by the client. Each line gets its regular, sale or quantity tier price, whichever is lowest; the running discount rules and the coupon code's rule then take money off, and each line carries the tax of its category. With priceList or currency, every price is resolved there like product reads resolve it; otherwise all products must be priced in the same currency.
+// @Tags         pricing
+// @Accept       json
+// @Produce      json
+// @Param        quote  body      QuoteRequest  true  "Products, quantities and coupon code"
+// @Success      200    {object}  models.Quote
+// @Failure      400    {object}  ErrorResponse
+// @Failure      404    {object}  ErrorResponse
+// @Failure      409    {object}  ErrorResponse
+// @Failure      422    {object}  ErrorResponse
+// @Failure      500    {object}  ErrorResponse
+// @Router       /pricing/quote [post]
+func (h *PricingHandler) Quote(c *gin.Context) {
+	var req QuoteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid quote data"})
+		return
+	}
+
+	quote, err := h.service.Quote(quoteItems(req.Items), req.CouponCode,
+		models.PriceQuery{PriceListID: req.PriceList, Currency: req.Currency})
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusBadRequest), ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, quote)
+}
+
+// Redeem godoc
+// @Summary      Redeem a cart's discounts
+// @Description  Quote the cart of an order and record that the order used the discounts applied to it, counting towards their usage limits. An order reference redeems its discounts only once; redeeming it again returns the quote it was charged.
+// @Tags         pricing
+// @Accept       json
+// @Produce      json
+// @Param        redemption  body      RedemptionRequest  true  "Order reference, products, quantities and coupon code"
+// @Success      201         {object}  models.Quote
+// @Failure      400         {object}  ErrorResponse
+// @Failure      404         {object}  ErrorResponse
+// @Failure      409         {object}  ErrorResponse
+// @Failure      422         {object}  ErrorResponse
+// @Failure      500         {object}  ErrorResponse
+// @Router       /pricing/redemptions [post]
+func (h *PricingHandler) Redeem(c *gin.Context) {
+	var req RedemptionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid redemption data"})
+		return
+	}
+
+	quote, err := h.service.Redeem(req.Reference, quoteItems(req.Items), req.CouponCode,
+		models.PriceQuery{PriceListID: req.PriceList, Currency: req.Currency})
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusBadRequest), ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, quote)
+}
+
+// quoteItems turns the items of a request into quote items
+func quoteItems(requested []QuoteItemRequest) []models.QuoteItem {
+	items := make([]models.QuoteItem, 0, len(requested))
+	for _, item := range requested {
+		items = append(items, models.QuoteItem{ProductID: item.ProductID, Quantity: item.Quantity})
+	}
+	return items
+}
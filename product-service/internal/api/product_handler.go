@@ -2,8 +2,13 @@
 package api
 
 import (
+	"fmt"
 	"net/http"
+	"net/url"
+	"path"
+	"sort"
 	"strconv"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 
@@ -26,10 +31,19 @@ func NewProductHandler(service service.ProductService) *ProductHandler {
 // @Accept       json
 // @Produce      json
 // @Param        categoryId   query     int     false  "Filter by category ID"
-// @Param        minPrice     query     number  false  "Filter by minimum price"
-// @Param        maxPrice     query     number  false  "Filter by maximum price"
+// @Param        includeDescendants  query  bool    false  "Include products of the category's subcategories"
+// @Param        priceList    query     int     false  "Resolve prices in this price list"
+// @Param        currency     query     string  false  "Resolve prices in this currency, through its default price list and exchange rates"
+// @Param        minPrice     query     string  false  "Filter by minimum price, as a decimal amount in the resolved currency (EUR base prices when none is asked for)"
+// @Param        maxPrice     query     string  false  "Filter by maximum price, as a decimal amount in the resolved currency (EUR base prices when none is asked for)"
 // @Param        q            query     string  false  "Search query"
 // @Param        inStock      query     bool    false  "Filter by stock availability"
+// @Param        locationId   query     int     false  "Restrict the inStock filter to stock held at this location"
+// @Param        deviceId     query     int     false  "Filter by compatible device model ID"
+// @Param        brandId      query     int     false  "Filter by brand ID"
+// @Param        onSale       query     bool    false  "Filter by whether a promotion currently puts the product on sale"
+// @Param        option       query     string  false  "Match variant option values, as option[name]=value"
+// @Param        attr         query     string  false  "Filter by attribute, as attr.key=value1,value2 or attr.key[gte]=20 (also gt, lt, lte)"
 // @Param        sortBy       query     string  false  "Sort field"
 // @Param        sortDir      query     string  false  "Sort direction (asc or desc)"
 // @Param        page         query     int     false  "Page number"
@@ -44,6 +58,18 @@ func (h *ProductHandler) ListProducts(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid filter parameters"})
 		return
 	}
+	attributes, err := parseAttributeFilters(c.Request.URL.Query())
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+	filter.Attributes = attributes
+	options, err := parseOptionFilters(c.Request.URL.Query())
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+	filter.Options = options
 
 	// Set default values if not provided
 	if filter.Page <= 0 {
@@ -55,23 +81,106 @@ func (h *ProductHandler) ListProducts(c *gin.Context) {
 
 	result, err := h.service.ListProducts(filter)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+		c.JSON(errorStatus(err, http.StatusInternalServerError), ErrorResponse{Error: err.Error()})
 		return
 	}
 
 	c.JSON(http.StatusOK, result)
 }
 
+// parseAttributeFilters reads attribute filters from the query string:
+// attr.color=Noir matches a value, attr.color=Noir,Bleu (or the parameter
+// repeated) any of several values, and attr.power_w[gte]=20 a numeric range
+// with gt, gte, lt or lte
+func parseAttributeFilters(query url.Values) ([]models.AttributeFilter, error) {
+	var names []string
+	for name := range query {
+		if strings.HasPrefix(name, "attr.") {
+			names = append(names, name)
+		}
+	}
+	// Keep the generated query stable regardless of map order
+	sort.Strings(names)
+
+	var filters []models.AttributeFilter
+	for _, name := range names {
+		key := strings.TrimPrefix(name, "attr.")
+		operator := models.AttributeOperatorEq
+		if open := strings.IndexByte(key, '['); open >= 0 && strings.HasSuffix(key, "]") {
+			operator = models.AttributeOperator(key[open+1 : len(key)-1])
+			key = key[:open]
+		}
+		if !models.AttributeKeyPattern.MatchString(key) {
+			return nil, fmt.Errorf("invalid attribute filter %q", name)
+		}
+
+		filter := models.AttributeFilter{Key: key, Operator: operator}
+		switch operator {
+		case models.AttributeOperatorEq:
+			for _, value := range query[name] {
+				for _, part := range strings.Split(value, ",") {
+					if part = strings.TrimSpace(part); part != "" {
+						filter.Values = append(filter.Values, part)
+					}
+				}
+			}
+			if len(filter.Values) == 0 {
+				continue
+			}
+		case models.AttributeOperatorGt, models.AttributeOperatorGte,
+			models.AttributeOperatorLt, models.AttributeOperatorLte:
+			number, err := strconv.ParseFloat(query.Get(name), 64)
+			if err != nil {
+				return nil, fmt.Errorf("attribute filter %q needs a number", name)
+			}
+			filter.Number = number
+		default:
+			return nil, fmt.Errorf("unknown operator %q in attribute filter %q", operator, name)
+		}
+		filters = append(filters, filter)
+	}
+	return filters, nil
+}
+
+// parseOptionFilters reads variant option filters from the query string:
+// option[colour]=Noir matches products with a variant whose colour is Noir
+func parseOptionFilters(query url.Values) (map[string]string, error) {
+	var options map[string]string
+	for name, values := range query {
+		if !strings.HasPrefix(name, "option[") {
+			continue
+		}
+		option := strings.TrimPrefix(name, "option[")
+		if !strings.HasSuffix(option, "]") || len(option) == 1 {
+			return nil, fmt.Errorf("invalid option filter %q", name)
+		}
+		if len(values) > 1 {
+			return nil, fmt.Errorf("option filter %q takes a single value", name)
+		}
+		if values[0] == "" {
+			continue
+		}
+		if options == nil {
+			options = make(map[string]string)
+		}
+		options[strings.TrimSuffix(option, "]")] = values[0]
+	}
+	return options, nil
+}
+
 // GetProduct godoc
 // @Summary      Get product by ID
 // @Description  Get detailed information about a product
 // @Tags         products
 // @Accept       json
 // @Produce      json
-// @Param        id   path      int  true  "Product ID"
-// @Success      200  {object}  models.Product
-// @Failure      404  {object}  ErrorResponse
-// @Failure      500  {object}  ErrorResponse
+// @Param        id         path      int     true   "Product ID"
+// @Param        priceList  query     int     false  "Resolve the price in this price list"
+// @Param        currency   query     string  false  "Resolve the price in this currency, through its default price list and exchange rates"
+// @Success      200        {object}  models.Product
+// @Failure      400        {object}  ErrorResponse
+// @Failure      404        {object}  ErrorResponse
+// @Failure      500        {object}  ErrorResponse
 // @Router       /products/{id} [get]
 func (h *ProductHandler) GetProduct(c *gin.Context) {
 	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
@@ -79,25 +188,99 @@ func (h *ProductHandler) GetProduct(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid product ID"})
 		return
 	}
+	var query models.PriceQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid price parameters"})
+		return
+	}
 
 	product, err := h.service.GetProductByID(uint(id))
 	if err != nil {
 		c.JSON(http.StatusNotFound, ErrorResponse{Error: err.Error()})
 		return
 	}
+	if query.IsSet() {
+		if err := h.service.PriceProduct(product, query); err != nil {
+			c.JSON(errorStatus(err, http.StatusInternalServerError), ErrorResponse{Error: err.Error()})
+			return
+		}
+	}
 
 	c.JSON(http.StatusOK, product)
 }
 
+// SlugRedirectResponse points a client at the current slug of a renamed record
+type SlugRedirectResponse struct {
+	Slug     string `json:"slug"`
+	Location string `json:"location"`
+}
+
+// GetProductBySlug godoc
+// @Summary      Get product by slug
+// @Description  Get detailed information about a product from its URL slug. An old slug of a renamed product answers 301 with the current slug in the Location header.
+// @Tags         products
+// @Accept       json
+// @Produce      json
+// @Param        slug       path      string  true   "Product slug"
+// @Param        priceList  query     int     false  "Resolve the price in this price list"
+// @Param        currency   query     string  false  "Resolve the price in this currency, through its default price list and exchange rates"
+// @Success      200        {object}  models.Product
+// @Success      301        {object}  SlugRedirectResponse
+// @Failure      400        {object}  ErrorResponse
+// @Failure      404        {object}  ErrorResponse
+// @Failure      500        {object}  ErrorResponse
+// @Router       /products/slug/{slug} [get]
+func (h *ProductHandler) GetProductBySlug(c *gin.Context) {
+	var query models.PriceQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid price parameters"})
+		return
+	}
+
+	product, err := h.service.GetProductBySlug(c.Param("slug"))
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusInternalServerError), ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	if product.Slug != c.Param("slug") {
+		redirectToSlug(c, product.Slug)
+		return
+	}
+	if query.IsSet() {
+		if err := h.service.PriceProduct(product, query); err != nil {
+			c.JSON(errorStatus(err, http.StatusInternalServerError), ErrorResponse{Error: err.Error()})
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, product)
+}
+
+// redirectToSlug answers a lookup by an old slug with a permanent redirect to
+// the same endpoint for the current one
+func redirectToSlug(c *gin.Context, slug string) {
+	location := path.Join(path.Dir(c.Request.URL.Path), url.PathEscape(slug))
+	// Keep the query, such as the price list asked for, across the redirect
+	if c.Request.URL.RawQuery != "" {
+		location += "?" + c.Request.URL.RawQuery
+	}
+	c.Header("Location", location)
+	c.JSON(http.StatusMovedPermanently, SlugRedirectResponse{Slug: slug, Location: location})
+}
+
 // CreateProduct godoc
 // @Summary      Create product
-// @Description  Add a new product
+// @Description  Add a new product, or a bundle of other products when it lists components
 // @Tags         products
 // @Accept       json
 // @Produce      json
 // @Param        product  body      models.Product  true  "Product information"
 // @Success      201      {object}  models.Product
 // @Failure      400      {object}  ErrorResponse
+// @Failure      404      {object}  ErrorResponse
+// @Failure      409      {object}  ErrorResponse
+// @Failure      422      {object}  ErrorResponse
 // @Failure      500      {object}  ErrorResponse
 // @Router       /products [post]
 func (h *ProductHandler) CreateProduct(c *gin.Context) {
@@ -108,16 +291,51 @@ func (h *ProductHandler) CreateProduct(c *gin.Context) {
 	}
 
 	if err := h.service.CreateProduct(&product); err != nil {
-		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		c.JSON(errorStatus(err, http.StatusBadRequest), errorResponse(err))
 		return
 	}
 
 	c.JSON(http.StatusCreated, product)
 }
 
+// CreateVariant godoc
+// @Summary      Create product variant
+// @Description  Add a variant with its own SKU, price, stock and image to a parent product; empty fields are taken from the parent
+// @Tags         products
+// @Accept       json
+// @Produce      json
+// @Param        id       path      int             true  "Parent product ID"
+// @Param        variant  body      models.Product  true  "Variant information, including its variantOptions"
+// @Success      201      {object}  models.Product
+// @Failure      400      {object}  ErrorResponse
+// @Failure      404      {object}  ErrorResponse
+// @Failure      422      {object}  ErrorResponse
+// @Failure      500      {object}  ErrorResponse
+// @Router       /products/{id}/variants [post]
+func (h *ProductHandler) CreateVariant(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid product ID"})
+		return
+	}
+
+	var variant models.Product
+	if err := c.ShouldBindJSON(&variant); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid variant data"})
+		return
+	}
+
+	if err := h.service.CreateVariant(uint(id), &variant); err != nil {
+		c.JSON(errorStatus(err, http.StatusBadRequest), errorResponse(err))
+		return
+	}
+
+	c.JSON(http.StatusCreated, variant)
+}
+
 // UpdateProduct godoc
 // @Summary      Update product
-// @Description  Update an existing product
+// @Description  Update an existing product. stockLevel and reservedLevel are read-only here and ignored; change stock through PATCH /products/{id}/stock. Send the version the edit is based on to have it rejected with 409 when the product changed in the meantime; without a version the update is not checked.
 // @Tags         products
 // @Accept       json
 // @Produce      json
@@ -126,6 +344,8 @@ func (h *ProductHandler) CreateProduct(c *gin.Context) {
 // @Success      200      {object}  models.Product
 // @Failure      400      {object}  ErrorResponse
 // @Failure      404      {object}  ErrorResponse
+// @Failure      409      {object}  ErrorResponse
+// @Failure      422      {object}  ErrorResponse
 // @Failure      500      {object}  ErrorResponse
 // @Router       /products/{id} [put]
 func (h *ProductHandler) UpdateProduct(c *gin.Context) {
@@ -145,7 +365,7 @@ func (h *ProductHandler) UpdateProduct(c *gin.Context) {
 	product.ID = uint(id)
 
 	if err := h.service.UpdateProduct(&product); err != nil {
-		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		c.JSON(errorStatus(err, http.StatusBadRequest), errorResponse(err))
 		return
 	}
 
@@ -154,13 +374,14 @@ func (h *ProductHandler) UpdateProduct(c *gin.Context) {
 
 // DeleteProduct godoc
 // @Summary      Delete product
-// @Description  Delete an existing product
+// @Description  Delete an existing product that is not part of a bundle
 // @Tags         products
 // @Accept       json
 // @Produce      json
 // @Param        id   path      int  true  "Product ID"
 // @Success      204  {object}  nil
 // @Failure      400  {object}  ErrorResponse
+// @Failure      409  {object}  ErrorResponse
 // @Failure      500  {object}  ErrorResponse
 // @Router       /products/{id} [delete]
 func (h *ProductHandler) DeleteProduct(c *gin.Context) {
@@ -171,7 +392,7 @@ func (h *ProductHandler) DeleteProduct(c *gin.Context) {
 	}
 
 	if err := h.service.DeleteProduct(uint(id)); err != nil {
-		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+		c.JSON(errorStatus(err, http.StatusInternalServerError), ErrorResponse{Error: err.Error()})
 		return
 	}
 
@@ -179,12 +400,17 @@ func (h *ProductHandler) DeleteProduct(c *gin.Context) {
 }
 
 type StockUpdateRequest struct {
-	Quantity int `json:"quantity" binding:"required"`
+	Quantity    int          `json:"quantity" binding:"required"`
+	LocationID  uint         `json:"locationId"`
+	Reason      string       `json:"reason"`
+	ReferenceID string       `json:"referenceId"`
+	Actor       string       `json:"actor"`
+	UnitCost    *models.Cost `json:"unitCost" swaggertype:"number"`
 }
 
 // UpdateStock godoc
 // @Summary      Update product stock
-// @Description  Update product stock level
+// @Description  Apply a signed change to the product stock level at a location (the default location when omitted) and record it in the stock ledger. Selling or returning a bundle changes the stock of each of its components. The reason is one of sale, return, restock, adjustment (the default) or damage; transfer and count are rejected.
 // @Tags         products
 // @Accept       json
 // @Produce      json
@@ -193,6 +419,7 @@ type StockUpdateRequest struct {
 // @Success      200     {object}  models.Product
 // @Failure      400     {object}  ErrorResponse
 // @Failure      404     {object}  ErrorResponse
+// @Failure      409     {object}  ErrorResponse
 // @Failure      500     {object}  ErrorResponse
 // @Router       /products/{id}/stock [patch]
 func (h *ProductHandler) UpdateStock(c *gin.Context) {
@@ -208,8 +435,17 @@ func (h *ProductHandler) UpdateStock(c *gin.Context) {
 		return
 	}
 
-	if err := h.service.UpdateStock(uint(id), req.Quantity); err != nil {
-		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+	change := models.StockChange{
+		ProductID:   uint(id),
+		LocationID:  req.LocationID,
+		Delta:       req.Quantity,
+		Reason:      models.StockReason(req.Reason),
+		ReferenceID: req.ReferenceID,
+		Actor:       req.Actor,
+		UnitCost:    req.UnitCost,
+	}
+	if _, err := h.service.UpdateStock(change); err != nil {
+		c.JSON(errorStatus(err, http.StatusBadRequest), ErrorResponse{Error: err.Error()})
 		return
 	}
 
@@ -221,3 +457,43 @@ func (h *ProductHandler) UpdateStock(c *gin.Context) {
 
 	c.JSON(http.StatusOK, product)
 }
+
+// GetStockHistory godoc
+// @Summary      Get product stock history
+// @Description  Get the paginated stock movements of a product, newest first
+// @Tags         products
+// @Accept       json
+// @Produce      json
+// @Param        id          path      int     true   "Product ID"
+// @Param        from        query     string  false  "Only movements on or after this date (YYYY-MM-DD)"
+// @Param        to          query     string  false  "Only movements on or before this date (YYYY-MM-DD)"
+// @Param        reason      query     string  false  "Filter by reason code"
+// @Param        locationId  query     int     false  "Filter by location ID"
+// @Param        page        query     int     false  "Page number"
+// @Param        pageSize    query     int     false  "Items per page"
+// @Success      200         {object}  models.PaginatedResponse
+// @Failure      400         {object}  ErrorResponse
+// @Failure      404         {object}  ErrorResponse
+// @Failure      500         {object}  ErrorResponse
+// @Router       /products/{id}/stock/history [get]
+func (h *ProductHandler) GetStockHistory(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid product ID"})
+		return
+	}
+
+	var filter models.StockMovementFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid filter parameters"})
+		return
+	}
+
+	result, err := h.service.GetStockHistory(uint(id), filter)
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusBadRequest), ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,114 @@
+// internal/api/product_handler_test.go
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"phone-accessories/internal/models"
+	"phone-accessories/internal/service"
+)
+
+// fakeProductService lists its products the way the repository filters them
+// by variant options. Only the methods the tests call are implemented.
+type fakeProductService struct {
+	service.ProductService
+	products []models.Product
+}
+
+func (s *fakeProductService) ListProducts(filter models.ProductFilter) (*models.PaginatedResponse, error) {
+	var items []models.Product
+	for _, product := range s.products {
+		if hasVariant(product, filter.Options) {
+			items = append(items, product)
+		}
+	}
+	return &models.PaginatedResponse{Items: items, Page: filter.Page, PageSize: filter.PageSize, TotalItems: int64(len(items))}, nil
+}
+
+// hasVariant reports whether a product has a variant with all the options
+func hasVariant(product models.Product, options map[string]string) bool {
+	if len(options) == 0 {
+		return true
+	}
+	for _, variant := range product.Variants {
+		matches := true
+		for name, value := range options {
+			if variant.VariantOptions[name] != value {
+				matches = false
+			}
+		}
+		if matches {
+			return true
+		}
+	}
+	return false
+}
+
+func TestListProductsOptionFilter(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	products := &fakeProductService{products: []models.Product{
+		{ID: 1, SKU: "CASE-1", Variants: []models.Product{
+			{ID: 11, VariantOptions: models.JSON{"colour": "Noir", "size": "6.1"}},
+			{ID: 12, VariantOptions: models.JSON{"colour": "Bleu", "size": "6.1"}},
+		}},
+		{ID: 2, SKU: "CASE-2", Variants: []models.Product{
+			{ID: 21, VariantOptions: models.JSON{"colour": "Bleu", "size": "6.7"}},
+		}},
+		{ID: 3, SKU: "CABLE-1"},
+	}}
+	router := gin.New()
+	router.GET("/products", NewProductHandler(products).ListProducts)
+
+	tests := []struct {
+		name       string
+		query      string
+		wantStatus int
+		wantSKUs   []string
+	}{
+		{name: "no option filter", query: "", wantStatus: http.StatusOK, wantSKUs: []string{"CASE-1", "CASE-2", "CABLE-1"}},
+		{name: "one option", query: "?option[colour]=Noir", wantStatus: http.StatusOK, wantSKUs: []string{"CASE-1"}},
+		{name: "options of one variant", query: "?option[colour]=Bleu&option[size]=6.7", wantStatus: http.StatusOK, wantSKUs: []string{"CASE-2"}},
+		{name: "options of different variants", query: "?option[colour]=Noir&option[size]=6.7", wantStatus: http.StatusOK},
+		{name: "empty value is ignored", query: "?option[colour]=", wantStatus: http.StatusOK, wantSKUs: []string{"CASE-1", "CASE-2", "CABLE-1"}},
+		{name: "missing name", query: "?option[]=Noir", wantStatus: http.StatusBadRequest},
+		{name: "unclosed name", query: "?option[colour=Noir", wantStatus: http.StatusBadRequest},
+		{name: "several values", query: "?option[colour]=Noir&option[colour]=Bleu", wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/products"+tt.query, nil))
+
+			if recorder.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", recorder.Code, tt.wantStatus, recorder.Body.String())
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+			var body struct {
+				Items []models.Product `json:"items"`
+			}
+			if err := json.Unmarshal(recorder.Body.Bytes(), &body); err != nil {
+				t.Fatalf("decoding response: %v", err)
+			}
+			var skus []string
+			for _, product := range body.Items {
+				skus = append(skus, product.SKU)
+			}
+			if len(skus) != len(tt.wantSKUs) {
+				t.Fatalf("listed %v, want %v", skus, tt.wantSKUs)
+			}
+			for i := range skus {
+				if skus[i] != tt.wantSKUs[i] {
+					t.Fatalf("listed %v, want %v", skus, tt.wantSKUs)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,167 @@
+// internal/api/promotion_handler.go
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"phone-accessories/internal/models"
+	"phone-accessories/internal/service"
+)
+
+type PromotionHandler struct {
+	service service.PromotionService
+}
+
+func NewPromotionHandler(service service.PromotionService) *PromotionHandler {
+	return &PromotionHandler{service: service}
+}
+
+// ListPromotions godoc
+// @Summary      List promotions
+// @Description  Get promotions, newest first, optionally only those running now
+// @Tags         promotions
+// @Accept       json
+// @Produce      json
+// @Param        scope     query     string  false  "Filter by scope (product, category or brand)"
+// @Param        targetId  query     int     false  "Filter by the product, category or brand targeted"
+// @Param        active    query     bool    false  "Filter by active flag"
+// @Param        current   query     bool    false  "Only promotions running now"
+// @Success      200       {array}   models.Promotion
+// @Failure      400       {object}  ErrorResponse
+// @Failure      500       {object}  ErrorResponse
+// @Router       /promotions [get]
+func (h *PromotionHandler) ListPromotions(c *gin.Context) {
+	var filter models.PromotionFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid filter parameters"})
+		return
+	}
+
+	promotions, err := h.service.ListPromotions(filter)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, promotions)
+}
+
+// GetPromotion godoc
+// @Summary      Get promotion by ID
+// @Description  Get detailed information about a promotion
+// @Tags         promotions
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "Promotion ID"
+// @Success      200  {object}  models.Promotion
+// @Failure      404  {object}  ErrorResponse
+// @Failure      500  {object}  ErrorResponse
+// @Router       /promotions/{id} [get]
+func (h *PromotionHandler) GetPromotion(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid promotion ID"})
+		return
+	}
+
+	promotion, err := h.service.GetPromotionByID(uint(id))
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusInternalServerError), ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, promotion)
+}
+
+// CreatePromotion godoc
+// @Summary      Create promotion
+// @Description  Schedule a sale: a sale price for a product, or a percentage or fixed amount off a product, category or brand, between startsAt and endsAt. Among the promotions running for a product the highest priority wins, then the lowest price.
+// @Tags         promotions
+// @Accept       json
+// @Produce      json
+// @Param        promotion  body      models.Promotion  true  "Promotion information"
+// @Success      201        {object}  models.Promotion
+// @Failure      400        {object}  ErrorResponse
+// @Failure      404        {object}  ErrorResponse
+// @Failure      500        {object}  ErrorResponse
+// @Router       /promotions [post]
+func (h *PromotionHandler) CreatePromotion(c *gin.Context) {
+	var promotion models.Promotion
+	if err := c.ShouldBindJSON(&promotion); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid promotion data"})
+		return
+	}
+
+	if err := h.service.CreatePromotion(&promotion); err != nil {
+		c.JSON(errorStatus(err, http.StatusBadRequest), ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, promotion)
+}
+
+// UpdatePromotion godoc
+// @Summary      Update promotion
+// @Description  Update an existing promotion
+// @Tags         promotions
+// @Accept       json
+// @Produce      json
+// @Param        id         path      int               true  "Promotion ID"
+// @Param        promotion  body      models.Promotion  true  "Promotion information"
+// @Success      200        {object}  models.Promotion
+// @Failure      400        {object}  ErrorResponse
+// @Failure      404        {object}  ErrorResponse
+// @Failure      500        {object}  ErrorResponse
+// @Router       /promotions/{id} [put]
+func (h *PromotionHandler) UpdatePromotion(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid promotion ID"})
+		return
+	}
+
+	var promotion models.Promotion
+	if err := c.ShouldBindJSON(&promotion); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid promotion data"})
+		return
+	}
+
+	// Ensure the ID in the path matches the promotion
+	promotion.ID = uint(id)
+
+	if err := h.service.UpdatePromotion(&promotion); err != nil {
+		c.JSON(errorStatus(err, http.StatusBadRequest), ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, promotion)
+}
+
+// DeletePromotion godoc
+// @Summary      Delete promotion
+// @Description  Delete a promotion, ending its sale prices at once
+// @Tags         promotions
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "Promotion ID"
+// @Success      204  {object}  nil
+// @Failure      404  {object}  ErrorResponse
+// @Failure      500  {object}  ErrorResponse
+// @Router       /promotions/{id} [delete]
+func (h *PromotionHandler) DeletePromotion(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid promotion ID"})
+		return
+	}
+
+	if err := h.service.DeletePromotion(uint(id)); err != nil {
+		c.JSON(errorStatus(err, http.StatusInternalServerError), ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
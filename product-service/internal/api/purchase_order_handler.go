@@ -0,0 +1,238 @@
+// internal/api/purchase_order_handler.go
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"phone-accessories/internal/models"
+	"phone-accessories/internal/service"
+)
+
+type PurchaseOrderHandler struct {
+	service service.PurchaseOrderService
+}
+
+func NewPurchaseOrderHandler(service service.PurchaseOrderService) *PurchaseOrderHandler {
+	return &PurchaseOrderHandler{service: service}
+}
+
+type ReceiveRequest struct {
+	Actor string                        `json:"actor"`
+	Lines []models.PurchaseOrderReceipt `json:"lines" binding:"required,min=1"`
+}
+
+// ListPurchaseOrders godoc
+// @Summary      List purchase orders
+// @Description  Get paginated purchase orders, newest first
+// @Tags         purchase-orders
+// @Accept       json
+// @Produce      json
+// @Param        supplierId  query     int     false  "Filter by supplier ID"
+// @Param        status      query     string  false  "Filter by status"
+// @Param        page        query     int     false  "Page number"
+// @Param        pageSize    query     int     false  "Items per page"
+// @Success      200         {object}  models.PaginatedResponse
+// @Failure      400         {object}  ErrorResponse
+// @Failure      500         {object}  ErrorResponse
+// @Router       /purchase-orders [get]
+func (h *PurchaseOrderHandler) ListPurchaseOrders(c *gin.Context) {
+	var filter models.PurchaseOrderFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid filter parameters"})
+		return
+	}
+
+	result, err := h.service.ListPurchaseOrders(filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// GetPurchaseOrder godoc
+// @Summary      Get purchase order by ID
+// @Description  Get a purchase order with its supplier and lines
+// @Tags         purchase-orders
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "Purchase order ID"
+// @Success      200  {object}  models.PurchaseOrder
+// @Failure      404  {object}  ErrorResponse
+// @Failure      500  {object}  ErrorResponse
+// @Router       /purchase-orders/{id} [get]
+func (h *PurchaseOrderHandler) GetPurchaseOrder(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid purchase order ID"})
+		return
+	}
+
+	order, err := h.service.GetPurchaseOrderByID(uint(id))
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusInternalServerError), ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, order)
+}
+
+// CreatePurchaseOrder godoc
+// @Summary      Create purchase order
+// @Description  Create a draft purchase order with a supplier
+// @Tags         purchase-orders
+// @Accept       json
+// @Produce      json
+// @Param        order  body      models.PurchaseOrder  true  "Purchase order information"
+// @Success      201    {object}  models.PurchaseOrder
+// @Failure      400    {object}  ErrorResponse
+// @Failure      500    {object}  ErrorResponse
+// @Router       /purchase-orders [post]
+func (h *PurchaseOrderHandler) CreatePurchaseOrder(c *gin.Context) {
+	var order models.PurchaseOrder
+	if err := c.ShouldBindJSON(&order); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid purchase order data"})
+		return
+	}
+
+	if err := h.service.CreatePurchaseOrder(&order); err != nil {
+		c.JSON(errorStatus(err, http.StatusBadRequest), ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, order)
+}
+
+// UpdatePurchaseOrder godoc
+// @Summary      Update purchase order
+// @Description  Update a draft purchase order, replacing its lines
+// @Tags         purchase-orders
+// @Accept       json
+// @Produce      json
+// @Param        id     path      int                   true  "Purchase order ID"
+// @Param        order  body      models.PurchaseOrder  true  "Purchase order information"
+// @Success      200    {object}  models.PurchaseOrder
+// @Failure      400    {object}  ErrorResponse
+// @Failure      404    {object}  ErrorResponse
+// @Failure      409    {object}  ErrorResponse
+// @Failure      500    {object}  ErrorResponse
+// @Router       /purchase-orders/{id} [put]
+func (h *PurchaseOrderHandler) UpdatePurchaseOrder(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid purchase order ID"})
+		return
+	}
+
+	var order models.PurchaseOrder
+	if err := c.ShouldBindJSON(&order); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid purchase order data"})
+		return
+	}
+
+	// Ensure the ID in the path matches the purchase order
+	order.ID = uint(id)
+
+	if err := h.service.UpdatePurchaseOrder(&order); err != nil {
+		c.JSON(errorStatus(err, http.StatusBadRequest), ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, order)
+}
+
+// SendPurchaseOrder godoc
+// @Summary      Send purchase order
+// @Description  Mark a draft purchase order as sent to the supplier; its quantities then count as incoming stock
+// @Tags         purchase-orders
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "Purchase order ID"
+// @Success      200  {object}  models.PurchaseOrder
+// @Failure      404  {object}  ErrorResponse
+// @Failure      409  {object}  ErrorResponse
+// @Failure      500  {object}  ErrorResponse
+// @Router       /purchase-orders/{id}/send [post]
+func (h *PurchaseOrderHandler) SendPurchaseOrder(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid purchase order ID"})
+		return
+	}
+
+	order, err := h.service.SendPurchaseOrder(uint(id))
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusInternalServerError), ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, order)
+}
+
+// CancelPurchaseOrder godoc
+// @Summary      Cancel purchase order
+// @Description  Cancel a purchase order that has not been fully received
+// @Tags         purchase-orders
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "Purchase order ID"
+// @Success      200  {object}  models.PurchaseOrder
+// @Failure      404  {object}  ErrorResponse
+// @Failure      409  {object}  ErrorResponse
+// @Failure      500  {object}  ErrorResponse
+// @Router       /purchase-orders/{id}/cancel [post]
+func (h *PurchaseOrderHandler) CancelPurchaseOrder(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid purchase order ID"})
+		return
+	}
+
+	order, err := h.service.CancelPurchaseOrder(uint(id))
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusInternalServerError), ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, order)
+}
+
+// ReceivePurchaseOrder godoc
+// @Summary      Receive goods
+// @Description  Book the quantities that arrived against a sent purchase order and add them to stock
+// @Tags         purchase-orders
+// @Accept       json
+// @Produce      json
+// @Param        id       path      int             true  "Purchase order ID"
+// @Param        receipt  body      ReceiveRequest  true  "Received quantities per line"
+// @Success      200      {object}  models.PurchaseOrder
+// @Failure      400      {object}  ErrorResponse
+// @Failure      404      {object}  ErrorResponse
+// @Failure      409      {object}  ErrorResponse
+// @Failure      500      {object}  ErrorResponse
+// @Router       /purchase-orders/{id}/receive [post]
+func (h *PurchaseOrderHandler) ReceivePurchaseOrder(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid purchase order ID"})
+		return
+	}
+
+	var req ReceiveRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid receipt data"})
+		return
+	}
+
+	order, err := h.service.ReceivePurchaseOrder(uint(id), req.Lines, req.Actor)
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusBadRequest), ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, order)
+}
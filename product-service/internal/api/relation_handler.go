@@ -0,0 +1,175 @@
+// internal/api/relation_handler.go
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"phone-accessories/internal/models"
+	"phone-accessories/internal/service"
+)
+
+type RelationHandler struct {
+	service service.RelationService
+}
+
+func NewRelationHandler(service service.RelationService) *RelationHandler {
+	return &RelationHandler{service: service}
+}
+
+type RelationRequest struct {
+	TargetID uint                `json:"targetId" binding:"required"`
+	Type     models.RelationType `json:"type" binding:"required"`
+}
+
+// ListRelated godoc
+// @Summary      List related products
+// @Description  Get the products related to a product, by type and position. Inactive and out-of-stock targets are left out unless includeUnavailable is set.
+// @Tags         relations
+// @Accept       json
+// @Produce      json
+// @Param        id                  path      int     true   "Product ID"
+// @Param        type                query     string  false  "Relation type (accessory_of, cross_sell, up_sell or replacement_for)"
+// @Param        includeUnavailable  query     bool    false  "Also return inactive and out-of-stock targets"
+// @Success      200                 {array}   models.ProductRelation
+// @Failure      400                 {object}  ErrorResponse
+// @Failure      404                 {object}  ErrorResponse
+// @Failure      500                 {object}  ErrorResponse
+// @Router       /products/{id}/related [get]
+func (h *RelationHandler) ListRelated(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid product ID"})
+		return
+	}
+
+	var filter models.RelatedProductFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid filter parameters"})
+		return
+	}
+
+	relations, err := h.service.ListRelated(uint(id), filter)
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusBadRequest), ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, relations)
+}
+
+// CreateRelation godoc
+// @Summary      Relate products
+// @Description  Relate a target product to a product; the relation is placed after the product's other relations of the same type
+// @Tags         relations
+// @Accept       json
+// @Produce      json
+// @Param        id        path      int              true  "Product ID"
+// @Param        relation  body      RelationRequest  true  "Target product and relation type"
+// @Success      201       {object}  models.ProductRelation
+// @Failure      400       {object}  ErrorResponse
+// @Failure      404       {object}  ErrorResponse
+// @Failure      409       {object}  ErrorResponse
+// @Failure      500       {object}  ErrorResponse
+// @Router       /products/{id}/relations [post]
+func (h *RelationHandler) CreateRelation(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid product ID"})
+		return
+	}
+
+	var request RelationRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid relation data"})
+		return
+	}
+
+	relation := models.ProductRelation{
+		ProductID: uint(id),
+		TargetID:  request.TargetID,
+		Type:      request.Type,
+	}
+	if err := h.service.CreateRelation(&relation); err != nil {
+		c.JSON(errorStatus(err, http.StatusBadRequest), ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, relation)
+}
+
+// UpdateRelation godoc
+// @Summary      Update product relation
+// @Description  Change the type or position of a product relation
+// @Tags         relations
+// @Accept       json
+// @Produce      json
+// @Param        id          path      int                           true  "Product ID"
+// @Param        relationId  path      int                           true  "Relation ID"
+// @Param        relation    body      models.ProductRelationUpdate  true  "Relation details"
+// @Success      200         {object}  models.ProductRelation
+// @Failure      400         {object}  ErrorResponse
+// @Failure      404         {object}  ErrorResponse
+// @Failure      409         {object}  ErrorResponse
+// @Failure      500         {object}  ErrorResponse
+// @Router       /products/{id}/relations/{relationId} [patch]
+func (h *RelationHandler) UpdateRelation(c *gin.Context) {
+	productID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid product ID"})
+		return
+	}
+	relationID, err := strconv.ParseUint(c.Param("relationId"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid relation ID"})
+		return
+	}
+
+	var update models.ProductRelationUpdate
+	if err := c.ShouldBindJSON(&update); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid relation data"})
+		return
+	}
+
+	relation, err := h.service.UpdateRelation(uint(productID), uint(relationID), update)
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusBadRequest), ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, relation)
+}
+
+// DeleteRelation godoc
+// @Summary      Delete product relation
+// @Description  Remove a related product from a product
+// @Tags         relations
+// @Accept       json
+// @Produce      json
+// @Param        id          path      int  true  "Product ID"
+// @Param        relationId  path      int  true  "Relation ID"
+// @Success      204         {object}  nil
+// @Failure      404         {object}  ErrorResponse
+// @Failure      500         {object}  ErrorResponse
+// @Router       /products/{id}/relations/{relationId} [delete]
+func (h *RelationHandler) DeleteRelation(c *gin.Context) {
+	productID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid product ID"})
+		return
+	}
+	relationID, err := strconv.ParseUint(c.Param("relationId"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid relation ID"})
+		return
+	}
+
+	if err := h.service.DeleteRelation(uint(productID), uint(relationID)); err != nil {
+		c.JSON(errorStatus(err, http.StatusInternalServerError), ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,94 @@
+// internal/api/report_handler.go
+package api
+
+import (
+	"encoding/csv"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"phone-accessories/internal/models"
+	"phone-accessories/internal/service"
+)
+
+type ReportHandler struct {
+	service service.ReportService
+}
+
+func NewReportHandler(service service.ReportService) *ReportHandler {
+	return &ReportHandler{service: service}
+}
+
+type InventoryValuationQuery struct {
+	AsOf   *time.Time `form:"asOf" time_format:"2006-01-02" time_utc:"1"`
+	Format string     `form:"format"`
+}
+
+// InventoryValuation godoc
+// @Summary      Inventory valuation report
+// @Description  Get the FIFO cost value of the stock on hand at the end of a day, per product and per category, as JSON or CSV
+// @Tags         reports
+// @Produce      json
+// @Produce      text/csv
+// @Param        asOf    query     string  false  "Valuation date (YYYY-MM-DD), defaults to today"
+// @Param        format  query     string  false  "Output format: json (default) or csv"
+// @Success      200     {object}  models.InventoryValuation
+// @Failure      400     {object}  ErrorResponse
+// @Failure      500     {object}  ErrorResponse
+// @Router       /reports/inventory-valuation [get]
+func (h *ReportHandler) InventoryValuation(c *gin.Context) {
+	var query InventoryValuationQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid report parameters"})
+		return
+	}
+	if query.Format != "" && query.Format != "json" && query.Format != "csv" {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Report format must be json or csv"})
+		return
+	}
+
+	asOf := time.Now().UTC()
+	if query.AsOf != nil {
+		asOf = *query.AsOf
+	}
+
+	valuation, err := h.service.InventoryValuation(asOf)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	if query.Format == "csv" {
+		writeValuationCSV(c, valuation)
+		return
+	}
+	c.JSON(http.StatusOK, valuation)
+}
+
+// writeValuationCSV writes one row per product, followed by a subtotal row per
+// category and a grand total, so the file can be imported as-is
+func writeValuationCSV(c *gin.Context, valuation *models.InventoryValuation) {
+	filename := fmt.Sprintf("inventory-valuation-%s.csv", valuation.AsOf.Format("2006-01-02"))
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	c.Status(http.StatusOK)
+
+	w := csv.NewWriter(c.Writer)
+	_ = w.Write([]string{"row_type", "category_id", "category", "product_id", "sku", "name",
+		"quantity", "average_unit_cost", "value"})
+	for _, line := range valuation.Products {
+		_ = w.Write([]string{"product", strconv.FormatUint(uint64(line.CategoryID), 10), line.CategoryName,
+			strconv.FormatUint(uint64(line.ProductID), 10), line.SKU, line.Name,
+			strconv.Itoa(line.Quantity), line.AverageUnitCost.String(), line.Value.String()})
+	}
+	for _, category := range valuation.Categories {
+		_ = w.Write([]string{"category", strconv.FormatUint(uint64(category.CategoryID), 10), category.CategoryName,
+			"", "", "", strconv.Itoa(category.Quantity), "", category.Value.String()})
+	}
+	_ = w.Write([]string{"total", "", "", "", "", "", strconv.Itoa(valuation.TotalQuantity), "",
+		valuation.TotalValue.String()})
+	w.Flush()
+}
@@ -0,0 +1,196 @@
+// internal/api/reservation_handler.go
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"phone-accessories/internal/models"
+	"phone-accessories/internal/service"
+)
+
+type ReservationHandler struct {
+	service service.ReservationService
+}
+
+func NewReservationHandler(service service.ReservationService) *ReservationHandler {
+	return &ReservationHandler{service: service}
+}
+
+type ProductReservationRequest struct {
+	Quantity   int    `json:"quantity" binding:"required,min=1"`
+	LocationID *uint  `json:"locationId"`
+	Reference  string `json:"reference"`
+	TTLSeconds int    `json:"ttlSeconds" binding:"min=0"`
+}
+
+type ReservationItemRequest struct {
+	ProductID  uint  `json:"productId" binding:"required"`
+	LocationID *uint `json:"locationId"`
+	Quantity   int   `json:"quantity" binding:"required,min=1"`
+}
+
+type ReservationRequest struct {
+	Reference  string                   `json:"reference"`
+	TTLSeconds int                      `json:"ttlSeconds" binding:"min=0"`
+	Items      []ReservationItemRequest `json:"items" binding:"required,min=1,dive"`
+}
+
+// ReserveProduct godoc
+// @Summary      Reserve product stock
+// @Description  Hold a quantity of a product for a limited time during checkout
+// @Tags         reservations
+// @Accept       json
+// @Produce      json
+// @Param        id           path      int                        true  "Product ID"
+// @Param        reservation  body      ProductReservationRequest  true  "Reservation information"
+// @Success      201          {object}  models.Reservation
+// @Failure      400          {object}  ErrorResponse
+// @Failure      409          {object}  ErrorResponse
+// @Failure      500          {object}  ErrorResponse
+// @Router       /products/{id}/reservations [post]
+func (h *ReservationHandler) ReserveProduct(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid product ID"})
+		return
+	}
+
+	var req ProductReservationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid reservation data"})
+		return
+	}
+
+	items := []models.ReservationItem{{ProductID: uint(id), LocationID: req.LocationID, Quantity: req.Quantity}}
+	reservation, err := h.service.CreateReservation(req.Reference, items, time.Duration(req.TTLSeconds)*time.Second)
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusBadRequest), ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, reservation)
+}
+
+// CreateReservation godoc
+// @Summary      Reserve cart stock
+// @Description  Hold the stock for several products at once; either every line is reserved or none is
+// @Tags         reservations
+// @Accept       json
+// @Produce      json
+// @Param        reservation  body      ReservationRequest  true  "Reservation information"
+// @Success      201          {object}  models.Reservation
+// @Failure      400          {object}  ErrorResponse
+// @Failure      409          {object}  ErrorResponse
+// @Failure      500          {object}  ErrorResponse
+// @Router       /reservations [post]
+func (h *ReservationHandler) CreateReservation(c *gin.Context) {
+	var req ReservationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid reservation data"})
+		return
+	}
+
+	items := make([]models.ReservationItem, 0, len(req.Items))
+	for _, item := range req.Items {
+		items = append(items, models.ReservationItem{
+			ProductID:  item.ProductID,
+			LocationID: item.LocationID,
+			Quantity:   item.Quantity,
+		})
+	}
+
+	reservation, err := h.service.CreateReservation(req.Reference, items, time.Duration(req.TTLSeconds)*time.Second)
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusBadRequest), ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, reservation)
+}
+
+// GetReservation godoc
+// @Summary      Get reservation by ID
+// @Description  Get a stock reservation and its items
+// @Tags         reservations
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "Reservation ID"
+// @Success      200  {object}  models.Reservation
+// @Failure      404  {object}  ErrorResponse
+// @Failure      500  {object}  ErrorResponse
+// @Router       /reservations/{id} [get]
+func (h *ReservationHandler) GetReservation(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid reservation ID"})
+		return
+	}
+
+	reservation, err := h.service.GetReservation(uint(id))
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusInternalServerError), ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, reservation)
+}
+
+// ConfirmReservation godoc
+// @Summary      Confirm reservation
+// @Description  Turn a held reservation into a stock decrement
+// @Tags         reservations
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "Reservation ID"
+// @Success      200  {object}  models.Reservation
+// @Failure      404  {object}  ErrorResponse
+// @Failure      409  {object}  ErrorResponse
+// @Failure      500  {object}  ErrorResponse
+// @Router       /reservations/{id}/confirm [post]
+func (h *ReservationHandler) ConfirmReservation(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid reservation ID"})
+		return
+	}
+
+	reservation, err := h.service.ConfirmReservation(uint(id))
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusInternalServerError), ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, reservation)
+}
+
+// ReleaseReservation godoc
+// @Summary      Release reservation
+// @Description  Return the units held by a reservation to available stock
+// @Tags         reservations
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "Reservation ID"
+// @Success      200  {object}  models.Reservation
+// @Failure      404  {object}  ErrorResponse
+// @Failure      409  {object}  ErrorResponse
+// @Failure      500  {object}  ErrorResponse
+// @Router       /reservations/{id}/release [post]
+func (h *ReservationHandler) ReleaseReservation(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid reservation ID"})
+		return
+	}
+
+	reservation, err := h.service.ReleaseReservation(uint(id))
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusInternalServerError), ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, reservation)
+}
@@ -10,7 +10,23 @@ import (
 func SetupRoutes(router *gin.Engine,
 	productService service.ProductService,
 	categoryService service.CategoryService,
-	searchService service.SearchService) {
+	searchService service.SearchService,
+	reservationService service.ReservationService,
+	locationService service.LocationService,
+	inventoryService service.InventoryService,
+	supplierService service.SupplierService,
+	purchaseOrderService service.PurchaseOrderService,
+	stockTakeService service.StockTakeService,
+	reportService service.ReportService,
+	mediaService service.MediaService,
+	relationService service.RelationService,
+	brandService service.BrandService,
+	deviceService service.DeviceService,
+	attributeService service.AttributeService,
+	priceListService service.PriceListService,
+	promotionService service.PromotionService,
+	pricingService service.PricingService,
+	discountService service.DiscountService) {
 
 	// API versioning
 	v1 := router.Group("/api/v1")
@@ -23,10 +39,33 @@ func SetupRoutes(router *gin.Engine,
 	{
 		products.GET("", NewProductHandler(productService).ListProducts)
 		products.POST("", NewProductHandler(productService).CreateProduct)
+		products.GET("/slug/:slug", NewProductHandler(productService).GetProductBySlug)
 		products.GET("/:id", NewProductHandler(productService).GetProduct)
 		products.PUT("/:id", NewProductHandler(productService).UpdateProduct)
 		products.DELETE("/:id", NewProductHandler(productService).DeleteProduct)
+		products.POST("/:id/variants", NewProductHandler(productService).CreateVariant)
+		products.GET("/:id/media", NewMediaHandler(mediaService).ListMedia)
+		products.POST("/:id/media", NewMediaHandler(mediaService).UploadMedia)
+		products.PATCH("/:id/media/:mediaId", NewMediaHandler(mediaService).UpdateMedia)
+		products.DELETE("/:id/media/:mediaId", NewMediaHandler(mediaService).DeleteMedia)
+		products.GET("/:id/related", NewRelationHandler(relationService).ListRelated)
+		products.POST("/:id/relations", NewRelationHandler(relationService).CreateRelation)
+		products.PATCH("/:id/relations/:relationId", NewRelationHandler(relationService).UpdateRelation)
+		products.DELETE("/:id/relations/:relationId", NewRelationHandler(relationService).DeleteRelation)
+		products.GET("/:id/price-tiers", NewPricingHandler(pricingService).ListPriceTiers)
+		products.PUT("/:id/price-tiers", NewPricingHandler(pricingService).SetPriceTiers)
 		products.PATCH("/:id/stock", NewProductHandler(productService).UpdateStock)
+		products.GET("/:id/stock/history", NewProductHandler(productService).GetStockHistory)
+		products.POST("/:id/reservations", NewReservationHandler(reservationService).ReserveProduct)
+	}
+
+	// Reservation routes
+	reservations := v1.Group("/reservations")
+	{
+		reservations.POST("", NewReservationHandler(reservationService).CreateReservation)
+		reservations.GET("/:id", NewReservationHandler(reservationService).GetReservation)
+		reservations.POST("/:id/confirm", NewReservationHandler(reservationService).ConfirmReservation)
+		reservations.POST("/:id/release", NewReservationHandler(reservationService).ReleaseReservation)
 	}
 
 	// Category routes
@@ -34,9 +73,143 @@ func SetupRoutes(router *gin.Engine,
 	{
 		categories.GET("", NewCategoryHandler(categoryService).ListCategories)
 		categories.POST("", NewCategoryHandler(categoryService).CreateCategory)
+		categories.GET("/tree", NewCategoryHandler(categoryService).GetCategoryTree)
+		categories.GET("/slug/:slug", NewCategoryHandler(categoryService).GetCategoryBySlug)
 		categories.GET("/:id", NewCategoryHandler(categoryService).GetCategory)
+		categories.GET("/:id/breadcrumbs", NewCategoryHandler(categoryService).GetBreadcrumbs)
 		categories.PUT("/:id", NewCategoryHandler(categoryService).UpdateCategory)
 		categories.DELETE("/:id", NewCategoryHandler(categoryService).DeleteCategory)
+		categories.GET("/:id/attributes", NewAttributeHandler(attributeService).GetSchema)
+		categories.POST("/:id/attributes", NewAttributeHandler(attributeService).CreateAttribute)
+		categories.PUT("/:id/attributes/:attributeId", NewAttributeHandler(attributeService).UpdateAttribute)
+		categories.DELETE("/:id/attributes/:attributeId", NewAttributeHandler(attributeService).DeleteAttribute)
+	}
+
+	// Location routes
+	locations := v1.Group("/locations")
+	{
+		locations.GET("", NewLocationHandler(locationService).ListLocations)
+		locations.POST("", NewLocationHandler(locationService).CreateLocation)
+		locations.GET("/:id", NewLocationHandler(locationService).GetLocation)
+		locations.PUT("/:id", NewLocationHandler(locationService).UpdateLocation)
+		locations.DELETE("/:id", NewLocationHandler(locationService).DeleteLocation)
+	}
+
+	// Inventory routes
+	inventory := v1.Group("/inventory")
+	{
+		inventory.POST("/transfers", NewInventoryHandler(inventoryService).TransferStock)
+		inventory.GET("/low-stock", NewInventoryHandler(inventoryService).ListLowStock)
+		inventory.POST("/adjustments", NewInventoryHandler(inventoryService).AdjustStock)
+	}
+
+	// Stock take routes
+	stockTakes := v1.Group("/stock-takes")
+	{
+		stockTakes.GET("", NewStockTakeHandler(stockTakeService).ListStockTakes)
+		stockTakes.POST("", NewStockTakeHandler(stockTakeService).OpenStockTake)
+		stockTakes.GET("/:id", NewStockTakeHandler(stockTakeService).GetStockTake)
+		stockTakes.POST("/:id/counts", NewStockTakeHandler(stockTakeService).RecordCounts)
+		stockTakes.GET("/:id/variance", NewStockTakeHandler(stockTakeService).GetVariance)
+		stockTakes.POST("/:id/commit", NewStockTakeHandler(stockTakeService).CommitStockTake)
+		stockTakes.POST("/:id/cancel", NewStockTakeHandler(stockTakeService).CancelStockTake)
+	}
+
+	// Brand routes
+	brands := v1.Group("/brands")
+	{
+		brands.GET("", NewBrandHandler(brandService).ListBrands)
+		brands.POST("", NewBrandHandler(brandService).CreateBrand)
+		brands.GET("/:id", NewBrandHandler(brandService).GetBrand)
+		brands.PUT("/:id", NewBrandHandler(brandService).UpdateBrand)
+		brands.DELETE("/:id", NewBrandHandler(brandService).DeleteBrand)
+	}
+
+	// Device routes
+	devices := v1.Group("/devices")
+	{
+		devices.GET("", NewDeviceHandler(deviceService).ListDevices)
+		devices.POST("", NewDeviceHandler(deviceService).CreateDevice)
+		devices.GET("/:id", NewDeviceHandler(deviceService).GetDevice)
+		devices.PUT("/:id", NewDeviceHandler(deviceService).UpdateDevice)
+		devices.DELETE("/:id", NewDeviceHandler(deviceService).DeleteDevice)
+		devices.GET("/:id/products", NewDeviceHandler(deviceService).ListDeviceProducts)
+	}
+
+	// Price list routes
+	priceLists := v1.Group("/price-lists")
+	{
+		priceLists.GET("", NewPriceListHandler(priceListService).ListPriceLists)
+		priceLists.POST("", NewPriceListHandler(priceListService).CreatePriceList)
+		priceLists.GET("/:id", NewPriceListHandler(priceListService).GetPriceList)
+		priceLists.PUT("/:id", NewPriceListHandler(priceListService).UpdatePriceList)
+		priceLists.DELETE("/:id", NewPriceListHandler(priceListService).DeletePriceList)
+		priceLists.GET("/:id/prices", NewPriceListHandler(priceListService).ListProductPrices)
+		priceLists.PUT("/:id/prices/:productId", NewPriceListHandler(priceListService).SetProductPrice)
+		priceLists.DELETE("/:id/prices/:productId", NewPriceListHandler(priceListService).DeleteProductPrice)
+	}
+
+	// Exchange rate routes
+	exchangeRates := v1.Group("/exchange-rates")
+	{
+		exchangeRates.GET("", NewPriceListHandler(priceListService).ListExchangeRates)
+		exchangeRates.POST("", NewPriceListHandler(priceListService).CreateExchangeRate)
+		exchangeRates.DELETE("/:id", NewPriceListHandler(priceListService).DeleteExchangeRate)
+	}
+
+	// Pricing routes
+	pricing := v1.Group("/pricing")
+	{
+		pricing.POST("/quote", NewPricingHandler(pricingService).Quote)
+		pricing.POST("/redemptions", NewPricingHandler(pricingService).Redeem)
+	}
+
+	// Promotion routes
+	promotions := v1.Group("/promotions")
+	{
+		promotions.GET("", NewPromotionHandler(promotionService).ListPromotions)
+		promotions.POST("", NewPromotionHandler(promotionService).CreatePromotion)
+		promotions.GET("/:id", NewPromotionHandler(promotionService).GetPromotion)
+		promotions.PUT("/:id", NewPromotionHandler(promotionService).UpdatePromotion)
+		promotions.DELETE("/:id", NewPromotionHandler(promotionService).DeletePromotion)
+	}
+
+	// Discount routes
+	discounts := v1.Group("/discounts")
+	{
+		discounts.GET("", NewDiscountHandler(discountService).ListDiscountRules)
+		discounts.POST("", NewDiscountHandler(discountService).CreateDiscountRule)
+		discounts.GET("/:id", NewDiscountHandler(discountService).GetDiscountRule)
+		discounts.PUT("/:id", NewDiscountHandler(discountService).UpdateDiscountRule)
+		discounts.DELETE("/:id", NewDiscountHandler(discountService).DeleteDiscountRule)
+	}
+
+	// Supplier routes
+	suppliers := v1.Group("/suppliers")
+	{
+		suppliers.GET("", NewSupplierHandler(supplierService).ListSuppliers)
+		suppliers.POST("", NewSupplierHandler(supplierService).CreateSupplier)
+		suppliers.GET("/:id", NewSupplierHandler(supplierService).GetSupplier)
+		suppliers.PUT("/:id", NewSupplierHandler(supplierService).UpdateSupplier)
+		suppliers.DELETE("/:id", NewSupplierHandler(supplierService).DeleteSupplier)
+	}
+
+	// Purchase order routes
+	purchaseOrders := v1.Group("/purchase-orders")
+	{
+		purchaseOrders.GET("", NewPurchaseOrderHandler(purchaseOrderService).ListPurchaseOrders)
+		purchaseOrders.POST("", NewPurchaseOrderHandler(purchaseOrderService).CreatePurchaseOrder)
+		purchaseOrders.GET("/:id", NewPurchaseOrderHandler(purchaseOrderService).GetPurchaseOrder)
+		purchaseOrders.PUT("/:id", NewPurchaseOrderHandler(purchaseOrderService).UpdatePurchaseOrder)
+		purchaseOrders.POST("/:id/send", NewPurchaseOrderHandler(purchaseOrderService).SendPurchaseOrder)
+		purchaseOrders.POST("/:id/cancel", NewPurchaseOrderHandler(purchaseOrderService).CancelPurchaseOrder)
+		purchaseOrders.POST("/:id/receive", NewPurchaseOrderHandler(purchaseOrderService).ReceivePurchaseOrder)
+	}
+
+	// Report routes
+	reports := v1.Group("/reports")
+	{
+		reports.GET("/inventory-valuation", NewReportHandler(reportService).InventoryValuation)
 	}
 
 	// Search route
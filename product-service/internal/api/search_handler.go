@@ -7,6 +7,7 @@ import (
 
 	"github.com/gin-gonic/gin"
 
+	"phone-accessories/internal/models"
 	"phone-accessories/internal/service"
 )
 
@@ -20,15 +21,18 @@ func NewSearchHandler(service service.SearchService) *SearchHandler {
 
 // Search godoc
 // @Summary      Search products
-// @Description  Search products by query
+// @Description  Search products by name, description, SKU or brand name
 // @Tags         search
 // @Accept       json
 // @Produce      json
 // @Param        q         query     string  true   "Search query"
 // @Param        page      query     int     false  "Page number"
 // @Param        pageSize  query     int     false  "Items per page"
+// @Param        priceList query     int     false  "Resolve prices in this price list"
+// @Param        currency  query     string  false  "Resolve prices in this currency, through its default price list and exchange rates"
 // @Success      200       {object}  models.PaginatedResponse
 // @Failure      400       {object}  ErrorResponse
+// @Failure      404       {object}  ErrorResponse
 // @Failure      500       {object}  ErrorResponse
 // @Router       /search [get]
 func (h *SearchHandler) Search(c *gin.Context) {
@@ -48,9 +52,15 @@ func (h *SearchHandler) Search(c *gin.Context) {
 		pageSize = 20
 	}
 
-	result, err := h.service.Search(query, page, pageSize)
+	var priceQuery models.PriceQuery
+	if err := c.ShouldBindQuery(&priceQuery); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid price parameters"})
+		return
+	}
+
+	result, err := h.service.Search(query, page, pageSize, priceQuery)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+		c.JSON(errorStatus(err, http.StatusInternalServerError), ErrorResponse{Error: err.Error()})
 		return
 	}
 
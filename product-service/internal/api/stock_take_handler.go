@@ -0,0 +1,259 @@
+// internal/api/stock_take_handler.go
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"phone-accessories/internal/models"
+	"phone-accessories/internal/service"
+)
+
+type StockTakeHandler struct {
+	service service.StockTakeService
+}
+
+func NewStockTakeHandler(service service.StockTakeService) *StockTakeHandler {
+	return &StockTakeHandler{service: service}
+}
+
+type OpenStockTakeRequest struct {
+	LocationID uint   `json:"locationId"`
+	CategoryID *uint  `json:"categoryId"`
+	Notes      string `json:"notes"`
+	Actor      string `json:"actor"`
+}
+
+type StockTakeCountsRequest struct {
+	Actor  string                  `json:"actor"`
+	Counts []models.StockTakeCount `json:"counts" binding:"required,min=1"`
+}
+
+type CommitStockTakeRequest struct {
+	Actor string `json:"actor"`
+	// Force commits counted products whose stock moved after the stock take
+	// was opened instead of refusing them
+	Force bool `json:"force"`
+}
+
+// ListStockTakes godoc
+// @Summary      List stock takes
+// @Description  Get paginated stock-take sessions, newest first
+// @Tags         stock-takes
+// @Accept       json
+// @Produce      json
+// @Param        locationId  query     int     false  "Filter by location ID"
+// @Param        status      query     string  false  "Filter by status"
+// @Param        page        query     int     false  "Page number"
+// @Param        pageSize    query     int     false  "Items per page"
+// @Success      200         {object}  models.PaginatedResponse
+// @Failure      400         {object}  ErrorResponse
+// @Failure      500         {object}  ErrorResponse
+// @Router       /stock-takes [get]
+func (h *StockTakeHandler) ListStockTakes(c *gin.Context) {
+	var filter models.StockTakeFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid filter parameters"})
+		return
+	}
+
+	result, err := h.service.ListStockTakes(filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// OpenStockTake godoc
+// @Summary      Open stock take
+// @Description  Start a physical count for a location, optionally limited to one category, snapshotting the stock levels it covers
+// @Tags         stock-takes
+// @Accept       json
+// @Produce      json
+// @Param        stockTake  body      OpenStockTakeRequest  true  "Stock take scope"
+// @Success      201        {object}  models.StockTake
+// @Failure      400        {object}  ErrorResponse
+// @Failure      404        {object}  ErrorResponse
+// @Failure      500        {object}  ErrorResponse
+// @Router       /stock-takes [post]
+func (h *StockTakeHandler) OpenStockTake(c *gin.Context) {
+	var req OpenStockTakeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid stock take data"})
+		return
+	}
+
+	take := models.StockTake{
+		LocationID: req.LocationID,
+		CategoryID: req.CategoryID,
+		Notes:      req.Notes,
+		Actor:      req.Actor,
+	}
+	if err := h.service.OpenStockTake(&take); err != nil {
+		c.JSON(errorStatus(err, http.StatusBadRequest), ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, take)
+}
+
+// GetStockTake godoc
+// @Summary      Get stock take by ID
+// @Description  Get a stock take with its expected and counted quantities
+// @Tags         stock-takes
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "Stock take ID"
+// @Success      200  {object}  models.StockTake
+// @Failure      404  {object}  ErrorResponse
+// @Failure      500  {object}  ErrorResponse
+// @Router       /stock-takes/{id} [get]
+func (h *StockTakeHandler) GetStockTake(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid stock take ID"})
+		return
+	}
+
+	take, err := h.service.GetStockTake(uint(id))
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusInternalServerError), ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, take)
+}
+
+// RecordCounts godoc
+// @Summary      Submit counted quantities
+// @Description  Record counted quantities per product or SKU; counts can be submitted in several batches and a later count replaces an earlier one
+// @Tags         stock-takes
+// @Accept       json
+// @Produce      json
+// @Param        id      path      int                     true  "Stock take ID"
+// @Param        counts  body      StockTakeCountsRequest  true  "Counted quantities"
+// @Success      200     {object}  models.StockTake
+// @Failure      400     {object}  ErrorResponse
+// @Failure      404     {object}  ErrorResponse
+// @Failure      409     {object}  ErrorResponse
+// @Failure      500     {object}  ErrorResponse
+// @Router       /stock-takes/{id}/counts [post]
+func (h *StockTakeHandler) RecordCounts(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid stock take ID"})
+		return
+	}
+
+	var req StockTakeCountsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid count data"})
+		return
+	}
+
+	take, err := h.service.RecordCounts(uint(id), req.Counts, req.Actor)
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusBadRequest), ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, take)
+}
+
+// GetVariance godoc
+// @Summary      Preview stock take variance
+// @Description  Compare counted quantities with the stock levels they were counted against, flagging products whose stock changed since the session opened
+// @Tags         stock-takes
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "Stock take ID"
+// @Success      200  {object}  models.StockTakeVarianceReport
+// @Failure      404  {object}  ErrorResponse
+// @Failure      500  {object}  ErrorResponse
+// @Router       /stock-takes/{id}/variance [get]
+func (h *StockTakeHandler) GetVariance(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid stock take ID"})
+		return
+	}
+
+	report, err := h.service.GetVariance(uint(id))
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusInternalServerError), ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// CommitStockTake godoc
+// @Summary      Commit stock take
+// @Description  Apply the variance of every counted product as a stock adjustment with the "count" reason and close the session. Counted products whose stock moved after the session was opened (changedSinceOpen) are refused with 409 unless force is set.
+// @Tags         stock-takes
+// @Accept       json
+// @Produce      json
+// @Param        id      path      int                     true   "Stock take ID"
+// @Param        commit  body      CommitStockTakeRequest  false  "Commit information"
+// @Success      200     {object}  models.StockTakeVarianceReport
+// @Failure      404     {object}  ErrorResponse
+// @Failure      409     {object}  ErrorResponse
+// @Failure      500     {object}  ErrorResponse
+// @Router       /stock-takes/{id}/commit [post]
+func (h *StockTakeHandler) CommitStockTake(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid stock take ID"})
+		return
+	}
+
+	// The body is optional; it only carries who committed the count and
+	// whether to force it
+	var req CommitStockTakeRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid commit data"})
+			return
+		}
+	}
+
+	report, err := h.service.CommitStockTake(uint(id), req.Actor, req.Force)
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusInternalServerError), ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// CancelStockTake godoc
+// @Summary      Cancel stock take
+// @Description  Close an open stock take without changing any stock levels
+// @Tags         stock-takes
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "Stock take ID"
+// @Success      200  {object}  models.StockTake
+// @Failure      404  {object}  ErrorResponse
+// @Failure      409  {object}  ErrorResponse
+// @Failure      500  {object}  ErrorResponse
+// @Router       /stock-takes/{id}/cancel [post]
+func (h *StockTakeHandler) CancelStockTake(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid stock take ID"})
+		return
+	}
+
+	take, err := h.service.CancelStockTake(uint(id))
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusInternalServerError), ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, take)
+}
@@ -0,0 +1,154 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"phone-accessories/internal/models"
+	"phone-accessories/internal/service"
+)
+
+type SupplierHandler struct {
+	service service.SupplierService
+}
+
+func NewSupplierHandler(service service.SupplierService) *SupplierHandler {
+	return &SupplierHandler{service: service}
+}
+
+// ListSuppliers godoc
+// @Summary      List suppliers
+// @Description  Get all suppliers
+// @Tags         suppliers
+// @Accept       json
+// @Produce      json
+// @Success      200  {array}   models.Supplier
+// @Failure      500  {object}  ErrorResponse
+// @Router       /suppliers [get]
+func (h *SupplierHandler) ListSuppliers(c *gin.Context) {
+	suppliers, err := h.service.ListSuppliers()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, suppliers)
+}
+
+// GetSupplier godoc
+// @Summary      Get supplier by ID
+// @Description  Get detailed information about a supplier
+// @Tags         suppliers
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "Supplier ID"
+// @Success      200  {object}  models.Supplier
+// @Failure      404  {object}  ErrorResponse
+// @Failure      500  {object}  ErrorResponse
+// @Router       /suppliers/{id} [get]
+func (h *SupplierHandler) GetSupplier(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid supplier ID"})
+		return
+	}
+
+	supplier, err := h.service.GetSupplierByID(uint(id))
+	if err != nil {
+		c.JSON(http.StatusNotFound, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, supplier)
+}
+
+// CreateSupplier godoc
+// @Summary      Create supplier
+// @Description  Add a new supplier
+// @Tags         suppliers
+// @Accept       json
+// @Produce      json
+// @Param        supplier  body      models.Supplier  true  "Supplier information"
+// @Success      201       {object}  models.Supplier
+// @Failure      400       {object}  ErrorResponse
+// @Failure      500       {object}  ErrorResponse
+// @Router       /suppliers [post]
+func (h *SupplierHandler) CreateSupplier(c *gin.Context) {
+	var supplier models.Supplier
+	if err := c.ShouldBindJSON(&supplier); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid supplier data"})
+		return
+	}
+
+	if err := h.service.CreateSupplier(&supplier); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, supplier)
+}
+
+// UpdateSupplier godoc
+// @Summary      Update supplier
+// @Description  Update an existing supplier
+// @Tags         suppliers
+// @Accept       json
+// @Produce      json
+// @Param        id        path      int              true  "Supplier ID"
+// @Param        supplier  body      models.Supplier  true  "Supplier information"
+// @Success      200       {object}  models.Supplier
+// @Failure      400       {object}  ErrorResponse
+// @Failure      404       {object}  ErrorResponse
+// @Failure      500       {object}  ErrorResponse
+// @Router       /suppliers/{id} [put]
+func (h *SupplierHandler) UpdateSupplier(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid supplier ID"})
+		return
+	}
+
+	var supplier models.Supplier
+	if err := c.ShouldBindJSON(&supplier); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid supplier data"})
+		return
+	}
+
+	// Ensure the ID in the path matches the supplier
+	supplier.ID = uint(id)
+
+	if err := h.service.UpdateSupplier(&supplier); err != nil {
+		c.JSON(errorStatus(err, http.StatusBadRequest), ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, supplier)
+}
+
+// DeleteSupplier godoc
+// @Summary      Delete supplier
+// @Description  Delete an existing supplier
+// @Tags         suppliers
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "Supplier ID"
+// @Success      204  {object}  nil
+// @Failure      400  {object}  ErrorResponse
+// @Failure      500  {object}  ErrorResponse
+// @Router       /suppliers/{id} [delete]
+func (h *SupplierHandler) DeleteSupplier(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid supplier ID"})
+		return
+	}
+
+	if err := h.service.DeleteSupplier(uint(id)); err != nil {
+		c.JSON(errorStatus(err, http.StatusBadRequest), ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
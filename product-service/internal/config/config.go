@@ -4,6 +4,7 @@ package config
 import (
 	"os"
 	"strconv"
+	"time"
 )
 
 // Config holds all configuration for the application
@@ -21,6 +22,25 @@ type Config struct {
 	// Pagination defaults
 	DefaultPageSize int
 	MaxPageSize     int
+
+	// Stock reservation configuration
+	ReservationTTL           time.Duration
+	ReservationMaxTTL        time.Duration
+	ReservationSweepInterval time.Duration
+
+	// Low-stock notification configuration
+	LowStockWebhookURL     string
+	LowStockWebhookTimeout time.Duration
+	LowStockLogEnabled     bool
+
+	// Media upload configuration
+	MediaStorageDir    string
+	MediaBaseURL       string
+	MediaMaxUploadSize int64
+
+	// Tax configuration
+	DefaultTaxRate   float64
+	PricesIncludeTax bool
 }
 
 // NewConfig creates a new Config struct with values from environment variables
@@ -35,6 +55,19 @@ func NewConfig() *Config {
 		DBName:          "product_service",
 		DefaultPageSize: 20,
 		MaxPageSize:     100,
+
+		ReservationTTL:           15 * time.Minute,
+		ReservationMaxTTL:        2 * time.Hour,
+		ReservationSweepInterval: time.Minute,
+
+		LowStockWebhookTimeout: 5 * time.Second,
+		LowStockLogEnabled:     true,
+
+		MediaStorageDir:    "uploads",
+		MediaBaseURL:       "/media",
+		MediaMaxUploadSize: 5 << 20,
+
+		PricesIncludeTax: true,
 	}
 	
 	// Override with environment variables if they exist
@@ -74,5 +107,67 @@ func NewConfig() *Config {
 		}
 	}
 	
+	if ttlStr := os.Getenv("RESERVATION_TTL"); ttlStr != "" {
+		if ttl, err := time.ParseDuration(ttlStr); err == nil && ttl > 0 {
+			config.ReservationTTL = ttl
+		}
+	}
+
+	if maxTTLStr := os.Getenv("RESERVATION_MAX_TTL"); maxTTLStr != "" {
+		if maxTTL, err := time.ParseDuration(maxTTLStr); err == nil && maxTTL > 0 {
+			config.ReservationMaxTTL = maxTTL
+		}
+	}
+
+	if intervalStr := os.Getenv("RESERVATION_SWEEP_INTERVAL"); intervalStr != "" {
+		// time.NewTicker panics on an interval that is not positive, so keep the default
+		if interval, err := time.ParseDuration(intervalStr); err == nil && interval > 0 {
+			config.ReservationSweepInterval = interval
+		}
+	}
+
+	if webhookURL := os.Getenv("LOW_STOCK_WEBHOOK_URL"); webhookURL != "" {
+		config.LowStockWebhookURL = webhookURL
+	}
+
+	if timeoutStr := os.Getenv("LOW_STOCK_WEBHOOK_TIMEOUT"); timeoutStr != "" {
+		if timeout, err := time.ParseDuration(timeoutStr); err == nil {
+			config.LowStockWebhookTimeout = timeout
+		}
+	}
+
+	if logEnabledStr := os.Getenv("LOW_STOCK_LOG_ENABLED"); logEnabledStr != "" {
+		if logEnabled, err := strconv.ParseBool(logEnabledStr); err == nil {
+			config.LowStockLogEnabled = logEnabled
+		}
+	}
+
+	if mediaDir := os.Getenv("MEDIA_STORAGE_DIR"); mediaDir != "" {
+		config.MediaStorageDir = mediaDir
+	}
+
+	if mediaBaseURL := os.Getenv("MEDIA_BASE_URL"); mediaBaseURL != "" {
+		config.MediaBaseURL = mediaBaseURL
+	}
+
+	if maxSizeStr := os.Getenv("MEDIA_MAX_UPLOAD_SIZE"); maxSizeStr != "" {
+		if maxSize, err := strconv.ParseInt(maxSizeStr, 10, 64); err == nil {
+			config.MediaMaxUploadSize = maxSize
+		}
+	}
+
+	if taxRateStr := os.Getenv("DEFAULT_TAX_RATE"); taxRateStr != "" {
+		// Like category rates, the default must be at least 0 and below 100
+		if taxRate, err := strconv.ParseFloat(taxRateStr, 64); err == nil && taxRate >= 0 && taxRate < 100 {
+			config.DefaultTaxRate = taxRate
+		}
+	}
+
+	if includeTaxStr := os.Getenv("PRICES_INCLUDE_TAX"); includeTaxStr != "" {
+		if includeTax, err := strconv.ParseBool(includeTaxStr); err == nil {
+			config.PricesIncludeTax = includeTax
+		}
+	}
+
 	return config
 }
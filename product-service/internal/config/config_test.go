@@ -0,0 +1,81 @@
+// internal/config/config_test.go
+package config
+
+import (
+	"testing"
+	"time"
+)
+
+func TestReservationDurations(t *testing.T) {
+	tests := []struct {
+		name         string
+		ttl          string
+		maxTTL       string
+		interval     string
+		wantTTL      time.Duration
+		wantMaxTTL   time.Duration
+		wantInterval time.Duration
+	}{
+		{
+			name:         "defaults",
+			wantTTL:      15 * time.Minute,
+			wantMaxTTL:   2 * time.Hour,
+			wantInterval: time.Minute,
+		},
+		{
+			name:         "positive values",
+			ttl:          "5m",
+			maxTTL:       "1h",
+			interval:     "30s",
+			wantTTL:      5 * time.Minute,
+			wantMaxTTL:   time.Hour,
+			wantInterval: 30 * time.Second,
+		},
+		{
+			name:         "zero keeps the defaults",
+			ttl:          "0s",
+			maxTTL:       "0",
+			interval:     "0s",
+			wantTTL:      15 * time.Minute,
+			wantMaxTTL:   2 * time.Hour,
+			wantInterval: time.Minute,
+		},
+		{
+			name:         "negative keeps the defaults",
+			ttl:          "-5m",
+			maxTTL:       "-1h",
+			interval:     "-1s",
+			wantTTL:      15 * time.Minute,
+			wantMaxTTL:   2 * time.Hour,
+			wantInterval: time.Minute,
+		},
+		{
+			name:         "unreadable keeps the defaults",
+			ttl:          "soon",
+			maxTTL:       "later",
+			interval:     "often",
+			wantTTL:      15 * time.Minute,
+			wantMaxTTL:   2 * time.Hour,
+			wantInterval: time.Minute,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("RESERVATION_TTL", tt.ttl)
+			t.Setenv("RESERVATION_MAX_TTL", tt.maxTTL)
+			t.Setenv("RESERVATION_SWEEP_INTERVAL", tt.interval)
+
+			config := NewConfig()
+			if config.ReservationTTL != tt.wantTTL {
+				t.Errorf("ReservationTTL = %s, want %s", config.ReservationTTL, tt.wantTTL)
+			}
+			if config.ReservationMaxTTL != tt.wantMaxTTL {
+				t.Errorf("ReservationMaxTTL = %s, want %s", config.ReservationMaxTTL, tt.wantMaxTTL)
+			}
+			if config.ReservationSweepInterval != tt.wantInterval {
+				t.Errorf("ReservationSweepInterval = %s, want %s", config.ReservationSweepInterval, tt.wantInterval)
+			}
+		})
+	}
+}
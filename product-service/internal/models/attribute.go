@@ -0,0 +1,96 @@
+// internal/models/attribute.go
+package models
+
+import (
+	"fmt"
+	"regexp"
+	"time"
+)
+
+// AttributeKeyPattern keeps attribute keys usable as JSON keys and in query
+// strings without escaping
+var AttributeKeyPattern = regexp.MustCompile(`^[a-z][a-z0-9_]{0,49}$`)
+
+// AttributeType is the kind of value a product attribute holds
+type AttributeType string
+
+const (
+	AttributeTypeString AttributeType = "string"
+	AttributeTypeNumber AttributeType = "number"
+	AttributeTypeBool   AttributeType = "bool"
+	// AttributeTypeEnum holds one of the definition's allowed values
+	AttributeTypeEnum AttributeType = "enum"
+	// AttributeTypeList holds a list of strings, limited to the allowed values
+	// when the definition has any
+	AttributeTypeList AttributeType = "list"
+)
+
+// IsValid reports whether t is one of the known attribute types
+func (t AttributeType) IsValid() bool {
+	switch t {
+	case AttributeTypeString, AttributeTypeNumber, AttributeTypeBool, AttributeTypeEnum, AttributeTypeList:
+		return true
+	}
+	return false
+}
+
+// AttributeDefinition describes a product attribute for the products of a
+// category and of all its subcategories. A subcategory can redefine a key to
+// override the definition it inherits.
+type AttributeDefinition struct {
+	ID            uint          `json:"id" gorm:"primaryKey"`
+	CategoryID    uint          `json:"categoryId" gorm:"not null;uniqueIndex:idx_attribute_definitions_category_key,priority:1"`
+	Key           string        `json:"key" gorm:"size:50;not null;uniqueIndex:idx_attribute_definitions_category_key,priority:2"`
+	Label         string        `json:"label" gorm:"size:100;not null"`
+	Type          AttributeType `json:"type" gorm:"size:20;not null"`
+	Unit          string        `json:"unit" gorm:"size:20"`
+	Required      bool          `json:"required" gorm:"not null;default:false"`
+	AllowedValues StringList    `json:"allowedValues" gorm:"type:jsonb"`
+	Position      int           `json:"position" gorm:"not null;default:0"`
+	CreatedAt     time.Time     `json:"createdAt"`
+	UpdatedAt     time.Time     `json:"updatedAt"`
+}
+
+// AttributeFieldError describes why a single product attribute is invalid
+type AttributeFieldError struct {
+	Field string `json:"field"`
+	Error string `json:"error"`
+}
+
+// AttributeValidationError is returned when a product's attributes do not
+// match the attribute schema of its category
+type AttributeValidationError struct {
+	Fields []AttributeFieldError
+}
+
+func (e *AttributeValidationError) Error() string {
+	return fmt.Sprintf("%d product attributes are invalid", len(e.Fields))
+}
+
+// Is makes errors.Is(err, ErrInvalidAttributes) match an AttributeValidationError
+func (e *AttributeValidationError) Is(target error) bool {
+	return target == ErrInvalidAttributes
+}
+
+// AttributeOperator compares a product attribute with a filter value
+type AttributeOperator string
+
+const (
+	// AttributeOperatorEq matches any of the filter's values
+	AttributeOperatorEq  AttributeOperator = "eq"
+	AttributeOperatorGt  AttributeOperator = "gt"
+	AttributeOperatorGte AttributeOperator = "gte"
+	AttributeOperatorLt  AttributeOperator = "lt"
+	AttributeOperatorLte AttributeOperator = "lte"
+)
+
+// AttributeFilter narrows a product list down by one attribute, given in the
+// query string as attr.color=Noir,Bleu or attr.power_w[gte]=20
+type AttributeFilter struct {
+	Key      string
+	Operator AttributeOperator
+	// Values holds the accepted values of an equality filter
+	Values []string
+	// Number is the bound of a range filter
+	Number float64
+}
@@ -0,0 +1,22 @@
+// internal/models/brand.go
+package models
+
+import "time"
+
+// Brand is a manufacturer, both of the accessories we sell (Anker, Belkin, our
+// house brand) and of the devices they fit (Apple, Samsung)
+type Brand struct {
+	ID          uint      `json:"id" gorm:"primaryKey"`
+	Name        string    `json:"name" gorm:"size:100;not null;uniqueIndex"`
+	Description string    `json:"description" gorm:"type:text"`
+	LogoURL     string    `json:"logoUrl" gorm:"size:255"`
+	IsActive    bool      `json:"isActive" gorm:"default:true"`
+	CreatedAt   time.Time `json:"createdAt"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+}
+
+// BrandFilter represents the filter options for brands
+type BrandFilter struct {
+	Active *bool  `form:"active"`
+	Query  string `form:"q"`
+}
@@ -0,0 +1,69 @@
+// internal/models/bundle.go
+package models
+
+import "time"
+
+// BundleComponent is a product sold as part of a bundle, such as the case in a
+// starter kit, and the number of units of it that go into one bundle
+type BundleComponent struct {
+	ID          uint      `json:"id" gorm:"primaryKey"`
+	BundleID    uint      `json:"bundleId" gorm:"not null;uniqueIndex:idx_bundle_components_bundle_component"`
+	ComponentID uint      `json:"componentId" gorm:"not null;uniqueIndex:idx_bundle_components_bundle_component;index"`
+	Component   *Product  `json:"component,omitempty" gorm:"foreignKey:ComponentID"`
+	Quantity    int       `json:"quantity" gorm:"not null"`
+	CreatedAt   time.Time `json:"createdAt"`
+}
+
+// DeriveBundleAvailability computes a bundle's availability from its
+// components, which must have their Component loaded. A bundle holds no stock
+// of its own: it is available as many times as its scarcest component allows,
+// and can only be sold ahead of stock when every component can.
+func (p *Product) DeriveBundleAvailability(components []BundleComponent) {
+	p.AvailableQuantity = 0
+	p.AvailabilityStatus = AvailabilityStatusOutOfStock
+	p.ExpectedAvailableAt = nil
+	if len(components) == 0 {
+		return
+	}
+
+	available := -1
+	sellsAhead := true
+	preorder := false
+	for _, component := range components {
+		if component.Component == nil || component.Quantity <= 0 {
+			return
+		}
+		units := component.Component.AvailableQuantity / component.Quantity
+		if units < 0 {
+			units = 0
+		}
+		if available < 0 || units < available {
+			available = units
+		}
+
+		if !component.Component.CanSell(component.Quantity) {
+			sellsAhead = false
+		}
+		if component.Component.AvailabilityStatus == AvailabilityStatusPreorder {
+			preorder = true
+			// The bundle ships once its last component does
+			shipDate := component.Component.ExpectedShipDate
+			if shipDate != nil && (p.ExpectedAvailableAt == nil || shipDate.After(*p.ExpectedAvailableAt)) {
+				p.ExpectedAvailableAt = shipDate
+			}
+		}
+	}
+
+	p.AvailableQuantity = available
+	switch {
+	case available > 0:
+		p.AvailabilityStatus = AvailabilityStatusInStock
+		p.ExpectedAvailableAt = nil
+	case !sellsAhead:
+		p.ExpectedAvailableAt = nil
+	case preorder:
+		p.AvailabilityStatus = AvailabilityStatusPreorder
+	default:
+		p.AvailabilityStatus = AvailabilityStatusBackorder
+	}
+}
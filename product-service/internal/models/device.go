@@ -0,0 +1,30 @@
+// internal/models/device.go
+package models
+
+import "time"
+
+// DeviceModel is a phone or tablet model that accessories can be compatible with
+type DeviceModel struct {
+	ID          uint      `json:"id" gorm:"primaryKey"`
+	BrandID     uint      `json:"brandId" gorm:"not null;uniqueIndex:idx_device_models_brand_name,priority:1"`
+	Brand       *Brand    `json:"brand,omitempty" gorm:"foreignKey:BrandID"`
+	Name        string    `json:"name" gorm:"size:100;not null;uniqueIndex:idx_device_models_brand_name,priority:2"`
+	ReleaseYear *int      `json:"releaseYear"`
+	CreatedAt   time.Time `json:"createdAt"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+}
+
+// DeviceFilter represents the filter options for device models
+type DeviceFilter struct {
+	BrandID  *uint  `form:"brandId"`
+	Query    string `form:"q"`
+	Page     int    `form:"page,default=1"`
+	PageSize int    `form:"pageSize,default=20"`
+}
+
+// CompatibilityValue is a free-text compatible attribute recorded on a product
+// before devices were modelled
+type CompatibilityValue struct {
+	ProductID uint
+	Value     string
+}
@@ -0,0 +1,218 @@
+// internal/models/discount.go
+package models
+
+import (
+	"math/big"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// DiscountType says how a discount rule takes money off a cart
+type DiscountType string
+
+const (
+	// DiscountTypePercentage takes Percentage percent off the matching lines
+	DiscountTypePercentage DiscountType = "percentage"
+	// DiscountTypeFixed takes Value off the matching lines as a whole
+	DiscountTypeFixed DiscountType = "fixed"
+	// DiscountTypeBuyXGetY gives GetQuantity units free for every BuyQuantity
+	// units bought among the matching lines, the cheapest units being free
+	DiscountTypeBuyXGetY DiscountType = "buy_x_get_y"
+)
+
+// IsValid reports whether t is one of the known discount types
+func (t DiscountType) IsValid() bool {
+	switch t {
+	case DiscountTypePercentage, DiscountTypeFixed, DiscountTypeBuyXGetY:
+		return true
+	}
+	return false
+}
+
+// DiscountScope says which cart lines a discount rule applies to
+type DiscountScope string
+
+const (
+	// DiscountScopeCart applies to every line of the cart
+	DiscountScopeCart DiscountScope = "cart"
+	// DiscountScopeProduct applies to a product and its variants
+	DiscountScopeProduct DiscountScope = "product"
+	// DiscountScopeCategory applies to the products of a category and of its subcategories
+	DiscountScopeCategory DiscountScope = "category"
+	// DiscountScopeBrand applies to the products of a brand
+	DiscountScopeBrand DiscountScope = "brand"
+)
+
+// IsValid reports whether s is one of the known discount scopes
+func (s DiscountScope) IsValid() bool {
+	switch s {
+	case DiscountScopeCart, DiscountScopeProduct, DiscountScopeCategory, DiscountScopeBrand:
+		return true
+	}
+	return false
+}
+
+// DiscountRule takes money off carts while it is valid and has uses left.
+// Rules with a coupon code only apply when the code is given; the others
+// apply to every cart they match. Rules are applied by priority, highest
+// first, then oldest first, each to what the previous ones left to pay.
+type DiscountRule struct {
+	ID         uint          `json:"id" gorm:"primaryKey"`
+	Name       string        `json:"name" gorm:"size:100;not null"`
+	Type       DiscountType  `json:"type" gorm:"size:20;not null"`
+	Scope      DiscountScope `json:"scope" gorm:"size:20;not null"`
+	TargetID   *uint         `json:"targetId"`
+	Percentage float64       `json:"percentage" gorm:"type:numeric(5,2);not null;default:0"`
+	// Value is the amount off of a fixed discount
+	Value       Money `json:"value" gorm:"embedded;embeddedPrefix:value_"`
+	BuyQuantity int   `json:"buyQuantity" gorm:"not null;default:0"`
+	GetQuantity int   `json:"getQuantity" gorm:"not null;default:0"`
+	// MinBasket is the cart subtotal needed for the rule to apply; zero means none
+	MinBasket  Money      `json:"minBasket" gorm:"embedded;embeddedPrefix:min_basket_"`
+	CouponCode *string    `json:"couponCode" gorm:"size:50;uniqueIndex"`
+	Priority   int        `json:"priority" gorm:"not null;default:0"`
+	StartsAt   time.Time  `json:"startsAt" gorm:"not null"`
+	EndsAt     *time.Time `json:"endsAt"`
+	// UsageLimit caps how many orders may redeem the rule; nil means no cap
+	UsageLimit *int      `json:"usageLimit"`
+	UsageCount int       `json:"usageCount" gorm:"not null;default:0"`
+	IsActive   bool      `json:"isActive" gorm:"default:true"`
+	CreatedAt  time.Time `json:"createdAt"`
+	UpdatedAt  time.Time `json:"updatedAt"`
+}
+
+// DiscountRuleFilter represents the filter options for discount rules
+type DiscountRuleFilter struct {
+	Active *bool `form:"active"`
+	// Current only lists rules valid right now with uses left
+	Current bool `form:"current"`
+}
+
+// IsRunning reports whether the rule is active, within its validity window
+// and has uses left at the given moment
+func (r *DiscountRule) IsRunning(now time.Time) bool {
+	return r.IsActive && !r.StartsAt.After(now) && (r.EndsAt == nil || r.EndsAt.After(now)) &&
+		(r.UsageLimit == nil || r.UsageCount < *r.UsageLimit)
+}
+
+// DiscountRedemption records that an order used a discount rule
+type DiscountRedemption struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	RuleID    uint      `json:"ruleId" gorm:"not null;uniqueIndex:idx_discount_redemptions_rule_reference"`
+	Reference string    `json:"reference" gorm:"size:100;not null;uniqueIndex:idx_discount_redemptions_rule_reference;index"`
+	Amount    Money     `json:"amount" gorm:"embedded;embeddedPrefix:amount_"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// QuoteRedemption keeps the quote an order was charged under the order's
+// reference, so that redeeming the reference again returns the same quote
+type QuoteRedemption struct {
+	ID        uint   `gorm:"primaryKey"`
+	Reference string `gorm:"size:100;not null;uniqueIndex"`
+	Quote     Quote  `gorm:"type:jsonb;serializer:json;not null"`
+	CreatedAt time.Time
+}
+
+// AppliedDiscount is the money a discount rule took off a quote, or off one
+// of its lines
+type AppliedDiscount struct {
+	RuleID     uint         `json:"ruleId"`
+	Name       string       `json:"name"`
+	Type       DiscountType `json:"type"`
+	CouponCode string       `json:"couponCode,omitempty"`
+	Amount     Money        `json:"amount"`
+}
+
+// DiscountableLine is a cart line a discount rule matches: its unit price,
+// quantity and what is left to pay on it after the rules applied before, all
+// in minor units
+type DiscountableLine struct {
+	UnitPrice int64
+	Quantity  int
+	Remaining int64
+}
+
+// Allocate works out what the rule takes off each of the lines it matches,
+// never more than is left to pay on a line. A percentage is taken off each
+// line and rounded there; a fixed amount is spread over the lines in
+// proportion to what is left on them; buy X get Y frees the last Y units of
+// every X+Y, counting the dearest units first so that the cheapest are free.
+func (r *DiscountRule) Allocate(lines []DiscountableLine) []int64 {
+	amounts := make([]int64, len(lines))
+	switch r.Type {
+	case DiscountTypePercentage:
+		percentage, ok := new(big.Rat).SetString(strconv.FormatFloat(r.Percentage, 'f', -1, 64))
+		if !ok {
+			return amounts
+		}
+		percentage.Quo(percentage, big.NewRat(100, 1))
+		for i, line := range lines {
+			amounts[i] = roundHalfAwayFromZero(new(big.Rat).Mul(percentage, new(big.Rat).SetInt64(line.Remaining)))
+		}
+	case DiscountTypeFixed:
+		var remaining int64
+		for _, line := range lines {
+			remaining += line.Remaining
+		}
+		if remaining <= 0 {
+			return amounts
+		}
+		total := r.Value.Amount
+		if total > remaining {
+			total = remaining
+		}
+		// Largest remainder: share out the whole amounts, then one minor unit
+		// each to the lines with the largest fractions left over
+		remainders := make([]int64, len(lines))
+		allocated := int64(0)
+		for i, line := range lines {
+			share := new(big.Int).Mul(big.NewInt(total), big.NewInt(line.Remaining))
+			quotient, remainder := share.QuoRem(share, big.NewInt(remaining), new(big.Int))
+			amounts[i] = quotient.Int64()
+			remainders[i] = remainder.Int64()
+			allocated += amounts[i]
+		}
+		order := make([]int, len(lines))
+		for i := range order {
+			order[i] = i
+		}
+		sort.SliceStable(order, func(a, b int) bool { return remainders[order[a]] > remainders[order[b]] })
+		for _, i := range order {
+			if allocated >= total {
+				break
+			}
+			amounts[i]++
+			allocated++
+		}
+	case DiscountTypeBuyXGetY:
+		if r.BuyQuantity < 1 || r.GetQuantity < 1 {
+			return amounts
+		}
+		order := make([]int, len(lines))
+		for i := range order {
+			order[i] = i
+		}
+		sort.SliceStable(order, func(a, b int) bool { return lines[order[a]].UnitPrice > lines[order[b]].UnitPrice })
+		block := r.BuyQuantity + r.GetQuantity
+		unit := 0
+		for _, i := range order {
+			for n := 0; n < lines[i].Quantity; n++ {
+				if unit%block >= r.BuyQuantity {
+					amounts[i] += lines[i].UnitPrice
+				}
+				unit++
+			}
+		}
+	}
+
+	for i, line := range lines {
+		if amounts[i] > line.Remaining {
+			amounts[i] = line.Remaining
+		}
+		if amounts[i] < 0 {
+			amounts[i] = 0
+		}
+	}
+	return amounts
+}
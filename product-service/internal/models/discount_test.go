@@ -0,0 +1,142 @@
+// internal/models/discount_test.go
+package models
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDiscountRuleAllocate(t *testing.T) {
+	tests := []struct {
+		name  string
+		rule  DiscountRule
+		lines []DiscountableLine
+		want  []int64
+	}{
+		{
+			name: "percentage rounds each line",
+			rule: DiscountRule{Type: DiscountTypePercentage, Percentage: 10},
+			lines: []DiscountableLine{
+				{UnitPrice: 1999, Quantity: 1, Remaining: 1999},
+				{UnitPrice: 15, Quantity: 3, Remaining: 45},
+			},
+			want: []int64{200, 5},
+		},
+		{
+			name: "percentage of what is left to pay",
+			rule: DiscountRule{Type: DiscountTypePercentage, Percentage: 50},
+			lines: []DiscountableLine{
+				{UnitPrice: 1000, Quantity: 2, Remaining: 1500},
+			},
+			want: []int64{750},
+		},
+		{
+			name: "fixed in proportion to what is left",
+			rule: DiscountRule{Type: DiscountTypeFixed, Value: NewMoney(1000, "EUR")},
+			lines: []DiscountableLine{
+				{UnitPrice: 3000, Quantity: 1, Remaining: 3000},
+				{UnitPrice: 1000, Quantity: 1, Remaining: 1000},
+			},
+			want: []int64{750, 250},
+		},
+		{
+			name: "fixed gives the leftover cents to the largest remainders",
+			rule: DiscountRule{Type: DiscountTypeFixed, Value: NewMoney(100, "EUR")},
+			lines: []DiscountableLine{
+				{UnitPrice: 100, Quantity: 1, Remaining: 100},
+				{UnitPrice: 100, Quantity: 1, Remaining: 100},
+				{UnitPrice: 100, Quantity: 1, Remaining: 100},
+			},
+			want: []int64{34, 33, 33},
+		},
+		{
+			name: "fixed leftover goes to the largest fraction, not the first line",
+			rule: DiscountRule{Type: DiscountTypeFixed, Value: NewMoney(10, "EUR")},
+			lines: []DiscountableLine{
+				{UnitPrice: 10, Quantity: 1, Remaining: 10},
+				{UnitPrice: 25, Quantity: 1, Remaining: 25},
+				{UnitPrice: 65, Quantity: 1, Remaining: 65},
+			},
+			// Exact shares are 1.0, 2.5 and 6.5; the two halves tie and the
+			// earlier line gets the last cent
+			want: []int64{1, 3, 6},
+		},
+		{
+			name: "fixed never takes off more than is left",
+			rule: DiscountRule{Type: DiscountTypeFixed, Value: NewMoney(5000, "EUR")},
+			lines: []DiscountableLine{
+				{UnitPrice: 1000, Quantity: 1, Remaining: 1000},
+				{UnitPrice: 500, Quantity: 1, Remaining: 500},
+			},
+			want: []int64{1000, 500},
+		},
+		{
+			name: "fixed with nothing left to pay",
+			rule: DiscountRule{Type: DiscountTypeFixed, Value: NewMoney(500, "EUR")},
+			lines: []DiscountableLine{
+				{UnitPrice: 1000, Quantity: 1, Remaining: 0},
+			},
+			want: []int64{0},
+		},
+		{
+			name: "buy two get one frees every third unit",
+			rule: DiscountRule{Type: DiscountTypeBuyXGetY, BuyQuantity: 2, GetQuantity: 1},
+			lines: []DiscountableLine{
+				{UnitPrice: 500, Quantity: 7, Remaining: 3500},
+			},
+			want: []int64{1000},
+		},
+		{
+			name: "buy two get one frees the cheapest units",
+			rule: DiscountRule{Type: DiscountTypeBuyXGetY, BuyQuantity: 2, GetQuantity: 1},
+			lines: []DiscountableLine{
+				{UnitPrice: 300, Quantity: 1, Remaining: 300},
+				{UnitPrice: 1000, Quantity: 2, Remaining: 2000},
+			},
+			want: []int64{300, 0},
+		},
+		{
+			name: "buy one get one across lines",
+			rule: DiscountRule{Type: DiscountTypeBuyXGetY, BuyQuantity: 1, GetQuantity: 1},
+			lines: []DiscountableLine{
+				{UnitPrice: 800, Quantity: 1, Remaining: 800},
+				{UnitPrice: 1200, Quantity: 2, Remaining: 2400},
+				{UnitPrice: 500, Quantity: 1, Remaining: 500},
+			},
+			// Dearest first: 1200 paid, 1200 free, 800 paid, 500 free
+			want: []int64{0, 1200, 500},
+		},
+		{
+			name: "buy X get Y capped at what is left",
+			rule: DiscountRule{Type: DiscountTypeBuyXGetY, BuyQuantity: 1, GetQuantity: 1},
+			lines: []DiscountableLine{
+				{UnitPrice: 1000, Quantity: 2, Remaining: 600},
+			},
+			want: []int64{600},
+		},
+		{
+			name: "buy X get Y not reached",
+			rule: DiscountRule{Type: DiscountTypeBuyXGetY, BuyQuantity: 3, GetQuantity: 1},
+			lines: []DiscountableLine{
+				{UnitPrice: 1000, Quantity: 3, Remaining: 3000},
+			},
+			want: []int64{0},
+		},
+		{
+			name: "buy X get Y without quantities",
+			rule: DiscountRule{Type: DiscountTypeBuyXGetY},
+			lines: []DiscountableLine{
+				{UnitPrice: 1000, Quantity: 4, Remaining: 4000},
+			},
+			want: []int64{0},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.rule.Allocate(tt.lines); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Allocate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
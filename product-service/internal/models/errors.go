@@ -0,0 +1,171 @@
+// internal/models/errors.go
+package models
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrProductNotFound is returned when a product does not exist
+	ErrProductNotFound = errors.New("product not found")
+
+	// ErrCategoryNotFound is returned when a category does not exist
+	ErrCategoryNotFound = errors.New("category not found")
+
+	// ErrCategoryCycle is returned when re-parenting a category would make it
+	// its own ancestor
+	ErrCategoryCycle = errors.New("a category cannot be moved under itself or one of its subcategories")
+
+	// ErrAttributeNotFound is returned when an attribute definition does not exist
+	ErrAttributeNotFound = errors.New("attribute definition not found")
+
+	// ErrInvalidAttributes is returned when a product's attributes do not match
+	// its category's attribute schema
+	ErrInvalidAttributes = errors.New("product attributes are invalid")
+
+	// ErrProductHasVariants is returned when changing the stock of a parent
+	// product, whose stock is tracked on its variants
+	ErrProductHasVariants = errors.New("stock is tracked on the product's variants")
+
+	// ErrProductIsBundle is returned when changing the stock of a bundle other
+	// than by selling it, since its stock is that of its components
+	ErrProductIsBundle = errors.New("bundles hold no stock of their own; change the stock of their components")
+
+	// ErrProductInBundle is returned when deleting a product that is still a
+	// component of a bundle
+	ErrProductInBundle = errors.New("product is a component of a bundle; remove it from the bundle first")
+
+	// ErrRelationNotFound is returned when a product relation does not exist
+	ErrRelationNotFound = errors.New("product relation not found")
+
+	// ErrRelationExists is returned when a product is already related to the
+	// target in the same way
+	ErrRelationExists = errors.New("product is already related to the target with this type")
+
+	// ErrPriceListNotFound is returned when a price list does not exist
+	ErrPriceListNotFound = errors.New("price list not found")
+
+	// ErrProductPriceNotFound is returned when a product has no price in a price list
+	ErrProductPriceNotFound = errors.New("product has no price in this price list")
+
+	// ErrExchangeRateNotFound is returned when an exchange rate does not exist
+	ErrExchangeRateNotFound = errors.New("exchange rate not found")
+
+	// ErrExchangeRateExists is returned when a currency pair already has a
+	// rate taking effect on the same date
+	ErrExchangeRateExists = errors.New("an exchange rate for this currency pair already takes effect on this date")
+
+	// ErrInvalidPriceQuery is returned when prices are asked for in an unknown
+	// currency, an inactive price list or with unreadable price bounds
+	ErrInvalidPriceQuery = errors.New("invalid price query")
+
+	// ErrPromotionNotFound is returned when a promotion does not exist
+	ErrPromotionNotFound = errors.New("promotion not found")
+
+	// ErrDiscountRuleNotFound is returned when a discount rule does not exist
+	ErrDiscountRuleNotFound = errors.New("discount rule not found")
+
+	// ErrCouponCodeExists is returned when another discount rule has the coupon code
+	ErrCouponCodeExists = errors.New("coupon code already in use")
+
+	// ErrCouponInvalid is returned when a coupon code is unknown, expired or used up
+	ErrCouponInvalid = errors.New("coupon code is not valid")
+
+	// ErrCouponNotApplicable is returned when a valid coupon takes nothing off
+	// the cart, such as when the basket is below its minimum
+	ErrCouponNotApplicable = errors.New("coupon does not apply to this cart")
+
+	// ErrDiscountExhausted is returned when redeeming a discount rule that
+	// reached its usage limit since the cart was quoted
+	ErrDiscountExhausted = errors.New("discount has reached its usage limit")
+
+	// ErrDiscountAlreadyRedeemed is returned when an order reference has
+	// already redeemed its discounts
+	ErrDiscountAlreadyRedeemed = errors.New("discounts have already been redeemed for this reference")
+
+	// ErrMediaNotFound is returned when a product media item does not exist
+	ErrMediaNotFound = errors.New("media not found")
+
+	// ErrUnsupportedMediaType is returned when an upload is not an accepted image format
+	ErrUnsupportedMediaType = errors.New("unsupported media type")
+
+	// ErrMediaTooLarge is returned when an upload exceeds the configured size limit
+	ErrMediaTooLarge = errors.New("media file is too large")
+
+	// ErrInsufficientStock is returned when a product does not have enough
+	// available stock to satisfy a request. Stock mutations report it through
+	// InsufficientStockError, which matches it with errors.Is.
+	ErrInsufficientStock = errors.New("insufficient stock")
+
+	// ErrVersionConflict is returned when an update was based on a stale
+	// version of a record that has since been modified
+	ErrVersionConflict = errors.New("record has been modified by another request")
+
+	// ErrLocationNotFound is returned when a stock location does not exist
+	ErrLocationNotFound = errors.New("location not found")
+
+	// ErrLocationInactive is returned when stock is moved to or from an
+	// inactive location
+	ErrLocationInactive = errors.New("location is not active")
+
+	// ErrBrandNotFound is returned when a brand does not exist
+	ErrBrandNotFound = errors.New("brand not found")
+
+	// ErrDeviceNotFound is returned when a device model does not exist
+	ErrDeviceNotFound = errors.New("device not found")
+
+	// ErrBrandInUse is returned when deleting a brand that still has device
+	// models or products
+	ErrBrandInUse = errors.New("cannot delete a brand that still has device models or products; deactivate it instead")
+
+	// ErrSupplierNotFound is returned when a supplier does not exist
+	ErrSupplierNotFound = errors.New("supplier not found")
+
+	// ErrPurchaseOrderNotFound is returned when a purchase order does not exist
+	ErrPurchaseOrderNotFound = errors.New("purchase order not found")
+
+	// ErrPurchaseOrderStatus is returned when a purchase order is not in a
+	// status that allows the requested operation
+	ErrPurchaseOrderStatus = errors.New("operation not allowed in the purchase order's current status")
+
+	// ErrStockTakeNotFound is returned when a stock take does not exist
+	ErrStockTakeNotFound = errors.New("stock take not found")
+
+	// ErrStockTakeClosed is returned when counting, committing or cancelling
+	// a stock take that is no longer open
+	ErrStockTakeClosed = errors.New("stock take is no longer open")
+
+	// ErrStockTakeChanged is returned when committing a stock take whose
+	// counted products moved after it was opened, without forcing the commit
+	ErrStockTakeChanged = errors.New("stock moved since the stock take was opened")
+
+	// ErrReservationNotFound is returned when a reservation does not exist
+	ErrReservationNotFound = errors.New("reservation not found")
+
+	// ErrReservationNotHeld is returned when a reservation is no longer held
+	// and can therefore not be confirmed or released
+	ErrReservationNotHeld = errors.New("reservation is no longer held")
+
+	// ErrReservationExpired is returned when confirming a reservation whose
+	// TTL has elapsed
+	ErrReservationExpired = errors.New("reservation has expired")
+)
+
+// InsufficientStockError reports a stock change that would leave a product with
+// less stock than it requires
+type InsufficientStockError struct {
+	ProductID uint
+	Requested int
+	Available int
+}
+
+func (e *InsufficientStockError) Error() string {
+	return fmt.Sprintf("insufficient stock for product %d: requested %d, available %d",
+		e.ProductID, e.Requested, e.Available)
+}
+
+// Is makes errors.Is(err, ErrInsufficientStock) match an InsufficientStockError
+func (e *InsufficientStockError) Is(target error) bool {
+	return target == ErrInsufficientStock
+}
@@ -0,0 +1,102 @@
+// internal/models/inventory.go
+package models
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// LowStockEvent is emitted when a stock change takes a product below its reorder point
+type LowStockEvent struct {
+	ProductID       uint      `json:"productId"`
+	SKU             string    `json:"sku"`
+	Name            string    `json:"name"`
+	StockLevel      int       `json:"stockLevel"`
+	PreviousLevel   int       `json:"previousLevel"`
+	ReorderPoint    int       `json:"reorderPoint"`
+	ReorderQuantity int       `json:"reorderQuantity"`
+	OccurredAt      time.Time `json:"occurredAt"`
+}
+
+// LowStockItem is a product under its reorder point with the quantity to reorder
+type LowStockItem struct {
+	ProductID         uint   `json:"productId"`
+	SKU               string `json:"sku"`
+	Name              string `json:"name"`
+	StockLevel        int    `json:"stockLevel"`
+	AvailableQuantity int    `json:"availableQuantity"`
+	ReorderPoint      int    `json:"reorderPoint"`
+	ReorderQuantity   int    `json:"reorderQuantity"`
+	IncomingQuantity  int    `json:"incomingQuantity"`
+	SuggestedQuantity int    `json:"suggestedQuantity"`
+}
+
+// SuggestedReorderQuantity returns how many units to order for a product so
+// that, together with what is already incoming on open purchase orders, it is
+// at least back at its reorder point
+func SuggestedReorderQuantity(product Product) int {
+	shortfall := product.ReorderPoint - product.StockLevel - product.IncomingQuantity
+	if shortfall <= 0 {
+		return 0
+	}
+	if product.ReorderQuantity > shortfall {
+		return product.ReorderQuantity
+	}
+	return shortfall
+}
+
+// StockAdjustmentLine is one line of a batch stock adjustment. The product is
+// identified by ProductID, by SKU, or by both when they agree.
+type StockAdjustmentLine struct {
+	ProductID  uint        `json:"productId"`
+	SKU        string      `json:"sku"`
+	LocationID uint        `json:"locationId"`
+	Delta      int         `json:"delta"`
+	Reason     StockReason `json:"reason"`
+}
+
+// Validate checks the fields of a line that need no lookup
+func (l *StockAdjustmentLine) Validate() error {
+	switch {
+	case l.ProductID == 0 && l.SKU == "":
+		return errors.New("product ID or SKU is required")
+	case l.Delta == 0:
+		return errors.New("delta must not be zero")
+	case !l.Reason.IsValid():
+		return fmt.Errorf("invalid stock change reason %q", l.Reason)
+	case !l.Reason.IsPublic():
+		return fmt.Errorf("stock change reason %q is only used by transfers and stock takes", l.Reason)
+	}
+	return nil
+}
+
+// StockAdjustmentResult is the outcome of a single applied adjustment line
+type StockAdjustmentResult struct {
+	Line           int    `json:"line"`
+	ProductID      uint   `json:"productId"`
+	SKU            string `json:"sku"`
+	LocationID     uint   `json:"locationId"`
+	Delta          int    `json:"delta"`
+	ResultingLevel int    `json:"resultingLevel"`
+	LocationLevel  int    `json:"locationLevel"`
+	MovementID     uint   `json:"movementId"`
+}
+
+// StockAdjustmentLineError describes why a line of a batch adjustment was rejected
+type StockAdjustmentLineError struct {
+	Line      int    `json:"line"`
+	ProductID uint   `json:"productId,omitempty"`
+	SKU       string `json:"sku,omitempty"`
+	Error     string `json:"error"`
+}
+
+// StockAdjustmentError is returned when one or more lines of a batch
+// adjustment are invalid, in which case none of the lines are applied
+type StockAdjustmentError struct {
+	Lines []StockAdjustmentLineError
+}
+
+func (e *StockAdjustmentError) Error() string {
+	return fmt.Sprintf("%d of the stock adjustment lines are invalid; no changes were applied", len(e.Lines))
+}
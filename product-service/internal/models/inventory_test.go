@@ -0,0 +1,28 @@
+// internal/models/inventory_test.go
+package models
+
+import "testing"
+
+func TestStockAdjustmentLineValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		line    StockAdjustmentLine
+		wantErr bool
+	}{
+		{name: "by product ID", line: StockAdjustmentLine{ProductID: 1, Delta: 2, Reason: StockReasonRestock}},
+		{name: "by SKU", line: StockAdjustmentLine{SKU: "CASE-1", Delta: -1, Reason: StockReasonDamage}},
+		{name: "no product", line: StockAdjustmentLine{Delta: 1, Reason: StockReasonAdjustment}, wantErr: true},
+		{name: "zero delta", line: StockAdjustmentLine{ProductID: 1, Reason: StockReasonAdjustment}, wantErr: true},
+		{name: "unknown reason", line: StockAdjustmentLine{ProductID: 1, Delta: 1, Reason: "lost"}, wantErr: true},
+		{name: "transfer reason", line: StockAdjustmentLine{ProductID: 1, Delta: 1, Reason: StockReasonTransfer}, wantErr: true},
+		{name: "count reason", line: StockAdjustmentLine{ProductID: 1, Delta: -1, Reason: StockReasonCount}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.line.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,53 @@
+// internal/models/location.go
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// LocationType describes what kind of place a location is
+type LocationType string
+
+const (
+	LocationTypeWarehouse LocationType = "warehouse"
+	LocationTypeShop      LocationType = "shop"
+)
+
+// Location is a place where stock is physically held, such as a warehouse or a shop
+type Location struct {
+	ID        uint           `json:"id" gorm:"primaryKey"`
+	Code      string         `json:"code" gorm:"size:50;not null;uniqueIndex"`
+	Name      string         `json:"name" gorm:"size:100;not null"`
+	Type      LocationType   `json:"type" gorm:"size:20;not null;default:'warehouse'"`
+	Address   string         `json:"address" gorm:"type:text"`
+	IsDefault bool           `json:"isDefault" gorm:"not null;default:false"`
+	IsActive  bool           `json:"isActive" gorm:"default:true"`
+	CreatedAt time.Time      `json:"createdAt"`
+	UpdatedAt time.Time      `json:"updatedAt"`
+	DeletedAt gorm.DeletedAt `json:"-" gorm:"index"`
+}
+
+// LocationStock is the stock level of a product at a single location, and
+// how much of it reservations for that location hold
+type LocationStock struct {
+	ProductID     uint      `json:"productId" gorm:"primaryKey"`
+	LocationID    uint      `json:"locationId" gorm:"primaryKey;index"`
+	Location      *Location `json:"location,omitempty" gorm:"foreignKey:LocationID"`
+	StockLevel    int       `json:"stockLevel" gorm:"not null;default:0"`
+	ReservedLevel int       `json:"reservedLevel" gorm:"not null;default:0"`
+	UpdatedAt     time.Time `json:"updatedAt"`
+}
+
+// StockTransfer records stock moved from one location to another
+type StockTransfer struct {
+	ID             uint      `json:"id" gorm:"primaryKey"`
+	ProductID      uint      `json:"productId" gorm:"not null;index"`
+	FromLocationID uint      `json:"fromLocationId" gorm:"not null"`
+	ToLocationID   uint      `json:"toLocationId" gorm:"not null"`
+	Quantity       int       `json:"quantity" gorm:"not null"`
+	Actor          string    `json:"actor" gorm:"size:100"`
+	Note           string    `json:"note" gorm:"type:text"`
+	CreatedAt      time.Time `json:"createdAt"`
+}
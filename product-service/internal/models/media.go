@@ -0,0 +1,29 @@
+// internal/models/media.go
+package models
+
+import "time"
+
+// ProductMedia is an image in a product's gallery. Media attached to a variant
+// is shown when that variant is selected.
+type ProductMedia struct {
+	ID          uint      `json:"id" gorm:"primaryKey"`
+	ProductID   uint      `json:"productId" gorm:"not null;index"`
+	VariantID   *uint     `json:"variantId" gorm:"index"`
+	StorageKey  string    `json:"-" gorm:"size:255;not null"`
+	URL         string    `json:"url" gorm:"size:500;not null"`
+	ContentType string    `json:"contentType" gorm:"size:50;not null"`
+	Size        int64     `json:"size" gorm:"not null"`
+	AltText     string    `json:"altText" gorm:"size:255"`
+	Position    int       `json:"position" gorm:"not null;default:0"`
+	IsPrimary   bool      `json:"isPrimary" gorm:"not null;default:false"`
+	CreatedAt   time.Time `json:"createdAt"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+}
+
+// ProductMediaUpdate holds the gallery details of a media item that can be changed after upload
+type ProductMediaUpdate struct {
+	AltText   *string `json:"altText"`
+	Position  *int    `json:"position"`
+	IsPrimary *bool   `json:"isPrimary"`
+	VariantID *uint   `json:"variantId"`
+}
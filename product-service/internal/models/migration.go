@@ -0,0 +1,11 @@
+// internal/models/migration.go
+package models
+
+import "time"
+
+// DataMigration records a one-off data migration that has been applied, so
+// that it does not run again on the next start
+type DataMigration struct {
+	Name      string    `json:"name" gorm:"primaryKey;size:100"`
+	AppliedAt time.Time `json:"appliedAt" gorm:"not null"`
+}
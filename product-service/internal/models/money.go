@@ -0,0 +1,178 @@
+// internal/models/money.go
+package models
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// DefaultCurrency is the currency of amounts given without one, such as
+// prices sent as plain numbers by clients written before prices had a currency
+const DefaultCurrency = "EUR"
+
+// currencyExponents lists the ISO 4217 currencies whose minor unit is not a
+// hundredth; every other currency has two decimal places
+var currencyExponents = map[string]int{
+	"BIF": 0, "CLP": 0, "DJF": 0, "GNF": 0, "ISK": 0, "JPY": 0, "KMF": 0, "KRW": 0,
+	"PYG": 0, "RWF": 0, "UGX": 0, "VND": 0, "VUV": 0, "XAF": 0, "XOF": 0, "XPF": 0,
+	"BHD": 3, "IQD": 3, "JOD": 3, "KWD": 3, "LYD": 3, "OMR": 3, "TND": 3,
+}
+
+// Money is an exact amount of a currency, held as an integer number of its
+// minor units (cents for EUR) so that sums and multiples never drift. In JSON
+// it is written as {"amount": "19.99", "currency": "EUR"}; a plain number or
+// decimal string is also accepted and taken to be in DefaultCurrency.
+type Money struct {
+	Amount   int64  `json:"amount" gorm:"not null;default:0" swaggertype:"string" example:"19.99"`
+	Currency string `json:"currency" gorm:"size:3;not null;default:'EUR'" example:"EUR"`
+}
+
+// NewMoney returns an amount given in minor units of the currency
+func NewMoney(minorUnits int64, currency string) Money {
+	return Money{Amount: minorUnits, Currency: currency}
+}
+
+// ParseMoney parses a decimal amount such as "19.99" in the currency. Amounts
+// with more decimal places than the currency has are rejected rather than rounded.
+func ParseMoney(value, currency string) (Money, error) {
+	currency, err := NormalizeCurrency(currency)
+	if err != nil {
+		return Money{}, err
+	}
+
+	value = strings.TrimSpace(value)
+	// big.Rat also reads fractions such as "1/3", which are not amounts
+	if value == "" || strings.Trim(value, "0123456789.-+eE") != "" {
+		return Money{}, fmt.Errorf("invalid amount %q", value)
+	}
+	amount, ok := new(big.Rat).SetString(value)
+	if !ok {
+		return Money{}, fmt.Errorf("invalid amount %q", value)
+	}
+	scale := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(CurrencyExponent(currency))), nil)
+	amount.Mul(amount, new(big.Rat).SetInt(scale))
+	if !amount.IsInt() {
+		return Money{}, fmt.Errorf("amount %q has more decimal places than %s allows", value, currency)
+	}
+	if !amount.Num().IsInt64() {
+		return Money{}, fmt.Errorf("amount %q is out of range", value)
+	}
+	return Money{Amount: amount.Num().Int64(), Currency: currency}, nil
+}
+
+// CurrencyExponent returns the number of decimal places of a currency's minor unit
+func CurrencyExponent(currency string) int {
+	if exponent, ok := currencyExponents[currency]; ok {
+		return exponent
+	}
+	return 2
+}
+
+// String formats the amount as a decimal, such as "19.99", without its currency
+func (m Money) String() string {
+	exponent := CurrencyExponent(m.Currency)
+	digits := new(big.Int).Abs(big.NewInt(m.Amount)).String()
+	sign := ""
+	if m.Amount < 0 {
+		sign = "-"
+	}
+	if exponent == 0 {
+		return sign + digits
+	}
+	if len(digits) <= exponent {
+		digits = strings.Repeat("0", exponent-len(digits)+1) + digits
+	}
+	return sign + digits[:len(digits)-exponent] + "." + digits[len(digits)-exponent:]
+}
+
+// IsZero reports whether the amount is zero, whatever its currency
+func (m Money) IsZero() bool {
+	return m.Amount == 0
+}
+
+// Mul returns the amount multiplied by a quantity
+func (m Money) Mul(quantity int64) Money {
+	return Money{Amount: m.Amount * quantity, Currency: m.Currency}
+}
+
+// MarshalJSON writes the amount as a decimal string next to its currency
+func (m Money) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Amount   string `json:"amount"`
+		Currency string `json:"currency"`
+	}{Amount: m.String(), Currency: m.Currency})
+}
+
+// UnmarshalJSON reads {"amount": "19.99", "currency": "EUR"}, where the amount
+// may also be a number and the currency defaults to DefaultCurrency, as well
+// as a bare number or decimal string
+func (m *Money) UnmarshalJSON(data []byte) error {
+	return m.unmarshal(data, "")
+}
+
+// unmarshal reads the forms UnmarshalJSON accepts, taking an amount without a
+// currency of its own to be in currency, or DefaultCurrency when that is empty
+func (m *Money) unmarshal(data []byte, currency string) error {
+	data = bytes.TrimSpace(data)
+	if bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+
+	var object struct {
+		Amount   json.RawMessage `json:"amount"`
+		Currency string          `json:"currency"`
+	}
+	object.Currency = currency
+	raw := data
+	if len(data) > 0 && data[0] == '{' {
+		if err := json.Unmarshal(data, &object); err != nil {
+			return err
+		}
+		if len(object.Amount) == 0 {
+			return errors.New("money amount is required")
+		}
+		raw = object.Amount
+	}
+
+	var value string
+	if len(raw) > 0 && raw[0] == '"' {
+		if err := json.Unmarshal(raw, &value); err != nil {
+			return err
+		}
+	} else {
+		var number json.Number
+		if err := json.Unmarshal(raw, &number); err != nil {
+			return fmt.Errorf("invalid money amount %s", raw)
+		}
+		value = number.String()
+	}
+
+	parsed, err := ParseMoney(value, object.Currency)
+	if err != nil {
+		return err
+	}
+	*m = parsed
+	return nil
+}
+
+// NormalizeCurrency checks an ISO 4217 currency code and upper-cases it,
+// defaulting an empty one to DefaultCurrency
+func NormalizeCurrency(currency string) (string, error) {
+	if currency == "" {
+		return DefaultCurrency, nil
+	}
+	currency = strings.ToUpper(strings.TrimSpace(currency))
+	if len(currency) != 3 {
+		return "", fmt.Errorf("invalid currency %q", currency)
+	}
+	for _, r := range currency {
+		if r < 'A' || r > 'Z' {
+			return "", fmt.Errorf("invalid currency %q", currency)
+		}
+	}
+	return currency, nil
+}
@@ -0,0 +1,122 @@
+// internal/models/money_test.go
+package models
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestParseMoney(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		currency string
+		want     Money
+		wantErr  bool
+	}{
+		{name: "two decimals", value: "19.99", currency: "EUR", want: NewMoney(1999, "EUR")},
+		{name: "whole amount", value: "20", currency: "EUR", want: NewMoney(2000, "EUR")},
+		{name: "one decimal", value: "4.5", currency: "EUR", want: NewMoney(450, "EUR")},
+		{name: "default currency", value: "1.00", currency: "", want: NewMoney(100, DefaultCurrency)},
+		{name: "lower-case currency", value: "1.00", currency: "usd", want: NewMoney(100, "USD")},
+		{name: "negative", value: "-0.05", currency: "EUR", want: NewMoney(-5, "EUR")},
+		{name: "surrounding space", value: " 3.10 ", currency: "EUR", want: NewMoney(310, "EUR")},
+		{name: "zero-decimal currency", value: "1500", currency: "JPY", want: NewMoney(1500, "JPY")},
+		{name: "three-decimal currency", value: "1.234", currency: "KWD", want: NewMoney(1234, "KWD")},
+		{name: "too many decimals", value: "19.999", currency: "EUR", wantErr: true},
+		{name: "decimals in zero-decimal currency", value: "1.5", currency: "JPY", wantErr: true},
+		{name: "fraction", value: "1/3", currency: "EUR", wantErr: true},
+		{name: "empty", value: "", currency: "EUR", wantErr: true},
+		{name: "not a number", value: "abc", currency: "EUR", wantErr: true},
+		{name: "invalid currency", value: "1.00", currency: "EURO", wantErr: true},
+		{name: "out of range", value: "1e30", currency: "EUR", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseMoney(tt.value, tt.currency)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("ParseMoney(%q, %q) = %v, want an error", tt.value, tt.currency, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseMoney(%q, %q) returned error: %v", tt.value, tt.currency, err)
+			}
+			if got != tt.want {
+				t.Errorf("ParseMoney(%q, %q) = %+v, want %+v", tt.value, tt.currency, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMoneyString(t *testing.T) {
+	tests := []struct {
+		money Money
+		want  string
+	}{
+		{money: NewMoney(1999, "EUR"), want: "19.99"},
+		{money: NewMoney(5, "EUR"), want: "0.05"},
+		{money: NewMoney(0, "EUR"), want: "0.00"},
+		{money: NewMoney(-5, "EUR"), want: "-0.05"},
+		{money: NewMoney(-1999, "EUR"), want: "-19.99"},
+		{money: NewMoney(1500, "JPY"), want: "1500"},
+		{money: NewMoney(-1500, "JPY"), want: "-1500"},
+		{money: NewMoney(1234, "KWD"), want: "1.234"},
+		{money: NewMoney(7, "KWD"), want: "0.007"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.money.String(); got != tt.want {
+			t.Errorf("%+v.String() = %q, want %q", tt.money, got, tt.want)
+		}
+	}
+}
+
+func TestMoneyJSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    Money
+		wantErr bool
+	}{
+		{name: "object", data: `{"amount": "19.99", "currency": "USD"}`, want: NewMoney(1999, "USD")},
+		{name: "object with number amount", data: `{"amount": 19.99, "currency": "USD"}`, want: NewMoney(1999, "USD")},
+		{name: "object without currency", data: `{"amount": "19.99"}`, want: NewMoney(1999, DefaultCurrency)},
+		{name: "bare number", data: `19.99`, want: NewMoney(1999, DefaultCurrency)},
+		{name: "bare string", data: `"19.99"`, want: NewMoney(1999, DefaultCurrency)},
+		{name: "object without amount", data: `{"currency": "USD"}`, wantErr: true},
+		{name: "too many decimals", data: `19.999`, wantErr: true},
+		{name: "boolean", data: `true`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got Money
+			err := json.Unmarshal([]byte(tt.data), &got)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("unmarshalling %s gave %+v, want an error", tt.data, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unmarshalling %s returned error: %v", tt.data, err)
+			}
+			if got != tt.want {
+				t.Errorf("unmarshalling %s gave %+v, want %+v", tt.data, got, tt.want)
+			}
+
+			// Writing the amount out and reading it back gives the same amount
+			data, err := json.Marshal(got)
+			if err != nil {
+				t.Fatalf("marshalling %+v returned error: %v", got, err)
+			}
+			var again Money
+			if err := json.Unmarshal(data, &again); err != nil || again != got {
+				t.Errorf("round trip through %s gave %+v (%v), want %+v", data, again, err, got)
+			}
+		})
+	}
+}
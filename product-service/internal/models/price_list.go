@@ -0,0 +1,230 @@
+// internal/models/price_list.go
+package models
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+)
+
+// PriceList holds the prices products sell at in one currency, such as the
+// Tunisian shop's prices in TND. The default list of a currency is used when
+// prices are asked for in that currency without naming a list.
+type PriceList struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	Name      string    `json:"name" gorm:"size:100;not null;uniqueIndex"`
+	Currency  string    `json:"currency" gorm:"size:3;not null;index"`
+	IsDefault bool      `json:"isDefault" gorm:"not null;default:false"`
+	IsActive  bool      `json:"isActive" gorm:"default:true"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// PriceListFilter represents the filter options for price lists
+type PriceListFilter struct {
+	Currency string `form:"currency"`
+	Active   *bool  `form:"active"`
+}
+
+// ProductPrice is the price of a product, or of a single variant, in a price list
+type ProductPrice struct {
+	ID          uint      `json:"id" gorm:"primaryKey"`
+	PriceListID uint      `json:"priceListId" gorm:"not null;uniqueIndex:idx_product_prices_list_product"`
+	ProductID   uint      `json:"productId" gorm:"not null;uniqueIndex:idx_product_prices_list_product;index"`
+	Price       Money     `json:"price" gorm:"embedded;embeddedPrefix:price_"`
+	CreatedAt   time.Time `json:"createdAt"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+}
+
+// ExchangeRate converts amounts from one currency into another from a date
+// on: one unit of BaseCurrency is worth Rate units of QuoteCurrency
+type ExchangeRate struct {
+	ID            uint      `json:"id" gorm:"primaryKey"`
+	BaseCurrency  string    `json:"baseCurrency" gorm:"size:3;not null;uniqueIndex:idx_exchange_rates_pair_date,priority:1"`
+	QuoteCurrency string    `json:"quoteCurrency" gorm:"size:3;not null;uniqueIndex:idx_exchange_rates_pair_date,priority:2"`
+	Rate          Decimal   `json:"rate" gorm:"type:numeric(20,10);not null" swaggertype:"number" example:"3.3512"`
+	EffectiveFrom time.Time `json:"effectiveFrom" gorm:"type:date;not null;uniqueIndex:idx_exchange_rates_pair_date,priority:3"`
+	CreatedAt     time.Time `json:"createdAt"`
+}
+
+// Decimal is an exact decimal number such as an exchange rate, kept as its
+// digits so that it never picks up binary rounding on its way between JSON,
+// Go and a numeric column. In JSON it is a number; a string is also accepted.
+type Decimal string
+
+// Rat returns the number as an exact fraction, or false when it is not a
+// plain decimal number
+func (d Decimal) Rat() (*big.Rat, bool) {
+	value := strings.TrimSpace(string(d))
+	// big.Rat also reads fractions such as "1/3", which are not decimals
+	if value == "" || strings.Trim(value, "0123456789.-+") != "" {
+		return nil, false
+	}
+	return new(big.Rat).SetString(value)
+}
+
+// MarshalJSON writes the number as it is stored
+func (d Decimal) MarshalJSON() ([]byte, error) {
+	if _, ok := d.Rat(); !ok {
+		return []byte("null"), nil
+	}
+	return []byte(d), nil
+}
+
+// UnmarshalJSON reads a number or a decimal string
+func (d *Decimal) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+	var value string
+	if len(data) > 0 && data[0] == '"' {
+		if err := json.Unmarshal(data, &value); err != nil {
+			return err
+		}
+	} else {
+		var number json.Number
+		if err := json.Unmarshal(data, &number); err != nil {
+			return fmt.Errorf("invalid decimal %s", data)
+		}
+		value = number.String()
+	}
+	if _, ok := Decimal(value).Rat(); !ok {
+		return fmt.Errorf("invalid decimal %q", value)
+	}
+	*d = Decimal(strings.TrimSpace(value))
+	return nil
+}
+
+// Value stores the number's digits in a numeric column
+func (d Decimal) Value() (driver.Value, error) {
+	return string(d), nil
+}
+
+// Scan reads a numeric column, dropping the zeros its scale pads it with
+func (d *Decimal) Scan(value interface{}) error {
+	var text string
+	switch v := value.(type) {
+	case nil:
+		*d = ""
+		return nil
+	case []byte:
+		text = string(v)
+	case string:
+		text = v
+	case int64:
+		text = fmt.Sprint(v)
+	default:
+		return fmt.Errorf("unsupported type %T for a decimal column", value)
+	}
+	if strings.Contains(text, ".") {
+		text = strings.TrimRight(strings.TrimRight(text, "0"), ".")
+	}
+	*d = Decimal(text)
+	return nil
+}
+
+// ExchangeRateFilter represents the filter options for exchange rates
+type ExchangeRateFilter struct {
+	// Currency matches rates from or into the currency
+	Currency string `form:"currency"`
+}
+
+// PriceQuery asks for prices in a price list, or in a currency
+type PriceQuery struct {
+	PriceListID *uint  `form:"priceList"`
+	Currency    string `form:"currency"`
+}
+
+// IsSet reports whether the query asks for resolved prices at all
+func (q PriceQuery) IsSet() bool {
+	return q.PriceListID != nil || q.Currency != ""
+}
+
+// PriceSource says where a resolved price comes from
+type PriceSource string
+
+const (
+	// PriceSourcePriceList is a price set in the price list
+	PriceSourcePriceList PriceSource = "price_list"
+	// PriceSourceBase is the product's own price, already in the currency
+	PriceSourceBase PriceSource = "base"
+	// PriceSourceConverted is the product's own price converted at the
+	// exchange rate in effect
+	PriceSourceConverted PriceSource = "converted"
+)
+
+// ResolvedPrice is the price of a product in the price list or currency asked
+// for, with its sale price, if it is on sale, in the same currency
+type ResolvedPrice struct {
+	Price       Money       `json:"price"`
+	SalePrice   *Money      `json:"salePrice,omitempty"`
+	SaleEndsAt  *time.Time  `json:"saleEndsAt,omitempty"`
+	Source      PriceSource `json:"source"`
+	PriceListID *uint       `json:"priceListId,omitempty"`
+}
+
+// PriceContext is what it takes to resolve prices for a price query: the
+// currency, the price list whose prices come first, if any, and conversion
+// factors for base prices in other currencies
+type PriceContext struct {
+	Currency  string
+	PriceList *PriceList
+	// Factors maps a currency to the number of minor units of Currency that
+	// one of its minor units is worth, as a decimal. The database and Resolve
+	// both convert with these exact figures so their results always agree.
+	Factors map[string]string
+}
+
+// Resolve returns the price of a product given its price in the price list,
+// if it has one there. It returns nil when the product's base price is in a
+// currency there is no exchange rate for.
+func (c *PriceContext) Resolve(product *Product, listPrice *Money) *ResolvedPrice {
+	if listPrice != nil && c.PriceList != nil {
+		return &ResolvedPrice{Price: *listPrice, Source: PriceSourcePriceList, PriceListID: &c.PriceList.ID}
+	}
+	price, ok := c.Convert(product.Price)
+	if !ok {
+		return nil
+	}
+	source := PriceSourceBase
+	if product.Price.Currency != c.Currency {
+		source = PriceSourceConverted
+	}
+	return &ResolvedPrice{Price: price, Source: source}
+}
+
+// Convert returns an amount in the context's currency, converting it at the
+// exchange rate in effect when it is in another one. Every price shown in
+// the currency goes through here, so that they all convert alike. It returns
+// false when there is no exchange rate for the amount's currency.
+func (c *PriceContext) Convert(amount Money) (Money, bool) {
+	if amount.Currency == c.Currency {
+		return amount, true
+	}
+	factor, ok := new(big.Rat).SetString(c.Factors[amount.Currency])
+	if !ok {
+		return Money{}, false
+	}
+	converted := new(big.Rat).Mul(new(big.Rat).SetInt64(amount.Amount), factor)
+	return Money{Amount: roundHalfAwayFromZero(converted), Currency: c.Currency}, true
+}
+
+// roundHalfAwayFromZero rounds to a whole number the way PostgreSQL's ROUND
+// does for numeric values
+func roundHalfAwayFromZero(value *big.Rat) int64 {
+	quotient, remainder := new(big.Int).QuoRem(value.Num(), value.Denom(), new(big.Int))
+	remainder.Abs(remainder).Mul(remainder, big.NewInt(2))
+	if remainder.Cmp(value.Denom()) >= 0 {
+		if value.Sign() < 0 {
+			quotient.Sub(quotient, big.NewInt(1))
+		} else {
+			quotient.Add(quotient, big.NewInt(1))
+		}
+	}
+	return quotient.Int64()
+}
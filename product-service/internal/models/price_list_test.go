@@ -0,0 +1,29 @@
+// internal/models/price_list_test.go
+package models
+
+import "testing"
+
+func TestPriceContextConvert(t *testing.T) {
+	pricing := &PriceContext{Currency: "PLN", Factors: map[string]string{"EUR": "4.300000000000"}}
+
+	tests := []struct {
+		name   string
+		amount Money
+		want   Money
+		wantOK bool
+	}{
+		{name: "same currency", amount: NewMoney(1999, "PLN"), want: NewMoney(1999, "PLN"), wantOK: true},
+		{name: "converted", amount: NewMoney(1999, "EUR"), want: NewMoney(8596, "PLN"), wantOK: true},
+		{name: "half rounds up", amount: NewMoney(5, "EUR"), want: NewMoney(22, "PLN"), wantOK: true},
+		{name: "no rate", amount: NewMoney(1999, "USD"), want: NewMoney(1999, "USD")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := pricing.Convert(tt.amount)
+			if ok != tt.wantOK || (ok && got != tt.want) {
+				t.Errorf("Convert(%+v) = %+v, %v, want %+v, %v", tt.amount, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
@@ -0,0 +1,113 @@
+// internal/models/pricing.go
+package models
+
+import (
+	"math/big"
+	"strconv"
+	"time"
+)
+
+// PriceTier is the unit price of a product when buying between MinQuantity
+// and MaxQuantity units at once, such as cables bought in packs of 50. A nil
+// MaxQuantity makes the tier open-ended.
+type PriceTier struct {
+	ID          uint  `json:"id" gorm:"primaryKey"`
+	ProductID   uint  `json:"productId" gorm:"not null;index"`
+	MinQuantity int   `json:"minQuantity" gorm:"not null"`
+	MaxQuantity *int  `json:"maxQuantity"`
+	UnitPrice   Money `json:"unitPrice" gorm:"embedded;embeddedPrefix:unit_price_"`
+	// ResolvedUnitPrice is the unit price in the price list or currency asked
+	// for, when there is one
+	ResolvedUnitPrice *Money    `json:"resolvedUnitPrice,omitempty" gorm:"-"`
+	CreatedAt         time.Time `json:"createdAt"`
+	UpdatedAt         time.Time `json:"updatedAt"`
+}
+
+// Covers reports whether buying quantity units falls in the tier
+func (t *PriceTier) Covers(quantity int) bool {
+	return quantity >= t.MinQuantity && (t.MaxQuantity == nil || quantity <= *t.MaxQuantity)
+}
+
+// TierFor returns the tier covering quantity, or nil when no tier does
+func TierFor(tiers []PriceTier, quantity int) *PriceTier {
+	for i := range tiers {
+		if tiers[i].Covers(quantity) {
+			return &tiers[i]
+		}
+	}
+	return nil
+}
+
+// QuoteItem asks for the price of a quantity of a product
+type QuoteItem struct {
+	ProductID uint
+	Quantity  int
+}
+
+// QuotePriceSource says which price a quote line's unit price comes from
+type QuotePriceSource string
+
+const (
+	// QuotePriceSourceRegular is the product's regular price
+	QuotePriceSourceRegular QuotePriceSource = "regular"
+	// QuotePriceSourceSale is the sale price set by a running promotion
+	QuotePriceSourceSale QuotePriceSource = "sale"
+	// QuotePriceSourceTier is the unit price of the quantity tier bought
+	QuotePriceSourceTier QuotePriceSource = "tier"
+)
+
+// QuoteLine is the authoritative price of one line of a quote
+type QuoteLine struct {
+	ProductID        uint             `json:"productId"`
+	SKU              string           `json:"sku"`
+	Name             string           `json:"name"`
+	Quantity         int              `json:"quantity"`
+	RegularUnitPrice Money            `json:"regularUnitPrice"`
+	UnitPrice        Money            `json:"unitPrice"`
+	LineTotal        Money            `json:"lineTotal"`
+	PriceSource      QuotePriceSource `json:"priceSource"`
+	Tier             *PriceTier       `json:"tier,omitempty"`
+	// Discount is what the discount rules took off the line total, each rule
+	// listed in Discounts, leaving NetTotal to pay
+	Discount  Money             `json:"discount"`
+	Discounts []AppliedDiscount `json:"discounts,omitempty"`
+	NetTotal  Money             `json:"netTotal"`
+	TaxRate   float64           `json:"taxRate"`
+	Tax       Money             `json:"tax"`
+}
+
+// Quote prices a cart: its lines, the discounts applied to it and the tax it
+// carries. Total is Subtotal less DiscountTotal, plus TaxTotal when prices
+// do not include tax.
+type Quote struct {
+	Lines            []QuoteLine       `json:"lines"`
+	PriceListID      *uint             `json:"priceListId,omitempty"`
+	CouponCode       string            `json:"couponCode,omitempty"`
+	Subtotal         Money             `json:"subtotal"`
+	Discounts        []AppliedDiscount `json:"discounts"`
+	DiscountTotal    Money             `json:"discountTotal"`
+	PricesIncludeTax bool              `json:"pricesIncludeTax"`
+	TaxTotal         Money             `json:"taxTotal"`
+	Total            Money             `json:"total"`
+}
+
+// TaxOn returns the tax at rate percent carried by an amount. When prices
+// include tax the tax is the part of the amount above its net value;
+// otherwise it is added on top of the amount.
+func TaxOn(amount Money, rate float64, inclusive bool) Money {
+	tax := Money{Currency: amount.Currency}
+	if rate <= 0 || amount.Amount == 0 {
+		return tax
+	}
+	percentage, ok := new(big.Rat).SetString(strconv.FormatFloat(rate, 'f', -1, 64))
+	if !ok {
+		return tax
+	}
+	base := big.NewRat(100, 1)
+	if inclusive {
+		base.Add(base, percentage)
+	}
+	value := new(big.Rat).Mul(new(big.Rat).SetInt64(amount.Amount), percentage)
+	tax.Amount = roundHalfAwayFromZero(value.Quo(value, base))
+	return tax
+}
@@ -0,0 +1,75 @@
+// internal/models/pricing_test.go
+package models
+
+import "testing"
+
+func intPtr(n int) *int {
+	return &n
+}
+
+func TestTierFor(t *testing.T) {
+	tiers := []PriceTier{
+		{ID: 1, MinQuantity: 10, MaxQuantity: intPtr(49), UnitPrice: NewMoney(450, "EUR")},
+		{ID: 2, MinQuantity: 50, UnitPrice: NewMoney(390, "EUR")},
+	}
+
+	tests := []struct {
+		name     string
+		tiers    []PriceTier
+		quantity int
+		wantID   uint
+	}{
+		{name: "below every tier", tiers: tiers, quantity: 9},
+		{name: "lower bound", tiers: tiers, quantity: 10, wantID: 1},
+		{name: "upper bound", tiers: tiers, quantity: 49, wantID: 1},
+		{name: "open-ended tier", tiers: tiers, quantity: 50, wantID: 2},
+		{name: "far into the open-ended tier", tiers: tiers, quantity: 5000, wantID: 2},
+		{name: "no tiers", tiers: nil, quantity: 10},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := TierFor(tt.tiers, tt.quantity)
+			if tt.wantID == 0 {
+				if got != nil {
+					t.Errorf("TierFor(%d) = tier %d, want none", tt.quantity, got.ID)
+				}
+				return
+			}
+			if got == nil || got.ID != tt.wantID {
+				t.Errorf("TierFor(%d) = %v, want tier %d", tt.quantity, got, tt.wantID)
+			}
+		})
+	}
+}
+
+func TestTaxOn(t *testing.T) {
+	tests := []struct {
+		name      string
+		amount    Money
+		rate      float64
+		inclusive bool
+		want      int64
+	}{
+		{name: "added on top", amount: NewMoney(10000, "EUR"), rate: 20, want: 2000},
+		{name: "included", amount: NewMoney(12000, "EUR"), rate: 20, inclusive: true, want: 2000},
+		{name: "fractional rate", amount: NewMoney(10000, "EUR"), rate: 5.5, want: 550},
+		{name: "half rounds up", amount: NewMoney(250, "EUR"), rate: 1, want: 3},
+		{name: "below half rounds down", amount: NewMoney(249, "EUR"), rate: 1, want: 2},
+		{name: "negative half rounds away from zero", amount: NewMoney(-250, "EUR"), rate: 1, want: -3},
+		{name: "included rounds to the nearest cent", amount: NewMoney(1999, "EUR"), rate: 20, inclusive: true, want: 333},
+		{name: "zero rate", amount: NewMoney(1999, "EUR"), rate: 0, want: 0},
+		{name: "negative rate", amount: NewMoney(1999, "EUR"), rate: -5, want: 0},
+		{name: "zero amount", amount: NewMoney(0, "EUR"), rate: 20, want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := TaxOn(tt.amount, tt.rate, tt.inclusive)
+			if got.Amount != tt.want || got.Currency != tt.amount.Currency {
+				t.Errorf("TaxOn(%s, %v, %v) = %s %s, want %s %s", tt.amount, tt.rate, tt.inclusive,
+					got, got.Currency, NewMoney(tt.want, tt.amount.Currency), tt.amount.Currency)
+			}
+		})
+	}
+}
@@ -2,58 +2,296 @@
 package models
 
 import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
 	"time"
 
 	"gorm.io/gorm"
 )
 
 type Product struct {
-	ID          uint           `json:"id" gorm:"primaryKey"`
-	Name        string         `json:"name" gorm:"size:255;not null"`
-	Description string         `json:"description" gorm:"type:text"`
-	Price       float64        `json:"price" gorm:"not null"`
-	SKU         string         `json:"sku" gorm:"size:50;uniqueIndex;not null"`
-	StockLevel  int            `json:"stockLevel" gorm:"not null;default:0"`
-	ImageURL    string         `json:"imageUrl" gorm:"size:255"`
-	CategoryID  uint           `json:"categoryId"`
-	Category    Category       `json:"category" gorm:"foreignKey:CategoryID"`
-	Attributes  JSON           `json:"attributes" gorm:"type:jsonb"`
-	IsActive    bool           `json:"isActive" gorm:"default:true"`
-	CreatedAt   time.Time      `json:"createdAt"`
-	UpdatedAt   time.Time      `json:"updatedAt"`
-	DeletedAt   gorm.DeletedAt `json:"-" gorm:"index"`
+	ID                  uint               `json:"id" gorm:"primaryKey"`
+	Name                string             `json:"name" gorm:"size:255;not null"`
+	Slug                string             `json:"slug" gorm:"size:255;uniqueIndex"`
+	Description         string             `json:"description" gorm:"type:text"`
+	Price               Money              `json:"price" gorm:"embedded;embeddedPrefix:price_" swaggertype:"number" example:"19.99"`
+	RegularPrice        *Money             `json:"regularPrice,omitempty" gorm:"-"`
+	SalePrice           *Money             `json:"salePrice" gorm:"-"`
+	SaleEndsAt          *time.Time         `json:"saleEndsAt" gorm:"-"`
+	ResolvedPrice       *ResolvedPrice     `json:"resolvedPrice,omitempty" gorm:"-"`
+	PriceTiers          []PriceTier        `json:"priceTiers,omitempty" gorm:"foreignKey:ProductID"`
+	SKU                 string             `json:"sku" gorm:"size:50;uniqueIndex;not null"`
+	StockLevel          int                `json:"stockLevel" gorm:"not null;default:0"`
+	ReservedLevel       int                `json:"reservedLevel" gorm:"not null;default:0"`
+	AvailableQuantity   int                `json:"availableQuantity" gorm:"-"`
+	Locations           []LocationStock    `json:"locations,omitempty" gorm:"foreignKey:ProductID"`
+	ReorderPoint        int                `json:"reorderPoint" gorm:"not null;default:0"`
+	ReorderQuantity     int                `json:"reorderQuantity" gorm:"not null;default:0"`
+	IncomingQuantity    int                `json:"incomingQuantity" gorm:"-"`
+	AvailabilityPolicy  AvailabilityPolicy `json:"availabilityPolicy" gorm:"size:20;not null;default:'deny'"`
+	BackorderLimit      *int               `json:"backorderLimit"`
+	ExpectedShipDate    *time.Time         `json:"expectedShipDate"`
+	AvailabilityStatus  AvailabilityStatus `json:"availabilityStatus" gorm:"-"`
+	ExpectedAvailableAt *time.Time         `json:"expectedAvailableAt" gorm:"-"`
+	ImageURL            string             `json:"imageUrl" gorm:"size:255"`
+	Media               []ProductMedia     `json:"media,omitempty" gorm:"foreignKey:ProductID"`
+	CompatibleDevices   []DeviceModel      `json:"compatibleDevices,omitempty" gorm:"many2many:product_devices"`
+	CompatibleDeviceIDs []uint             `json:"compatibleDeviceIds,omitempty" gorm:"-"`
+	CategoryID          uint               `json:"categoryId"`
+	Category            Category           `json:"category" gorm:"foreignKey:CategoryID"`
+	BrandID             *uint              `json:"brandId" gorm:"index"`
+	Brand               *Brand             `json:"brand,omitempty" gorm:"foreignKey:BrandID"`
+	Attributes          JSON               `json:"attributes" gorm:"type:jsonb;index:idx_products_attributes,type:gin"`
+	IsActive            bool               `json:"isActive" gorm:"default:true"`
+	ParentID            *uint              `json:"parentId" gorm:"index"`
+	Options             []ProductOption    `json:"options,omitempty" gorm:"foreignKey:ProductID"`
+	VariantOptions      JSON               `json:"variantOptions,omitempty" gorm:"type:jsonb"`
+	Variants            []Product          `json:"variants,omitempty" gorm:"foreignKey:ParentID"`
+	IsBundle            bool               `json:"isBundle" gorm:"not null;default:false"`
+	Components          []BundleComponent  `json:"components,omitempty" gorm:"foreignKey:BundleID"`
+	Version             uint               `json:"version" gorm:"not null;default:1"`
+	CreatedAt           time.Time          `json:"createdAt"`
+	UpdatedAt           time.Time          `json:"updatedAt"`
+	DeletedAt           gorm.DeletedAt     `json:"-" gorm:"index"`
+}
+
+// productJSON is the product without its JSON methods, so they can build on
+// its default encoding
+type productJSON Product
+
+// MarshalJSON writes the price as a decimal number in major units, as it was
+// before prices had a currency, with the currency in its own field next to it
+func (p Product) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		productJSON
+		Price    json.Number `json:"price"`
+		Currency string      `json:"currency"`
+	}{productJSON: productJSON(p), Price: json.Number(p.Price.String()), Currency: p.Price.Currency})
+}
+
+// UnmarshalJSON reads the price as a decimal number or string in the currency
+// field, which defaults to DefaultCurrency; a price written as
+// {"amount": "19.99", "currency": "EUR"} is also accepted
+func (p *Product) UnmarshalJSON(data []byte) error {
+	fields := struct {
+		*productJSON
+		Price    json.RawMessage `json:"price"`
+		Currency string          `json:"currency"`
+	}{productJSON: (*productJSON)(p)}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+
+	currency, err := NormalizeCurrency(fields.Currency)
+	if err != nil {
+		return err
+	}
+	p.Price = Money{Currency: currency}
+	if len(fields.Price) == 0 {
+		return nil
+	}
+	return p.Price.unmarshal(fields.Price, currency)
+}
+
+// AvailabilityPolicy decides what happens when a product runs out of stock
+type AvailabilityPolicy string
+
+const (
+	// AvailabilityPolicyDeny refuses to sell more than is in stock
+	AvailabilityPolicyDeny AvailabilityPolicy = "deny"
+	// AvailabilityPolicyBackorder keeps selling and ships once stock arrives
+	AvailabilityPolicyBackorder AvailabilityPolicy = "backorder"
+	// AvailabilityPolicyPreorder sells a product that has not been released yet
+	AvailabilityPolicyPreorder AvailabilityPolicy = "preorder"
+)
+
+// IsValid reports whether p is one of the known availability policies
+func (p AvailabilityPolicy) IsValid() bool {
+	switch p {
+	case AvailabilityPolicyDeny, AvailabilityPolicyBackorder, AvailabilityPolicyPreorder:
+		return true
+	}
+	return false
+}
+
+// SellsAheadOfStock reports whether the policy allows selling units that are not in stock yet
+func (p AvailabilityPolicy) SellsAheadOfStock() bool {
+	return p == AvailabilityPolicyBackorder || p == AvailabilityPolicyPreorder
+}
+
+// AvailabilityStatus is the computed sellability of a product shown to shoppers
+type AvailabilityStatus string
+
+const (
+	AvailabilityStatusInStock    AvailabilityStatus = "in_stock"
+	AvailabilityStatusBackorder  AvailabilityStatus = "backorder"
+	AvailabilityStatusPreorder   AvailabilityStatus = "preorder"
+	AvailabilityStatusOutOfStock AvailabilityStatus = "out_of_stock"
+)
+
+// AfterFind computes the quantity available to sell, excluding units held by
+// open reservations, and the availability status that follows from it, and
+// starts the regular price off at the product's price
+func (p *Product) AfterFind(tx *gorm.DB) error {
+	p.AvailableQuantity = p.StockLevel - p.ReservedLevel
+	// Promotions running now may lower it to a sale price
+	regular := p.Price
+	p.RegularPrice = &regular
+
+	p.ExpectedAvailableAt = nil
+	switch {
+	case p.AvailableQuantity > 0:
+		p.AvailabilityStatus = AvailabilityStatusInStock
+	case !p.CanSell(1):
+		p.AvailabilityStatus = AvailabilityStatusOutOfStock
+	case p.AvailabilityPolicy == AvailabilityPolicyPreorder:
+		p.AvailabilityStatus = AvailabilityStatusPreorder
+		p.ExpectedAvailableAt = p.ExpectedShipDate
+	default:
+		p.AvailabilityStatus = AvailabilityStatusBackorder
+	}
+	return nil
+}
+
+// CanSell reports whether quantity more units can be sold or reserved, either
+// from available stock or ahead of stock as the availability policy allows.
+// BackorderLimit caps the units sold ahead of stock; nil means no cap.
+func (p *Product) CanSell(quantity int) bool {
+	available := p.StockLevel - p.ReservedLevel
+	if quantity <= available {
+		return true
+	}
+	if !p.AvailabilityPolicy.SellsAheadOfStock() {
+		return false
+	}
+	return p.BackorderLimit == nil || quantity <= available+*p.BackorderLimit
 }
 
 // ProductFilter represents the filter options for products
 type ProductFilter struct {
-	CategoryID    *uint    `form:"categoryId"`
-	MinPrice      *float64 `form:"minPrice"`
-	MaxPrice      *float64 `form:"maxPrice"`
-	SearchQuery   string   `form:"q"`
-	InStock       *bool    `form:"inStock"`
-	SortBy        string   `form:"sortBy"`
-	SortDirection string   `form:"sortDir"`
-	Page          int      `form:"page,default=1"`
-	PageSize      int      `form:"pageSize,default=20"`
+	CategoryID  *uint  `form:"categoryId"`
+	SearchQuery string `form:"q"`
+	InStock     *bool  `form:"inStock"`
+	LocationID  *uint  `form:"locationId"`
+	DeviceID    *uint  `form:"deviceId"`
+	BrandID     *uint  `form:"brandId"`
+	// OnSale keeps products with a promotion running now, or without one when false
+	OnSale *bool `form:"onSale"`
+	// PriceListID and Currency ask for prices resolved in a price list or a
+	// currency; minPrice, maxPrice and sorting by price then use the resolved
+	// price. Without either, base prices in DefaultCurrency are compared.
+	PriceListID *uint  `form:"priceList"`
+	Currency    string `form:"currency"`
+	// MinPrice and MaxPrice are decimal amounts in the currency prices are
+	// compared in
+	MinPrice string `form:"minPrice"`
+	MaxPrice string `form:"maxPrice"`
+	// Pricing and PriceRange are worked out by the service from the price
+	// query, so that bounds are compared exactly in minor units
+	Pricing    *PriceContext `form:"-"`
+	PriceRange PriceRange    `form:"-"`
+	// IncludeDescendants widens the category filter to its subcategories
+	IncludeDescendants bool `form:"includeDescendants"`
+	// Attributes are read from attr.* query parameters by the handler
+	Attributes []AttributeFilter `form:"-"`
+	// Options matches products with a variant having these option values,
+	// read from option[colour]=Noir query parameters by the handler
+	Options       map[string]string `form:"-"`
+	SortBy        string            `form:"sortBy"`
+	SortDirection string            `form:"sortDir"`
+	Page          int               `form:"page,default=1"`
+	PageSize      int               `form:"pageSize,default=20"`
+}
+
+// PriceRange bounds the price of listed products, either end being optional
+type PriceRange struct {
+	Min *Money
+	Max *Money
 }
 
 // JSON is a custom type for handling JSON in GORM
 type JSON map[string]interface{}
 
-// Category represents a product category
+// Value stores the map as a JSON document
+func (j JSON) Value() (driver.Value, error) {
+	if j == nil {
+		return nil, nil
+	}
+	b, err := json.Marshal(j)
+	return string(b), err
+}
+
+// Scan reads a JSON document into the map
+func (j *JSON) Scan(value interface{}) error {
+	return scanJSON(value, j)
+}
+
+// StringList is a list of strings stored as a JSON array
+type StringList []string
+
+// Value stores the list as a JSON array
+func (l StringList) Value() (driver.Value, error) {
+	if l == nil {
+		return "[]", nil
+	}
+	b, err := json.Marshal(l)
+	return string(b), err
+}
+
+// Scan reads a JSON array into the list
+func (l *StringList) Scan(value interface{}) error {
+	return scanJSON(value, l)
+}
+
+func scanJSON(value interface{}, target interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case []byte:
+		return json.Unmarshal(v, target)
+	case string:
+		return json.Unmarshal([]byte(v), target)
+	default:
+		return errors.New("unsupported type for JSON column")
+	}
+}
+
+// ProductOption is a variant axis defined on a parent product, such as colour
+// or compatible model, with the values its variants can take
+type ProductOption struct {
+	ID        uint       `json:"id" gorm:"primaryKey"`
+	ProductID uint       `json:"productId" gorm:"not null;uniqueIndex:idx_product_options_name,priority:1"`
+	Name      string     `json:"name" gorm:"size:50;not null;uniqueIndex:idx_product_options_name,priority:2"`
+	Values    StringList `json:"values" gorm:"type:jsonb;not null"`
+	Position  int        `json:"position" gorm:"not null;default:0"`
+}
+
+// Category represents a product category. A nil TaxRate takes the tax rate
+// of the parent category, or the default rate for top-level categories.
 type Category struct {
 	ID          uint           `json:"id" gorm:"primaryKey"`
 	Name        string         `json:"name" gorm:"size:100;not null;uniqueIndex"`
+	Slug        string         `json:"slug" gorm:"size:255;uniqueIndex"`
 	Description string         `json:"description" gorm:"type:text"`
 	ParentID    *uint          `json:"parentId"`
 	Parent      *Category      `json:"parent,omitempty" gorm:"foreignKey:ParentID"`
 	ImageURL    string         `json:"imageUrl" gorm:"size:255"`
+	TaxRate     *float64       `json:"taxRate" gorm:"type:numeric(5,2)"`
 	IsActive    bool           `json:"isActive" gorm:"default:true"`
+	Version     uint           `json:"version" gorm:"not null;default:1"`
 	CreatedAt   time.Time      `json:"createdAt"`
 	UpdatedAt   time.Time      `json:"updatedAt"`
 	DeletedAt   gorm.DeletedAt `json:"-" gorm:"index"`
 }
 
+// CategoryNode is a category with its subcategories, as returned by the
+// category tree
+type CategoryNode struct {
+	Category
+	Children []*CategoryNode `json:"children"`
+}
+
 // PaginatedResponse represents a paginated response
 type PaginatedResponse struct {
 	Items      interface{} `json:"items"`
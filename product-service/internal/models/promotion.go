@@ -0,0 +1,136 @@
+// internal/models/promotion.go
+package models
+
+import (
+	"math/big"
+	"strconv"
+	"time"
+)
+
+// PromotionScope says which products a promotion applies to
+type PromotionScope string
+
+const (
+	// PromotionScopeProduct applies to one product and its variants
+	PromotionScopeProduct PromotionScope = "product"
+	// PromotionScopeCategory applies to the products of a category and of its subcategories
+	PromotionScopeCategory PromotionScope = "category"
+	// PromotionScopeBrand applies to the products of a brand
+	PromotionScopeBrand PromotionScope = "brand"
+)
+
+// IsValid reports whether s is one of the known promotion scopes
+func (s PromotionScope) IsValid() bool {
+	switch s {
+	case PromotionScopeProduct, PromotionScopeCategory, PromotionScopeBrand:
+		return true
+	}
+	return false
+}
+
+// PromotionType says how a promotion lowers a price
+type PromotionType string
+
+const (
+	// PromotionTypeSalePrice sells a product at Value instead of its price
+	PromotionTypeSalePrice PromotionType = "sale_price"
+	// PromotionTypePercentage takes Percentage percent off the price
+	PromotionTypePercentage PromotionType = "percentage"
+	// PromotionTypeFixed takes Value off the price
+	PromotionTypeFixed PromotionType = "fixed"
+)
+
+// IsValid reports whether t is one of the known promotion types
+func (t PromotionType) IsValid() bool {
+	switch t {
+	case PromotionTypeSalePrice, PromotionTypePercentage, PromotionTypeFixed:
+		return true
+	}
+	return false
+}
+
+// Promotion puts products on sale between two moments. When several
+// promotions apply to a product the one with the highest priority wins, then
+// the one giving the lowest price, then the oldest.
+type Promotion struct {
+	ID         uint           `json:"id" gorm:"primaryKey"`
+	Name       string         `json:"name" gorm:"size:100;not null"`
+	Scope      PromotionScope `json:"scope" gorm:"size:20;not null;index:idx_promotions_target,priority:1"`
+	TargetID   uint           `json:"targetId" gorm:"not null;index:idx_promotions_target,priority:2"`
+	Type       PromotionType  `json:"type" gorm:"size:20;not null"`
+	Percentage float64        `json:"percentage" gorm:"type:numeric(5,2);not null;default:0"`
+	// Value is the sale price or the amount off, and only applies to prices
+	// in its currency
+	Value     Money      `json:"value" gorm:"embedded;embeddedPrefix:value_"`
+	Priority  int        `json:"priority" gorm:"not null;default:0"`
+	StartsAt  time.Time  `json:"startsAt" gorm:"not null;index"`
+	EndsAt    *time.Time `json:"endsAt" gorm:"index"`
+	IsActive  bool       `json:"isActive" gorm:"default:true"`
+	CreatedAt time.Time  `json:"createdAt"`
+	UpdatedAt time.Time  `json:"updatedAt"`
+}
+
+// PromotionFilter represents the filter options for promotions
+type PromotionFilter struct {
+	Scope    PromotionScope `form:"scope"`
+	TargetID *uint          `form:"targetId"`
+	Active   *bool          `form:"active"`
+	// Current only lists promotions running right now
+	Current bool `form:"current"`
+}
+
+// Apply returns the price after the promotion, and whether the promotion
+// lowers it at all
+func (p *Promotion) Apply(price Money) (Money, bool) {
+	var sale int64
+	switch p.Type {
+	case PromotionTypeSalePrice:
+		if p.Value.Currency != price.Currency {
+			return price, false
+		}
+		sale = p.Value.Amount
+	case PromotionTypeFixed:
+		if p.Value.Currency != price.Currency {
+			return price, false
+		}
+		sale = price.Amount - p.Value.Amount
+	case PromotionTypePercentage:
+		percentage, ok := new(big.Rat).SetString(strconv.FormatFloat(p.Percentage, 'f', -1, 64))
+		if !ok {
+			return price, false
+		}
+		factor := new(big.Rat).Sub(big.NewRat(100, 1), percentage)
+		factor.Quo(factor, big.NewRat(100, 1))
+		sale = roundHalfAwayFromZero(factor.Mul(factor, new(big.Rat).SetInt64(price.Amount)))
+	default:
+		return price, false
+	}
+
+	if sale < 0 {
+		sale = 0
+	}
+	if sale >= price.Amount {
+		return price, false
+	}
+	return Money{Amount: sale, Currency: price.Currency}, true
+}
+
+// BestPromotion picks the promotion that sets the sale price among those that
+// lower the price, returning nil when none does
+func BestPromotion(price Money, promotions []Promotion) (*Promotion, Money) {
+	var best *Promotion
+	bestPrice := price
+	for i := range promotions {
+		promotion := &promotions[i]
+		sale, ok := promotion.Apply(price)
+		if !ok {
+			continue
+		}
+		if best == nil || promotion.Priority > best.Priority ||
+			(promotion.Priority == best.Priority && (sale.Amount < bestPrice.Amount ||
+				(sale.Amount == bestPrice.Amount && promotion.ID < best.ID))) {
+			best, bestPrice = promotion, sale
+		}
+	}
+	return best, bestPrice
+}
@@ -0,0 +1,98 @@
+// internal/models/promotion_test.go
+package models
+
+import "testing"
+
+func TestBestPromotion(t *testing.T) {
+	price := NewMoney(2000, "EUR")
+
+	tests := []struct {
+		name       string
+		promotions []Promotion
+		wantID     uint
+		wantPrice  int64
+	}{
+		{
+			name:      "no promotions",
+			wantPrice: 2000,
+		},
+		{
+			name: "sale price",
+			promotions: []Promotion{
+				{ID: 1, Type: PromotionTypeSalePrice, Value: NewMoney(1500, "EUR")},
+			},
+			wantID:    1,
+			wantPrice: 1500,
+		},
+		{
+			name: "percentage rounds to the nearest cent",
+			promotions: []Promotion{
+				{ID: 1, Type: PromotionTypePercentage, Percentage: 33.33},
+			},
+			wantID:    1,
+			wantPrice: 1333,
+		},
+		{
+			name: "fixed amount never goes below zero",
+			promotions: []Promotion{
+				{ID: 1, Type: PromotionTypeFixed, Value: NewMoney(5000, "EUR")},
+			},
+			wantID:    1,
+			wantPrice: 0,
+		},
+		{
+			name: "lowest price wins at the same priority",
+			promotions: []Promotion{
+				{ID: 1, Type: PromotionTypePercentage, Percentage: 10},
+				{ID: 2, Type: PromotionTypeFixed, Value: NewMoney(500, "EUR")},
+			},
+			wantID:    2,
+			wantPrice: 1500,
+		},
+		{
+			name: "higher priority wins over a lower price",
+			promotions: []Promotion{
+				{ID: 1, Type: PromotionTypeSalePrice, Value: NewMoney(1000, "EUR")},
+				{ID: 2, Type: PromotionTypePercentage, Percentage: 10, Priority: 1},
+			},
+			wantID:    2,
+			wantPrice: 1800,
+		},
+		{
+			name: "oldest wins a tie",
+			promotions: []Promotion{
+				{ID: 7, Type: PromotionTypeSalePrice, Value: NewMoney(1500, "EUR")},
+				{ID: 3, Type: PromotionTypeFixed, Value: NewMoney(500, "EUR")},
+			},
+			wantID:    3,
+			wantPrice: 1500,
+		},
+		{
+			name: "promotions that do not lower the price are ignored",
+			promotions: []Promotion{
+				{ID: 1, Type: PromotionTypeSalePrice, Value: NewMoney(2500, "EUR"), Priority: 5},
+				{ID: 2, Type: PromotionTypeSalePrice, Value: NewMoney(1000, "USD"), Priority: 5},
+				{ID: 3, Type: PromotionTypePercentage, Percentage: 0, Priority: 5},
+				{ID: 4, Type: PromotionTypeFixed, Value: NewMoney(100, "EUR")},
+			},
+			wantID:    4,
+			wantPrice: 1900,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			best, sale := BestPromotion(price, tt.promotions)
+			if tt.wantID == 0 {
+				if best != nil {
+					t.Errorf("BestPromotion chose promotion %d, want none", best.ID)
+				}
+			} else if best == nil || best.ID != tt.wantID {
+				t.Errorf("BestPromotion chose %v, want promotion %d", best, tt.wantID)
+			}
+			if sale.Amount != tt.wantPrice || sale.Currency != price.Currency {
+				t.Errorf("BestPromotion price = %+v, want %d %s", sale, tt.wantPrice, price.Currency)
+			}
+		})
+	}
+}
@@ -0,0 +1,73 @@
+// internal/models/purchase_order.go
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Supplier is a company we buy stock from
+type Supplier struct {
+	ID          uint           `json:"id" gorm:"primaryKey"`
+	Name        string         `json:"name" gorm:"size:255;not null;uniqueIndex"`
+	ContactName string         `json:"contactName" gorm:"size:255"`
+	Email       string         `json:"email" gorm:"size:255"`
+	Phone       string         `json:"phone" gorm:"size:50"`
+	Address     string         `json:"address" gorm:"type:text"`
+	IsActive    bool           `json:"isActive" gorm:"default:true"`
+	CreatedAt   time.Time      `json:"createdAt"`
+	UpdatedAt   time.Time      `json:"updatedAt"`
+	DeletedAt   gorm.DeletedAt `json:"-" gorm:"index"`
+}
+
+// PurchaseOrderStatus represents the lifecycle state of a purchase order
+type PurchaseOrderStatus string
+
+const (
+	PurchaseOrderStatusDraft             PurchaseOrderStatus = "draft"
+	PurchaseOrderStatusSent              PurchaseOrderStatus = "sent"
+	PurchaseOrderStatusPartiallyReceived PurchaseOrderStatus = "partially_received"
+	PurchaseOrderStatusReceived          PurchaseOrderStatus = "received"
+	PurchaseOrderStatusCancelled         PurchaseOrderStatus = "cancelled"
+)
+
+// PurchaseOrder is an order of stock placed with a supplier
+type PurchaseOrder struct {
+	ID         uint                `json:"id" gorm:"primaryKey"`
+	Reference  string              `json:"reference" gorm:"size:100;index"`
+	SupplierID uint                `json:"supplierId" gorm:"not null;index"`
+	Supplier   *Supplier           `json:"supplier,omitempty" gorm:"foreignKey:SupplierID"`
+	Status     PurchaseOrderStatus `json:"status" gorm:"size:20;not null;index"`
+	LocationID *uint               `json:"locationId"`
+	ExpectedAt *time.Time          `json:"expectedAt"`
+	Notes      string              `json:"notes" gorm:"type:text"`
+	Lines      []PurchaseOrderLine `json:"lines" gorm:"foreignKey:PurchaseOrderID"`
+	SentAt     *time.Time          `json:"sentAt"`
+	CreatedAt  time.Time           `json:"createdAt"`
+	UpdatedAt  time.Time           `json:"updatedAt"`
+}
+
+// PurchaseOrderLine is the quantity of a single product ordered on a purchase order
+type PurchaseOrderLine struct {
+	ID               uint `json:"id" gorm:"primaryKey"`
+	PurchaseOrderID  uint `json:"purchaseOrderId" gorm:"not null;index"`
+	ProductID        uint `json:"productId" gorm:"not null;index"`
+	QuantityOrdered  int  `json:"quantityOrdered" gorm:"not null"`
+	QuantityReceived int  `json:"quantityReceived" gorm:"not null;default:0"`
+	UnitCost         Cost `json:"unitCost" gorm:"column:unit_cost_amount;not null;default:0" swaggertype:"number"`
+}
+
+// PurchaseOrderReceipt is the quantity of a purchase order line that arrived
+type PurchaseOrderReceipt struct {
+	LineID   uint `json:"lineId"`
+	Quantity int  `json:"quantity"`
+}
+
+// PurchaseOrderFilter represents the filter options for purchase orders
+type PurchaseOrderFilter struct {
+	SupplierID *uint  `form:"supplierId"`
+	Status     string `form:"status"`
+	Page       int    `form:"page,default=1"`
+	PageSize   int    `form:"pageSize,default=20"`
+}
@@ -0,0 +1,56 @@
+// internal/models/relation.go
+package models
+
+import "time"
+
+// RelationType says how a related product relates to the product it is shown with
+type RelationType string
+
+const (
+	// RelationTypeAccessoryOf points to an accessory that goes with the product,
+	// such as a screen protector for a case
+	RelationTypeAccessoryOf RelationType = "accessory_of"
+	// RelationTypeCrossSell points to a product often bought together with it
+	RelationTypeCrossSell RelationType = "cross_sell"
+	// RelationTypeUpSell points to a better, usually dearer, alternative
+	RelationTypeUpSell RelationType = "up_sell"
+	// RelationTypeReplacementFor points to a product that replaces it, such as
+	// the successor of a discontinued product
+	RelationTypeReplacementFor RelationType = "replacement_for"
+)
+
+// IsValid reports whether t is one of the known relation types
+func (t RelationType) IsValid() bool {
+	switch t {
+	case RelationTypeAccessoryOf, RelationTypeCrossSell, RelationTypeUpSell, RelationTypeReplacementFor:
+		return true
+	}
+	return false
+}
+
+// ProductRelation links a product to a related target product. Relations of
+// the same type are shown in order of their position.
+type ProductRelation struct {
+	ID        uint         `json:"id" gorm:"primaryKey"`
+	ProductID uint         `json:"productId" gorm:"not null;uniqueIndex:idx_product_relations_product_type_target,priority:1"`
+	Type      RelationType `json:"type" gorm:"size:20;not null;uniqueIndex:idx_product_relations_product_type_target,priority:2"`
+	TargetID  uint         `json:"targetId" gorm:"not null;uniqueIndex:idx_product_relations_product_type_target,priority:3;index"`
+	Target    *Product     `json:"target,omitempty" gorm:"foreignKey:TargetID"`
+	Position  int          `json:"position" gorm:"not null;default:0"`
+	CreatedAt time.Time    `json:"createdAt"`
+	UpdatedAt time.Time    `json:"updatedAt"`
+}
+
+// ProductRelationUpdate holds the details of a relation that can be changed
+// after it was created
+type ProductRelationUpdate struct {
+	Type     *RelationType `json:"type"`
+	Position *int          `json:"position"`
+}
+
+// RelatedProductFilter represents the filter options for a product's related products
+type RelatedProductFilter struct {
+	Type RelationType `form:"type"`
+	// IncludeUnavailable also returns inactive and out-of-stock targets
+	IncludeUnavailable bool `form:"includeUnavailable"`
+}
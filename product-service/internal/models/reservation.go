@@ -0,0 +1,37 @@
+// internal/models/reservation.go
+package models
+
+import "time"
+
+// ReservationStatus represents the lifecycle state of a stock reservation
+type ReservationStatus string
+
+const (
+	ReservationStatusHeld      ReservationStatus = "held"
+	ReservationStatusConfirmed ReservationStatus = "confirmed"
+	ReservationStatusReleased  ReservationStatus = "released"
+	ReservationStatusExpired   ReservationStatus = "expired"
+)
+
+// Reservation holds stock for a checkout until it is confirmed, released or expires
+type Reservation struct {
+	ID        uint              `json:"id" gorm:"primaryKey"`
+	Reference string            `json:"reference" gorm:"size:100;index"`
+	Status    ReservationStatus `json:"status" gorm:"size:20;not null;index"`
+	ExpiresAt time.Time         `json:"expiresAt" gorm:"not null;index"`
+	Items     []ReservationItem `json:"items" gorm:"foreignKey:ReservationID"`
+	CreatedAt time.Time         `json:"createdAt"`
+	UpdatedAt time.Time         `json:"updatedAt"`
+}
+
+// ReservationItem is the quantity of a single product held by a reservation.
+// Bundles are held as their components, each item recording the bundle it
+// was reserved for.
+type ReservationItem struct {
+	ID            uint  `json:"id" gorm:"primaryKey"`
+	ReservationID uint  `json:"reservationId" gorm:"not null;index"`
+	ProductID     uint  `json:"productId" gorm:"not null;index"`
+	BundleID      *uint `json:"bundleId,omitempty"`
+	LocationID    *uint `json:"locationId"`
+	Quantity      int   `json:"quantity" gorm:"not null"`
+}
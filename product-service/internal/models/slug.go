@@ -0,0 +1,22 @@
+// internal/models/slug.go
+package models
+
+import "time"
+
+// SlugEntity names the kind of record a slug belongs to
+type SlugEntity string
+
+const (
+	SlugEntityProduct  SlugEntity = "product"
+	SlugEntityCategory SlugEntity = "category"
+)
+
+// SlugRedirect remembers a slug a record used to have, so links to it keep
+// resolving after the record is renamed
+type SlugRedirect struct {
+	ID        uint       `json:"id" gorm:"primaryKey"`
+	Entity    SlugEntity `json:"entity" gorm:"size:20;not null;uniqueIndex:idx_slug_redirects_entity_slug,priority:1"`
+	Slug      string     `json:"slug" gorm:"size:255;not null;uniqueIndex:idx_slug_redirects_entity_slug,priority:2"`
+	TargetID  uint       `json:"targetId" gorm:"not null;index"`
+	CreatedAt time.Time  `json:"createdAt"`
+}
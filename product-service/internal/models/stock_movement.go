@@ -0,0 +1,77 @@
+// internal/models/stock_movement.go
+package models
+
+import "time"
+
+// StockReason is the reason code recorded with every stock movement
+type StockReason string
+
+const (
+	StockReasonSale       StockReason = "sale"
+	StockReasonReturn     StockReason = "return"
+	StockReasonRestock    StockReason = "restock"
+	StockReasonAdjustment StockReason = "adjustment"
+	StockReasonDamage     StockReason = "damage"
+	StockReasonTransfer   StockReason = "transfer"
+	StockReasonCount      StockReason = "count"
+)
+
+// IsValid reports whether r is one of the known reason codes
+func (r StockReason) IsValid() bool {
+	switch r {
+	case StockReasonSale, StockReasonReturn, StockReasonRestock, StockReasonAdjustment, StockReasonDamage,
+		StockReasonTransfer, StockReasonCount:
+		return true
+	}
+	return false
+}
+
+// IsPublic reports whether r may be sent to the stock endpoints. Transfers
+// and stock takes record their own reasons, which are not accepted there.
+func (r StockReason) IsPublic() bool {
+	switch r {
+	case StockReasonSale, StockReasonReturn, StockReasonRestock, StockReasonAdjustment, StockReasonDamage:
+		return true
+	}
+	return false
+}
+
+// StockMovement is a ledger entry recording a single change to a product's stock level
+type StockMovement struct {
+	ID             uint        `json:"id" gorm:"primaryKey"`
+	ProductID      uint        `json:"productId" gorm:"not null;index:idx_stock_movements_product_created,priority:1"`
+	LocationID     *uint       `json:"locationId" gorm:"index"`
+	Delta          int         `json:"delta" gorm:"not null"`
+	ResultingLevel int         `json:"resultingLevel" gorm:"not null"`
+	LocationLevel  int         `json:"locationLevel" gorm:"not null;default:0"`
+	Reason         StockReason `json:"reason" gorm:"size:20;not null"`
+	ReferenceID    string      `json:"referenceId" gorm:"size:100;index"`
+	Actor          string      `json:"actor" gorm:"size:100"`
+	CreatedAt      time.Time   `json:"createdAt" gorm:"index:idx_stock_movements_product_created,priority:2"`
+	// Components lists the movements of a bundle's components. A bundle holds
+	// no stock of its own, so only these are recorded in the ledger.
+	Components []StockMovement `json:"components,omitempty" gorm:"-"`
+}
+
+// StockChange describes a stock mutation to apply and record in the ledger.
+// A zero LocationID applies the change at the default location. UnitCost is
+// the cost of incoming units; when nil the product's latest cost is used.
+type StockChange struct {
+	ProductID   uint
+	LocationID  uint
+	Delta       int
+	Reason      StockReason
+	ReferenceID string
+	Actor       string
+	UnitCost    *Cost
+}
+
+// StockMovementFilter represents the filter options for a product's stock history
+type StockMovementFilter struct {
+	From       *time.Time `form:"from" time_format:"2006-01-02" time_utc:"1"`
+	To         *time.Time `form:"to" time_format:"2006-01-02" time_utc:"1"`
+	Reason     string     `form:"reason"`
+	LocationID *uint      `form:"locationId"`
+	Page       int        `form:"page,default=1"`
+	PageSize   int        `form:"pageSize,default=20"`
+}
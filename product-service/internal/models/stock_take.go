@@ -0,0 +1,93 @@
+// internal/models/stock_take.go
+package models
+
+import "time"
+
+// StockTakeStatus represents the lifecycle state of a stock-take session
+type StockTakeStatus string
+
+const (
+	StockTakeStatusOpen      StockTakeStatus = "open"
+	StockTakeStatusCommitted StockTakeStatus = "committed"
+	StockTakeStatusCancelled StockTakeStatus = "cancelled"
+)
+
+// StockTake is a physical count session for one location, optionally limited
+// to a single category. Opening a session snapshots the stock levels it covers.
+type StockTake struct {
+	ID         uint            `json:"id" gorm:"primaryKey"`
+	LocationID uint            `json:"locationId" gorm:"not null;index"`
+	CategoryID *uint           `json:"categoryId" gorm:"index"`
+	Status     StockTakeStatus `json:"status" gorm:"size:20;not null;index"`
+	Notes      string          `json:"notes" gorm:"type:text"`
+	Actor      string          `json:"actor" gorm:"size:100"`
+	// SnapshotMovementID is the last ledger entry when the session was opened.
+	// It is taken while the covered products are locked, so every movement of
+	// theirs with a higher ID happened after the snapshot.
+	SnapshotMovementID uint            `json:"snapshotMovementId" gorm:"not null;default:0"`
+	Lines              []StockTakeLine `json:"lines,omitempty" gorm:"foreignKey:StockTakeID"`
+	CommittedAt        *time.Time      `json:"committedAt"`
+	CreatedAt          time.Time       `json:"createdAt"`
+	UpdatedAt          time.Time       `json:"updatedAt"`
+}
+
+// StockTakeLine is the expected and counted quantity of one product in a stock take
+type StockTakeLine struct {
+	ID              uint       `json:"id" gorm:"primaryKey"`
+	StockTakeID     uint       `json:"stockTakeId" gorm:"not null;uniqueIndex:idx_stock_take_lines_product,priority:1"`
+	ProductID       uint       `json:"productId" gorm:"not null;uniqueIndex:idx_stock_take_lines_product,priority:2"`
+	SKU             string     `json:"sku" gorm:"size:50"`
+	ExpectedLevel   int        `json:"expectedLevel" gorm:"not null;default:0"`
+	CountedQuantity *int       `json:"countedQuantity"`
+	CountedAt       *time.Time `json:"countedAt"`
+	CountedBy       string     `json:"countedBy" gorm:"size:100"`
+	// CountedMovementID is the product's last ledger entry when it was
+	// counted; movements up to it happened before the count
+	CountedMovementID uint `json:"countedMovementId" gorm:"not null;default:0"`
+}
+
+// StockTakeCount is a counted quantity submitted for a stock take. The product
+// is identified by ProductID or, when that is zero, by SKU.
+type StockTakeCount struct {
+	ProductID uint   `json:"productId"`
+	SKU       string `json:"sku"`
+	Quantity  int    `json:"quantity"`
+}
+
+// StockTakeVarianceLine compares a product's counted quantity with the stock
+// level it had when it was counted: the snapshot level plus the movements
+// between the snapshot and the count. Movements after the count are kept.
+type StockTakeVarianceLine struct {
+	ProductID     uint   `json:"productId"`
+	SKU           string `json:"sku"`
+	Name          string `json:"name"`
+	ExpectedLevel int    `json:"expectedLevel"`
+	// CountedLevel is the stock level the count is measured against
+	CountedLevel    *int `json:"countedLevel"`
+	CurrentLevel    int  `json:"currentLevel"`
+	CountedQuantity *int `json:"countedQuantity"`
+	Variance        *int `json:"variance"`
+	// ChangedSinceOpen is set when the product's stock at the location moved
+	// after the session was opened, so the count may need to be redone.
+	// Committing such lines has to be forced.
+	ChangedSinceOpen bool `json:"changedSinceOpen"`
+}
+
+// StockTakeVarianceReport lists the variance of every line in a stock take
+type StockTakeVarianceReport struct {
+	StockTakeID    uint                    `json:"stockTakeId"`
+	Status         StockTakeStatus         `json:"status"`
+	LocationID     uint                    `json:"locationId"`
+	CountedLines   int                     `json:"countedLines"`
+	UncountedLines int                     `json:"uncountedLines"`
+	ChangedLines   int                     `json:"changedLines"`
+	Lines          []StockTakeVarianceLine `json:"lines"`
+}
+
+// StockTakeFilter represents the filter options for stock takes
+type StockTakeFilter struct {
+	LocationID *uint  `form:"locationId"`
+	Status     string `form:"status"`
+	Page       int    `form:"page,default=1"`
+	PageSize   int    `form:"pageSize,default=20"`
+}
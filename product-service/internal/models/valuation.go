@@ -0,0 +1,112 @@
+// internal/models/valuation.go
+package models
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Cost is a unit cost or stock value held as an integer number of minor units
+// of DefaultCurrency, so FIFO layers sum up exactly. In JSON it stays a plain
+// decimal number such as 12.50; a decimal string is also accepted.
+type Cost int64
+
+// String formats the cost as a decimal, such as "12.50"
+func (c Cost) String() string {
+	return NewMoney(int64(c), DefaultCurrency).String()
+}
+
+// MarshalJSON writes the cost as a decimal number
+func (c Cost) MarshalJSON() ([]byte, error) {
+	return []byte(c.String()), nil
+}
+
+// UnmarshalJSON reads a decimal number or string; more decimal places than
+// DefaultCurrency has are rejected rather than rounded
+func (c *Cost) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+	var value string
+	if len(data) > 0 && data[0] == '"' {
+		if err := json.Unmarshal(data, &value); err != nil {
+			return err
+		}
+	} else {
+		var number json.Number
+		if err := json.Unmarshal(data, &number); err != nil {
+			return fmt.Errorf("invalid cost %s", data)
+		}
+		value = number.String()
+	}
+	money, err := ParseMoney(value, DefaultCurrency)
+	if err != nil {
+		return err
+	}
+	*c = Cost(money.Amount)
+	return nil
+}
+
+// CostLayer is a quantity of a product that came into stock at a single unit
+// cost. Outgoing stock consumes the oldest layers first (FIFO).
+type CostLayer struct {
+	ID         uint      `json:"id" gorm:"primaryKey"`
+	ProductID  uint      `json:"productId" gorm:"not null;index:idx_cost_layers_product_remaining,priority:1"`
+	MovementID uint      `json:"movementId" gorm:"not null;index"`
+	Quantity   int       `json:"quantity" gorm:"not null"`
+	Remaining  int       `json:"remaining" gorm:"not null;index:idx_cost_layers_product_remaining,priority:2"`
+	UnitCost   Cost      `json:"unitCost" gorm:"column:unit_cost_amount;not null;default:0" swaggertype:"number"`
+	CreatedAt  time.Time `json:"createdAt" gorm:"index"`
+}
+
+// CostConsumption records units taken out of a cost layer by a stock movement
+type CostConsumption struct {
+	ID         uint      `json:"id" gorm:"primaryKey"`
+	LayerID    uint      `json:"layerId" gorm:"not null;index"`
+	ProductID  uint      `json:"productId" gorm:"not null;index"`
+	MovementID uint      `json:"movementId" gorm:"not null;index"`
+	Quantity   int       `json:"quantity" gorm:"not null"`
+	UnitCost   Cost      `json:"unitCost" gorm:"column:unit_cost_amount;not null;default:0" swaggertype:"number"`
+	CreatedAt  time.Time `json:"createdAt" gorm:"index"`
+}
+
+// CostDrift is a product whose stock level differs from the units left in its
+// cost layers
+type CostDrift struct {
+	ProductID  uint   `json:"productId"`
+	SKU        string `json:"sku"`
+	StockLevel int    `json:"stockLevel"`
+	Layered    int    `json:"layered"`
+}
+
+// InventoryValuationLine is the quantity and cost value of one product's stock
+type InventoryValuationLine struct {
+	ProductID       uint   `json:"productId"`
+	SKU             string `json:"sku"`
+	Name            string `json:"name"`
+	CategoryID      uint   `json:"categoryId"`
+	CategoryName    string `json:"categoryName"`
+	Quantity        int    `json:"quantity"`
+	AverageUnitCost Cost   `json:"averageUnitCost" swaggertype:"number"`
+	Value           Cost   `json:"value" swaggertype:"number"`
+}
+
+// InventoryValuationCategory is the total cost value of the stock in a category
+type InventoryValuationCategory struct {
+	CategoryID   uint   `json:"categoryId"`
+	CategoryName string `json:"categoryName"`
+	Quantity     int    `json:"quantity"`
+	Value        Cost   `json:"value" swaggertype:"number"`
+}
+
+// InventoryValuation is the cost value of the stock on hand at a point in time
+type InventoryValuation struct {
+	AsOf          time.Time                    `json:"asOf"`
+	TotalQuantity int                          `json:"totalQuantity"`
+	TotalValue    Cost                         `json:"totalValue" swaggertype:"number"`
+	Products      []InventoryValuationLine     `json:"products"`
+	Categories    []InventoryValuationCategory `json:"categories"`
+}
@@ -0,0 +1,39 @@
+// internal/models/valuation_test.go
+package models
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestCostJSON(t *testing.T) {
+	tests := []struct {
+		data    string
+		want    Cost
+		wantErr bool
+	}{
+		{data: `12.5`, want: 1250},
+		{data: `"12.50"`, want: 1250},
+		{data: `0`, want: 0},
+		{data: `12.505`, wantErr: true},
+		{data: `"abc"`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		var got Cost
+		err := json.Unmarshal([]byte(tt.data), &got)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("unmarshalling %s gave %d, want an error", tt.data, got)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("unmarshalling %s gave %d (%v), want %d", tt.data, got, err, tt.want)
+			continue
+		}
+		if data, _ := json.Marshal(got); string(data) != got.String() {
+			t.Errorf("marshalling %d gave %s, want %s", got, data, got.String())
+		}
+	}
+}
@@ -0,0 +1,29 @@
+// internal/notification/dispatcher.go
+package notification
+
+import (
+	"log"
+
+	"phone-accessories/internal/models"
+)
+
+// Dispatcher fans events out to a set of sinks without blocking the caller
+type Dispatcher struct {
+	sinks []Sink
+}
+
+func NewDispatcher(sinks ...Sink) *Dispatcher {
+	return &Dispatcher{sinks: sinks}
+}
+
+// LowStock delivers a low-stock event to every sink in the background; delivery
+// failures are logged rather than failing the stock change that caused them
+func (d *Dispatcher) LowStock(event models.LowStockEvent) {
+	for _, sink := range d.sinks {
+		go func(sink Sink) {
+			if err := sink.NotifyLowStock(event); err != nil {
+				log.Printf("Failed to deliver low-stock event for product %d: %v", event.ProductID, err)
+			}
+		}(sink)
+	}
+}
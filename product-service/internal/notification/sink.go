@@ -0,0 +1,26 @@
+// internal/notification/sink.go
+package notification
+
+import (
+	"log"
+
+	"phone-accessories/internal/models"
+)
+
+// Sink receives inventory events, such as low-stock alerts
+type Sink interface {
+	NotifyLowStock(event models.LowStockEvent) error
+}
+
+// LogSink writes events to the application log, which is handy for local development
+type LogSink struct{}
+
+func NewLogSink() *LogSink {
+	return &LogSink{}
+}
+
+func (s *LogSink) NotifyLowStock(event models.LowStockEvent) error {
+	log.Printf("Low stock: product %d (%s) dropped from %d to %d, below its reorder point of %d",
+		event.ProductID, event.SKU, event.PreviousLevel, event.StockLevel, event.ReorderPoint)
+	return nil
+}
@@ -0,0 +1,50 @@
+// internal/notification/webhook.go
+package notification
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"phone-accessories/internal/models"
+)
+
+// WebhookSink posts events as JSON to an HTTP endpoint
+type WebhookSink struct {
+	url    string
+	client *http.Client
+}
+
+// webhookPayload wraps an event with its type so a single endpoint can receive several kinds of events
+type webhookPayload struct {
+	Type string      `json:"type"`
+	Data interface{} `json:"data"`
+}
+
+func NewWebhookSink(url string, timeout time.Duration) *WebhookSink {
+	return &WebhookSink{url: url, client: &http.Client{Timeout: timeout}}
+}
+
+func (s *WebhookSink) NotifyLowStock(event models.LowStockEvent) error {
+	return s.post(webhookPayload{Type: "inventory.low_stock", Data: event})
+}
+
+func (s *WebhookSink) post(payload webhookPayload) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	resp, err := s.client.Post(s.url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook %s responded with status %d", s.url, resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+// internal/repository/attribute_repository.go
+package repository
+
+import (
+	"errors"
+
+	"gorm.io/gorm"
+
+	"phone-accessories/internal/models"
+)
+
+type AttributeRepository interface {
+	Create(definition *models.AttributeDefinition) error
+	GetByID(id uint) (*models.AttributeDefinition, error)
+	Update(definition *models.AttributeDefinition) error
+	Delete(id uint) error
+	EffectiveSchema(categoryID uint) ([]models.AttributeDefinition, error)
+}
+
+type attributeRepository struct {
+	db *gorm.DB
+}
+
+func NewAttributeRepository(db *gorm.DB) AttributeRepository {
+	return &attributeRepository{db: db}
+}
+
+func (r *attributeRepository) Create(definition *models.AttributeDefinition) error {
+	return r.db.Create(definition).Error
+}
+
+func (r *attributeRepository) GetByID(id uint) (*models.AttributeDefinition, error) {
+	var definition models.AttributeDefinition
+	if err := r.db.First(&definition, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, models.ErrAttributeNotFound
+		}
+		return nil, err
+	}
+	return &definition, nil
+}
+
+func (r *attributeRepository) Update(definition *models.AttributeDefinition) error {
+	if _, err := r.GetByID(definition.ID); err != nil {
+		return err
+	}
+	return r.db.Model(definition).Select("*").Omit("id", "category_id", "created_at").Updates(definition).Error
+}
+
+func (r *attributeRepository) Delete(id uint) error {
+	result := r.db.Delete(&models.AttributeDefinition{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return models.ErrAttributeNotFound
+	}
+	return nil
+}
+
+// EffectiveSchema returns the attribute definitions that apply to products of
+// the category: its own and those inherited from the categories above it,
+// where the definition closest to the category wins for each key
+func (r *attributeRepository) EffectiveSchema(categoryID uint) ([]models.AttributeDefinition, error) {
+	var definitions []models.AttributeDefinition
+	if err := r.db.Raw(categoryAncestorsCTE+`
+		SELECT * FROM (
+			SELECT DISTINCT ON (d.key) d.* FROM attribute_definitions d
+			JOIN ancestors a ON a.id = d.category_id
+			ORDER BY d.key, a.depth ASC
+		) schema
+		ORDER BY position ASC, key ASC`, categoryID).
+		Scan(&definitions).Error; err != nil {
+		return nil, err
+	}
+	return definitions, nil
+}
@@ -0,0 +1,89 @@
+// internal/repository/brand_repository.go
+package repository
+
+import (
+	"errors"
+
+	"gorm.io/gorm"
+
+	"phone-accessories/internal/models"
+)
+
+type BrandRepository interface {
+	Create(brand *models.Brand) error
+	GetByID(id uint) (*models.Brand, error)
+	Update(brand *models.Brand) error
+	Delete(id uint) error
+	List(filter models.BrandFilter) ([]models.Brand, error)
+}
+
+type brandRepository struct {
+	db *gorm.DB
+}
+
+func NewBrandRepository(db *gorm.DB) BrandRepository {
+	return &brandRepository{db: db}
+}
+
+func (r *brandRepository) Create(brand *models.Brand) error {
+	return r.db.Create(brand).Error
+}
+
+func (r *brandRepository) GetByID(id uint) (*models.Brand, error) {
+	var brand models.Brand
+	if err := r.db.First(&brand, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, models.ErrBrandNotFound
+		}
+		return nil, err
+	}
+	return &brand, nil
+}
+
+func (r *brandRepository) Update(brand *models.Brand) error {
+	if _, err := r.GetByID(brand.ID); err != nil {
+		return err
+	}
+	return r.db.Model(brand).Select("*").Omit("id", "created_at").Updates(brand).Error
+}
+
+func (r *brandRepository) Delete(id uint) error {
+	// Brands still referenced by device models or products can only be deactivated
+	var count int64
+	if err := r.db.Model(&models.DeviceModel{}).Where("brand_id = ?", id).Count(&count).Error; err != nil {
+		return err
+	}
+	if count == 0 {
+		if err := r.db.Unscoped().Model(&models.Product{}).Where("brand_id = ?", id).Count(&count).Error; err != nil {
+			return err
+		}
+	}
+	if count > 0 {
+		return models.ErrBrandInUse
+	}
+
+	result := r.db.Delete(&models.Brand{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return models.ErrBrandNotFound
+	}
+	return nil
+}
+
+func (r *brandRepository) List(filter models.BrandFilter) ([]models.Brand, error) {
+	query := r.db.Model(&models.Brand{})
+	if filter.Active != nil {
+		query = query.Where("is_active = ?", *filter.Active)
+	}
+	if filter.Query != "" {
+		query = query.Where("name ILIKE ?", "%"+filter.Query+"%")
+	}
+
+	var brands []models.Brand
+	if err := query.Order("name ASC").Find(&brands).Error; err != nil {
+		return nil, err
+	}
+	return brands, nil
+}
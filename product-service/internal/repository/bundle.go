@@ -0,0 +1,161 @@
+// internal/repository/bundle.go
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+
+	"phone-accessories/internal/models"
+)
+
+// applyBundleChange sells a bundle, or takes one back, by applying the change
+// to each of its components in the caller's transaction, so either every
+// component's stock moves or none does. The movement returned for the bundle
+// only summarises those of its components and is not recorded itself.
+func applyBundleChange(tx *gorm.DB, bundleID uint, change models.StockChange) (*models.StockMovement, error) {
+	if change.Reason != models.StockReasonSale && change.Reason != models.StockReasonReturn {
+		return nil, fmt.Errorf("%w: %d", models.ErrProductIsBundle, bundleID)
+	}
+
+	var components []models.BundleComponent
+	if err := tx.Where("bundle_id = ?", bundleID).Order("id ASC").Find(&components).Error; err != nil {
+		return nil, err
+	}
+	if len(components) == 0 {
+		return nil, fmt.Errorf("bundle %d has no components", bundleID)
+	}
+
+	locationID, err := resolveLocationID(tx, change.LocationID)
+	if err != nil {
+		return nil, err
+	}
+	// Tie the component movements together in the ledger
+	referenceID := change.ReferenceID
+	if referenceID == "" {
+		referenceID = fmt.Sprintf("bundle:%d", bundleID)
+	}
+
+	movement := &models.StockMovement{
+		ProductID:   bundleID,
+		LocationID:  &locationID,
+		Delta:       change.Delta,
+		Reason:      change.Reason,
+		ReferenceID: referenceID,
+		Actor:       change.Actor,
+		CreatedAt:   time.Now().UTC(),
+	}
+	for _, component := range components {
+		componentMovement, err := applyStockChange(tx, models.StockChange{
+			ProductID:   component.ComponentID,
+			LocationID:  locationID,
+			Delta:       change.Delta * component.Quantity,
+			Reason:      change.Reason,
+			ReferenceID: referenceID,
+			Actor:       change.Actor,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("bundle %d: %w", bundleID, err)
+		}
+		movement.Components = append(movement.Components, *componentMovement)
+	}
+	return movement, nil
+}
+
+// replaceBundleComponents replaces the components of a bundle, checking that
+// each one is a product that holds stock of its own
+func replaceBundleComponents(tx *gorm.DB, bundle *models.Product) error {
+	if err := tx.Where("bundle_id = ?", bundle.ID).Delete(&models.BundleComponent{}).Error; err != nil {
+		return err
+	}
+
+	for i := range bundle.Components {
+		component := &bundle.Components[i]
+		var product models.Product
+		if err := tx.Select("id", "is_bundle").First(&product, component.ComponentID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return fmt.Errorf("%w: %d", models.ErrProductNotFound, component.ComponentID)
+			}
+			return err
+		}
+		if product.IsBundle {
+			return fmt.Errorf("product %d is a bundle and cannot be part of another bundle", product.ID)
+		}
+		var variants int64
+		if err := tx.Model(&models.Product{}).Where("parent_id = ?", product.ID).Count(&variants).Error; err != nil {
+			return err
+		}
+		if variants > 0 {
+			return fmt.Errorf("%w: add one of the variants of product %d to the bundle instead", models.ErrProductHasVariants, product.ID)
+		}
+
+		component.ID = 0
+		component.BundleID = bundle.ID
+		component.Component = nil
+	}
+	if len(bundle.Components) == 0 {
+		return nil
+	}
+	return tx.Create(&bundle.Components).Error
+}
+
+// attachBundleAvailability loads the components of the bundles among the
+// products and derives each bundle's availability from them
+func attachBundleAvailability(db *gorm.DB, products []models.Product) error {
+	var ids []uint
+	for _, product := range products {
+		if product.IsBundle {
+			ids = append(ids, product.ID)
+		}
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	var components []models.BundleComponent
+	if err := db.Preload("Component").Where("bundle_id IN ?", ids).
+		Order("id ASC").Find(&components).Error; err != nil {
+		return err
+	}
+	byBundle := make(map[uint][]models.BundleComponent, len(ids))
+	for _, component := range components {
+		byBundle[component.BundleID] = append(byBundle[component.BundleID], component)
+	}
+	for i := range products {
+		if products[i].IsBundle {
+			products[i].Components = byBundle[products[i].ID]
+			products[i].DeriveBundleAvailability(products[i].Components)
+		}
+	}
+	return nil
+}
+
+// expandBundleItems replaces the reservation items for bundles with items for
+// their components, so the hold, its confirmation and its release all act on
+// the products that actually hold the stock
+func expandBundleItems(tx *gorm.DB, items []models.ReservationItem) ([]models.ReservationItem, error) {
+	expanded := make([]models.ReservationItem, 0, len(items))
+	for _, item := range items {
+		var components []models.BundleComponent
+		if err := tx.Where("bundle_id = ?", item.ProductID).Order("id ASC").Find(&components).Error; err != nil {
+			return nil, err
+		}
+		if len(components) == 0 {
+			expanded = append(expanded, item)
+			continue
+		}
+
+		bundleID := item.ProductID
+		for _, component := range components {
+			expanded = append(expanded, models.ReservationItem{
+				ProductID:  component.ComponentID,
+				BundleID:   &bundleID,
+				LocationID: item.LocationID,
+				Quantity:   item.Quantity * component.Quantity,
+			})
+		}
+	}
+	return expanded, nil
+}
@@ -5,8 +5,10 @@ import (
 	"errors"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 
 	"phone-accessories/internal/models"
+	"phone-accessories/internal/slug"
 )
 
 type CategoryRepository interface {
@@ -15,8 +17,29 @@ type CategoryRepository interface {
 	Update(category *models.Category) error
 	Delete(id uint) error
 	List() ([]models.Category, error)
+	Ancestors(id uint) ([]models.Category, error)
+	GetBySlug(slug string) (*models.Category, error)
+	EnsureSlugs() error
 }
 
+// categoryAncestorsCTE defines "ancestors" as a category and every category
+// above it, with the category itself at depth 1. The path guards against
+// looping forever over a cycle already in the data.
+const categoryAncestorsCTE = `WITH RECURSIVE ancestors AS (
+	SELECT id, parent_id, ARRAY[id] AS path, 1 AS depth FROM categories WHERE id = ? AND deleted_at IS NULL
+	UNION ALL
+	SELECT c.id, c.parent_id, a.path || c.id, a.depth + 1 FROM categories c
+	JOIN ancestors a ON c.id = a.parent_id
+	WHERE c.deleted_at IS NULL AND NOT c.id = ANY(a.path)
+)`
+
+// categorySubtreeSQL selects the ID of a category and of every category below it
+const categorySubtreeSQL = `WITH RECURSIVE subtree AS (
+	SELECT id FROM categories WHERE id = ? AND deleted_at IS NULL
+	UNION
+	SELECT c.id FROM categories c JOIN subtree s ON c.parent_id = s.id WHERE c.deleted_at IS NULL
+) SELECT id FROM subtree`
+
 type categoryRepository struct {
 	db *gorm.DB
 }
@@ -26,14 +49,22 @@ func NewCategoryRepository(db *gorm.DB) CategoryRepository {
 }
 
 func (r *categoryRepository) Create(category *models.Category) error {
-	return r.db.Create(category).Error
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		category.Slug, err = assignSlug(tx, models.SlugEntityCategory, "categories", 0,
+			slug.Derive(category.Slug, category.Name, "", "", "category"), "")
+		if err != nil {
+			return err
+		}
+		return tx.Create(category).Error
+	})
 }
 
 func (r *categoryRepository) GetByID(id uint) (*models.Category, error) {
 	var category models.Category
 	if err := r.db.Preload("Parent").First(&category, id).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("category not found")
+			return nil, models.ErrCategoryNotFound
 		}
 		return nil, err
 	}
@@ -41,7 +72,89 @@ func (r *categoryRepository) GetByID(id uint) (*models.Category, error) {
 }
 
 func (r *categoryRepository) Update(category *models.Category) error {
-	return r.db.Save(category).Error
+	// Only apply the update if nobody changed the category since the caller read
+	// it. Requests without a version, from clients written before versions
+	// existed, are not checked and update whatever version is stored.
+	sent := category.Version
+	version := sent
+	category.Version = version + 1
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := checkCategoryParent(tx, category); err != nil {
+			return err
+		}
+
+		// A renamed category gets a new slug and keeps the old one as a redirect
+		var current models.Category
+		query := tx.Select("slug", "name", "version").Where("id = ?", category.ID)
+		if sent == 0 {
+			// Lock the row so that the version read is the one updated
+			query = query.Clauses(clause.Locking{Strength: "UPDATE"})
+		}
+		if err := query.Limit(1).Find(&current).Error; err != nil {
+			return err
+		}
+		if sent == 0 {
+			version = current.Version
+			category.Version = version + 1
+		}
+		var err error
+		category.Slug, err = assignSlug(tx, models.SlugEntityCategory, "categories", category.ID,
+			slug.Derive(category.Slug, category.Name, current.Slug, current.Name, "category"), current.Slug)
+		if err != nil {
+			return err
+		}
+
+		result := tx.Model(category).Where("version = ?", version).
+			Select("*").Omit("id", "created_at", "deleted_at", clause.Associations).
+			Updates(category)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			if _, err := r.GetByID(category.ID); err != nil {
+				return err
+			}
+			return models.ErrVersionConflict
+		}
+		return nil
+	})
+	if err != nil {
+		category.Version = sent
+		return err
+	}
+	return nil
+}
+
+// checkCategoryParent makes sure a category's new parent exists and is not
+// the category itself or one of its subcategories. Moves are serialised with
+// a table lock that still lets reads through, so two concurrent moves cannot
+// each pass the check and together close a loop.
+func checkCategoryParent(tx *gorm.DB, category *models.Category) error {
+	if category.ParentID == nil {
+		return nil
+	}
+	if *category.ParentID == category.ID {
+		return models.ErrCategoryCycle
+	}
+	if err := tx.Exec("LOCK TABLE categories IN SHARE ROW EXCLUSIVE MODE").Error; err != nil {
+		return err
+	}
+
+	var ancestorIDs []uint
+	if err := tx.Raw(categoryAncestorsCTE+` SELECT id FROM ancestors`, *category.ParentID).
+		Scan(&ancestorIDs).Error; err != nil {
+		return err
+	}
+	if len(ancestorIDs) == 0 {
+		return errors.New("parent category does not exist")
+	}
+	for _, id := range ancestorIDs {
+		if id == category.ID {
+			return models.ErrCategoryCycle
+		}
+	}
+	return nil
 }
 
 func (r *categoryRepository) Delete(id uint) error {
@@ -54,6 +167,13 @@ func (r *categoryRepository) Delete(id uint) error {
 	if count > 0 {
 		return errors.New("cannot delete category with associated products")
 	}
+
+	if err := r.db.Model(&models.Category{}).Where("parent_id = ?", id).Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return errors.New("cannot delete category with subcategories")
+	}
 	
 	return r.db.Delete(&models.Category{}, id).Error
 }
@@ -65,3 +185,34 @@ func (r *categoryRepository) List() ([]models.Category, error) {
 	}
 	return categories, nil
 }
+
+// Ancestors returns the path from the root category down to the given one,
+// ending with the category itself
+func (r *categoryRepository) Ancestors(id uint) ([]models.Category, error) {
+	var categories []models.Category
+	if err := r.db.Raw(categoryAncestorsCTE+`
+		SELECT c.* FROM ancestors a JOIN categories c ON c.id = a.id
+		ORDER BY a.depth DESC`, id).
+		Scan(&categories).Error; err != nil {
+		return nil, err
+	}
+	return categories, nil
+}
+
+// GetBySlug returns the category with the slug, or the category that used to
+// have it before being renamed
+func (r *categoryRepository) GetBySlug(value string) (*models.Category, error) {
+	id, err := findBySlug(r.db, models.SlugEntityCategory, "categories", value)
+	if err != nil {
+		return nil, err
+	}
+	if id == 0 {
+		return nil, models.ErrCategoryNotFound
+	}
+	return r.GetByID(id)
+}
+
+// EnsureSlugs gives categories created before slugs existed a slug
+func (r *categoryRepository) EnsureSlugs() error {
+	return backfillSlugs(r.db, models.SlugEntityCategory, "categories", "category")
+}
@@ -0,0 +1,192 @@
+// internal/repository/device_repository.go
+package repository
+
+import (
+	"errors"
+	"math"
+
+	"gorm.io/gorm"
+
+	"phone-accessories/internal/models"
+)
+
+type DeviceRepository interface {
+	Create(device *models.DeviceModel) error
+	GetByID(id uint) (*models.DeviceModel, error)
+	Update(device *models.DeviceModel) error
+	Delete(id uint) error
+	List(filter models.DeviceFilter) (*models.PaginatedResponse, error)
+	ListUnlinkedCompatibility() ([]models.CompatibilityValue, error)
+	LinkCompatibility(productID uint, devices []models.DeviceModel) error
+	BackfillOnce(backfill func(repo DeviceRepository) error) error
+}
+
+type deviceRepository struct {
+	db *gorm.DB
+}
+
+func NewDeviceRepository(db *gorm.DB) DeviceRepository {
+	return &deviceRepository{db: db}
+}
+
+func (r *deviceRepository) Create(device *models.DeviceModel) error {
+	if err := r.db.Omit("Brand").Create(device).Error; err != nil {
+		return err
+	}
+	return r.db.Preload("Brand").First(device, device.ID).Error
+}
+
+func (r *deviceRepository) GetByID(id uint) (*models.DeviceModel, error) {
+	var device models.DeviceModel
+	if err := r.db.Preload("Brand").First(&device, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, models.ErrDeviceNotFound
+		}
+		return nil, err
+	}
+	return &device, nil
+}
+
+func (r *deviceRepository) Update(device *models.DeviceModel) error {
+	if _, err := r.GetByID(device.ID); err != nil {
+		return err
+	}
+	if err := r.db.Model(device).Select("*").Omit("id", "created_at", "Brand").Updates(device).Error; err != nil {
+		return err
+	}
+	return r.db.Preload("Brand").First(device, device.ID).Error
+}
+
+func (r *deviceRepository) Delete(id uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		// Products simply stop listing the device as compatible
+		if err := tx.Exec("DELETE FROM product_devices WHERE device_model_id = ?", id).Error; err != nil {
+			return err
+		}
+		result := tx.Delete(&models.DeviceModel{}, id)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return models.ErrDeviceNotFound
+		}
+		return nil
+	})
+}
+
+func (r *deviceRepository) List(filter models.DeviceFilter) (*models.PaginatedResponse, error) {
+	var devices []models.DeviceModel
+	var totalItems int64
+
+	query := r.db.Model(&models.DeviceModel{})
+	if filter.BrandID != nil {
+		query = query.Where("brand_id = ?", *filter.BrandID)
+	}
+	if filter.Query != "" {
+		query = query.Where("name ILIKE ?", "%"+filter.Query+"%")
+	}
+
+	if err := query.Count(&totalItems).Error; err != nil {
+		return nil, err
+	}
+
+	if filter.Page < 1 {
+		filter.Page = 1
+	}
+	if filter.PageSize < 1 {
+		filter.PageSize = 20
+	}
+	offset := (filter.Page - 1) * filter.PageSize
+
+	if err := query.Preload("Brand").Order("brand_id ASC, name ASC").
+		Offset(offset).Limit(filter.PageSize).Find(&devices).Error; err != nil {
+		return nil, err
+	}
+
+	totalPages := int(math.Ceil(float64(totalItems) / float64(filter.PageSize)))
+	return &models.PaginatedResponse{
+		Items:      devices,
+		Page:       filter.Page,
+		PageSize:   filter.PageSize,
+		TotalItems: totalItems,
+		TotalPages: totalPages,
+	}, nil
+}
+
+// ListUnlinkedCompatibility returns the free-text compatible attribute of every
+// product that has no device links yet. Products changed since the first
+// device model existed are left out, as their links, or the lack of them, were
+// set by whoever changed them.
+func (r *deviceRepository) ListUnlinkedCompatibility() ([]models.CompatibilityValue, error) {
+	var values []models.CompatibilityValue
+	if err := r.db.Model(&models.Product{}).
+		Select("id AS product_id, attributes->>'compatible' AS value").
+		Where("attributes->>'compatible' IS NOT NULL").
+		Where("NOT EXISTS (SELECT 1 FROM product_devices pd WHERE pd.product_id = products.id)").
+		Where("NOT EXISTS (SELECT 1 FROM device_models d WHERE d.created_at < products.updated_at)").
+		Order("id ASC").
+		Scan(&values).Error; err != nil {
+		return nil, err
+	}
+	return values, nil
+}
+
+// LinkCompatibility marks the product as compatible with the given devices,
+// creating any brand or device model that does not exist yet
+func (r *deviceRepository) LinkCompatibility(productID uint, devices []models.DeviceModel) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		for _, device := range devices {
+			brand := models.Brand{Name: device.Brand.Name}
+			if err := tx.Where("name = ?", brand.Name).FirstOrCreate(&brand).Error; err != nil {
+				return err
+			}
+			model := models.DeviceModel{BrandID: brand.ID, Name: device.Name}
+			if err := tx.Where("brand_id = ? AND name = ?", brand.ID, model.Name).FirstOrCreate(&model).Error; err != nil {
+				return err
+			}
+			if err := tx.Exec("INSERT INTO product_devices (product_id, device_model_id) VALUES (?, ?) ON CONFLICT DO NOTHING",
+				productID, model.ID).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// BackfillOnce runs the compatibility backfill unless it has already run, with
+// a repository bound to the transaction that records it
+func (r *deviceRepository) BackfillOnce(backfill func(repo DeviceRepository) error) error {
+	return runOnce(r.db, "compatibility-backfill", func(tx *gorm.DB) error {
+		return backfill(&deviceRepository{db: tx})
+	})
+}
+
+// replaceCompatibleDevices sets the devices the product fits to exactly the
+// given IDs
+func replaceCompatibleDevices(tx *gorm.DB, product *models.Product) error {
+	if len(product.CompatibleDeviceIDs) == 0 {
+		product.CompatibleDevices = nil
+		return tx.Model(product).Association("CompatibleDevices").Clear()
+	}
+
+	var devices []models.DeviceModel
+	if err := tx.Preload("Brand").Where("id IN ?", product.CompatibleDeviceIDs).Find(&devices).Error; err != nil {
+		return err
+	}
+	if len(devices) != len(uniqueIDs(product.CompatibleDeviceIDs)) {
+		return models.ErrDeviceNotFound
+	}
+	if err := tx.Omit("CompatibleDevices.*").Model(product).Association("CompatibleDevices").Replace(devices); err != nil {
+		return err
+	}
+	product.CompatibleDevices = devices
+	return nil
+}
+
+func uniqueIDs(ids []uint) map[uint]struct{} {
+	unique := make(map[uint]struct{}, len(ids))
+	for _, id := range ids {
+		unique[id] = struct{}{}
+	}
+	return unique
+}
@@ -0,0 +1,206 @@
+// internal/repository/discount_repository.go
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"phone-accessories/internal/models"
+)
+
+type DiscountRepository interface {
+	Create(rule *models.DiscountRule) error
+	GetByID(id uint) (*models.DiscountRule, error)
+	GetByCoupon(code string) (*models.DiscountRule, error)
+	Update(rule *models.DiscountRule) error
+	Delete(id uint) error
+	List(filter models.DiscountRuleFilter) ([]models.DiscountRule, error)
+	Running(now time.Time) ([]models.DiscountRule, error)
+	Redeem(reference string, quote *models.Quote, redemptions []models.DiscountRedemption) error
+	GetRedemption(reference string) (*models.Quote, error)
+}
+
+type discountRepository struct {
+	db *gorm.DB
+}
+
+func NewDiscountRepository(db *gorm.DB) DiscountRepository {
+	return &discountRepository{db: db}
+}
+
+func (r *discountRepository) Create(rule *models.DiscountRule) error {
+	rule.UsageCount = 0
+	if err := r.checkCoupon(rule); err != nil {
+		return err
+	}
+	return r.db.Create(rule).Error
+}
+
+func (r *discountRepository) GetByID(id uint) (*models.DiscountRule, error) {
+	var rule models.DiscountRule
+	if err := r.db.First(&rule, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, models.ErrDiscountRuleNotFound
+		}
+		return nil, err
+	}
+	return &rule, nil
+}
+
+// GetByCoupon returns the rule with the coupon code, which is stored upper-cased
+func (r *discountRepository) GetByCoupon(code string) (*models.DiscountRule, error) {
+	var rule models.DiscountRule
+	if err := r.db.Where("coupon_code = ?", code).First(&rule).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, models.ErrDiscountRuleNotFound
+		}
+		return nil, err
+	}
+	return &rule, nil
+}
+
+// Update saves a rule, leaving its usage count to redemptions
+func (r *discountRepository) Update(rule *models.DiscountRule) error {
+	current, err := r.GetByID(rule.ID)
+	if err != nil {
+		return err
+	}
+	rule.UsageCount = current.UsageCount
+	rule.CreatedAt = current.CreatedAt
+	if err := r.checkCoupon(rule); err != nil {
+		return err
+	}
+	return r.db.Model(rule).Select("*").Omit("id", "created_at", "usage_count").Updates(rule).Error
+}
+
+func (r *discountRepository) Delete(id uint) error {
+	result := r.db.Delete(&models.DiscountRule{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return models.ErrDiscountRuleNotFound
+	}
+	return nil
+}
+
+func (r *discountRepository) List(filter models.DiscountRuleFilter) ([]models.DiscountRule, error) {
+	query := r.db.Model(&models.DiscountRule{})
+	if filter.Active != nil {
+		query = query.Where("is_active = ?", *filter.Active)
+	}
+	if filter.Current {
+		query = runningDiscounts(query, time.Now().UTC())
+	}
+
+	var rules []models.DiscountRule
+	if err := query.Order("priority DESC, id ASC").Find(&rules).Error; err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+// Running returns the rules without a coupon code that apply to every cart
+// at the moment, in the order they are applied
+func (r *discountRepository) Running(now time.Time) ([]models.DiscountRule, error) {
+	var rules []models.DiscountRule
+	query := runningDiscounts(r.db.Model(&models.DiscountRule{}), now).Where("coupon_code IS NULL")
+	if err := query.Order("priority DESC, id ASC").Find(&rules).Error; err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+// Redeem keeps the quote an order was charged and records that the order used
+// its discount rules, counting one use of each. It fails with
+// ErrDiscountExhausted when a rule has no uses left and with
+// ErrDiscountAlreadyRedeemed when the reference was already redeemed.
+func (r *discountRepository) Redeem(reference string, quote *models.Quote, redemptions []models.DiscountRedemption) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		// The unique reference makes a concurrent redemption of the same order
+		// wait here until this one commits, and then find it already redeemed
+		stored := models.QuoteRedemption{Reference: reference, Quote: *quote}
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&stored).Error; err != nil {
+			return err
+		}
+		if stored.ID == 0 {
+			return models.ErrDiscountAlreadyRedeemed
+		}
+
+		for i := range redemptions {
+			redemption := &redemptions[i]
+			redemption.ID = 0
+			redemption.Reference = reference
+
+			var existing int64
+			if err := tx.Model(&models.DiscountRedemption{}).
+				Where("rule_id = ? AND reference = ?", redemption.RuleID, reference).
+				Count(&existing).Error; err != nil {
+				return err
+			}
+			if existing > 0 {
+				return models.ErrDiscountAlreadyRedeemed
+			}
+
+			// The limit is checked in the same statement as the count goes up
+			// so that concurrent orders cannot both take the last use
+			result := tx.Model(&models.DiscountRule{}).
+				Where("id = ? AND (usage_limit IS NULL OR usage_count < usage_limit)", redemption.RuleID).
+				UpdateColumn("usage_count", gorm.Expr("usage_count + 1"))
+			if result.Error != nil {
+				return result.Error
+			}
+			if result.RowsAffected == 0 {
+				return models.ErrDiscountExhausted
+			}
+
+			if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(redemption).Error; err != nil {
+				return err
+			}
+			if redemption.ID == 0 {
+				return models.ErrDiscountAlreadyRedeemed
+			}
+		}
+		return nil
+	})
+}
+
+// GetRedemption returns the quote an order reference was charged, or nil when
+// the reference has not been redeemed
+func (r *discountRepository) GetRedemption(reference string) (*models.Quote, error) {
+	var redemption models.QuoteRedemption
+	if err := r.db.Where("reference = ?", reference).Limit(1).Find(&redemption).Error; err != nil {
+		return nil, err
+	}
+	if redemption.ID == 0 {
+		return nil, nil
+	}
+	return &redemption.Quote, nil
+}
+
+// checkCoupon makes sure no other rule has the rule's coupon code
+func (r *discountRepository) checkCoupon(rule *models.DiscountRule) error {
+	if rule.CouponCode == nil {
+		return nil
+	}
+	var count int64
+	if err := r.db.Model(&models.DiscountRule{}).
+		Where("coupon_code = ? AND id <> ?", *rule.CouponCode, rule.ID).
+		Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return models.ErrCouponCodeExists
+	}
+	return nil
+}
+
+// runningDiscounts narrows a discount rule query down to the rules valid at a
+// moment that have uses left
+func runningDiscounts(query *gorm.DB, now time.Time) *gorm.DB {
+	return query.Where("is_active AND starts_at <= ? AND (ends_at IS NULL OR ends_at > ?)", now, now).
+		Where("usage_limit IS NULL OR usage_count < usage_limit")
+}
@@ -0,0 +1,191 @@
+// internal/repository/location_repository.go
+package repository
+
+import (
+	"errors"
+	"fmt"
+
+	"gorm.io/gorm"
+
+	"phone-accessories/internal/models"
+)
+
+type LocationRepository interface {
+	Create(location *models.Location) error
+	GetByID(id uint) (*models.Location, error)
+	Update(location *models.Location) error
+	Delete(id uint) error
+	List() ([]models.Location, error)
+	Transfer(transfer *models.StockTransfer) ([]models.StockMovement, error)
+	EnsureDefault() error
+}
+
+type locationRepository struct {
+	db *gorm.DB
+}
+
+func NewLocationRepository(db *gorm.DB) LocationRepository {
+	return &locationRepository{db: db}
+}
+
+func (r *locationRepository) Create(location *models.Location) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if location.IsDefault {
+			if err := clearDefaultLocation(tx); err != nil {
+				return err
+			}
+		}
+		return tx.Create(location).Error
+	})
+}
+
+func (r *locationRepository) GetByID(id uint) (*models.Location, error) {
+	var location models.Location
+	if err := r.db.First(&location, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, models.ErrLocationNotFound
+		}
+		return nil, err
+	}
+	return &location, nil
+}
+
+func (r *locationRepository) Update(location *models.Location) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var current models.Location
+		if err := tx.First(&current, location.ID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return models.ErrLocationNotFound
+			}
+			return err
+		}
+
+		// There must always be a default location to apply unlocated stock changes to
+		if current.IsDefault && !location.IsDefault {
+			return errors.New("cannot unset the default location; mark another location as default instead")
+		}
+		if location.IsDefault && !current.IsDefault {
+			if err := clearDefaultLocation(tx); err != nil {
+				return err
+			}
+		}
+
+		return tx.Model(location).Select("*").Omit("id", "created_at", "deleted_at").Updates(location).Error
+	})
+}
+
+func (r *locationRepository) Delete(id uint) error {
+	location, err := r.GetByID(id)
+	if err != nil {
+		return err
+	}
+	if location.IsDefault {
+		return errors.New("cannot delete the default location")
+	}
+
+	// Check if there is stock held at this location
+	var count int64
+	if err := r.db.Model(&models.LocationStock{}).
+		Where("location_id = ? AND (stock_level <> 0 OR reserved_level <> 0)", id).Count(&count).Error; err != nil {
+		return err
+	}
+
+	if count > 0 {
+		return errors.New("cannot delete location that still holds stock")
+	}
+
+	return r.db.Delete(&models.Location{}, id).Error
+}
+
+func (r *locationRepository) List() ([]models.Location, error) {
+	var locations []models.Location
+	if err := r.db.Order("id ASC").Find(&locations).Error; err != nil {
+		return nil, err
+	}
+	return locations, nil
+}
+
+func (r *locationRepository) Transfer(transfer *models.StockTransfer) ([]models.StockMovement, error) {
+	var movements []models.StockMovement
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(transfer).Error; err != nil {
+			return err
+		}
+
+		reference := fmt.Sprintf("transfer:%d", transfer.ID)
+		changes := []models.StockChange{
+			{ProductID: transfer.ProductID, LocationID: transfer.FromLocationID, Delta: -transfer.Quantity},
+			{ProductID: transfer.ProductID, LocationID: transfer.ToLocationID, Delta: transfer.Quantity},
+		}
+		for _, change := range changes {
+			change.Reason = models.StockReasonTransfer
+			change.ReferenceID = reference
+			change.Actor = transfer.Actor
+
+			movement, err := applyStockChange(tx, change)
+			if err != nil {
+				return err
+			}
+			movements = append(movements, *movement)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return movements, nil
+}
+
+// EnsureDefault creates the default location if none exists yet and assigns
+// any stock recorded before locations were introduced to it
+func (r *locationRepository) EnsureDefault() error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var location models.Location
+		if err := tx.Where("is_default = ?", true).Limit(1).Find(&location).Error; err != nil {
+			return err
+		}
+		if location.ID == 0 {
+			location = models.Location{
+				Code:      "MAIN",
+				Name:      "Central warehouse",
+				Type:      models.LocationTypeWarehouse,
+				IsDefault: true,
+				IsActive:  true,
+			}
+			if err := tx.Create(&location).Error; err != nil {
+				return err
+			}
+		}
+
+		return tx.Exec(`INSERT INTO location_stocks (product_id, location_id, stock_level, updated_at)
+			SELECT p.id, ?, p.stock_level, NOW() FROM products p
+			WHERE p.stock_level <> 0
+			AND NOT EXISTS (SELECT 1 FROM location_stocks ls WHERE ls.product_id = p.id)`, location.ID).Error
+	})
+}
+
+// resolveLocationID returns the location a stock change applies to, falling
+// back to the default location when none is given
+func resolveLocationID(tx *gorm.DB, id uint) (uint, error) {
+	var location models.Location
+	query := tx.Select("id", "is_active")
+	if id == 0 {
+		query = query.Where("is_default = ?", true)
+	} else {
+		query = query.Where("id = ?", id)
+	}
+	if err := query.Limit(1).Find(&location).Error; err != nil {
+		return 0, err
+	}
+	if location.ID == 0 {
+		return 0, fmt.Errorf("%w: %d", models.ErrLocationNotFound, id)
+	}
+	if !location.IsActive {
+		return 0, fmt.Errorf("%w: %d", models.ErrLocationInactive, location.ID)
+	}
+	return location.ID, nil
+}
+
+func clearDefaultLocation(tx *gorm.DB) error {
+	return tx.Model(&models.Location{}).Where("is_default = ?", true).Update("is_default", false).Error
+}
@@ -0,0 +1,153 @@
+// internal/repository/media_repository.go
+package repository
+
+import (
+	"errors"
+
+	"gorm.io/gorm"
+
+	"phone-accessories/internal/models"
+)
+
+type MediaRepository interface {
+	Create(media *models.ProductMedia) error
+	GetByID(productID, id uint) (*models.ProductMedia, error)
+	List(productID uint) ([]models.ProductMedia, error)
+	Update(media *models.ProductMedia) error
+	Delete(productID, id uint) (*models.ProductMedia, error)
+}
+
+type mediaRepository struct {
+	db *gorm.DB
+}
+
+func NewMediaRepository(db *gorm.DB) MediaRepository {
+	return &mediaRepository{db: db}
+}
+
+// Create adds a media item at the end of the product's gallery. The first
+// media of a product becomes its primary image.
+func (r *mediaRepository) Create(media *models.ProductMedia) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var stats struct {
+			Count        int64
+			NextPosition int
+		}
+		if err := tx.Model(&models.ProductMedia{}).
+			Select("COUNT(*) AS count, COALESCE(MAX(position) + 1, 0) AS next_position").
+			Where("product_id = ?", media.ProductID).Scan(&stats).Error; err != nil {
+			return err
+		}
+		media.Position = stats.NextPosition
+		if stats.Count == 0 {
+			media.IsPrimary = true
+		}
+
+		if media.IsPrimary {
+			if err := clearPrimaryMedia(tx, media.ProductID); err != nil {
+				return err
+			}
+		}
+		if err := tx.Create(media).Error; err != nil {
+			return err
+		}
+		if media.IsPrimary {
+			return syncProductImage(tx, media.ProductID, media.URL)
+		}
+		return nil
+	})
+}
+
+func (r *mediaRepository) GetByID(productID, id uint) (*models.ProductMedia, error) {
+	var media models.ProductMedia
+	if err := r.db.Where("product_id = ?", productID).First(&media, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, models.ErrMediaNotFound
+		}
+		return nil, err
+	}
+	return &media, nil
+}
+
+func (r *mediaRepository) List(productID uint) ([]models.ProductMedia, error) {
+	var media []models.ProductMedia
+	if err := r.db.Where("product_id = ?", productID).Order("position ASC, id ASC").Find(&media).Error; err != nil {
+		return nil, err
+	}
+	return media, nil
+}
+
+func (r *mediaRepository) Update(media *models.ProductMedia) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var current models.ProductMedia
+		if err := tx.Where("product_id = ?", media.ProductID).First(&current, media.ID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return models.ErrMediaNotFound
+			}
+			return err
+		}
+
+		// A product with media always has a primary image
+		if current.IsPrimary && !media.IsPrimary {
+			return errors.New("cannot unset the primary image; mark another image as primary instead")
+		}
+		if media.IsPrimary && !current.IsPrimary {
+			if err := clearPrimaryMedia(tx, media.ProductID); err != nil {
+				return err
+			}
+			if err := syncProductImage(tx, media.ProductID, media.URL); err != nil {
+				return err
+			}
+		}
+
+		return tx.Model(media).Select("variant_id", "alt_text", "position", "is_primary").Updates(media).Error
+	})
+}
+
+// Delete removes a media item and returns it so its file can be removed from
+// storage. When the primary image is deleted the next one takes its place.
+func (r *mediaRepository) Delete(productID, id uint) (*models.ProductMedia, error) {
+	var media models.ProductMedia
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("product_id = ?", productID).First(&media, id).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return models.ErrMediaNotFound
+			}
+			return err
+		}
+		if err := tx.Delete(&media).Error; err != nil {
+			return err
+		}
+		if !media.IsPrimary {
+			return nil
+		}
+
+		var next models.ProductMedia
+		if err := tx.Where("product_id = ?", productID).Order("position ASC, id ASC").
+			Limit(1).Find(&next).Error; err != nil {
+			return err
+		}
+		if next.ID == 0 {
+			return syncProductImage(tx, productID, "")
+		}
+		if err := tx.Model(&next).Update("is_primary", true).Error; err != nil {
+			return err
+		}
+		return syncProductImage(tx, productID, next.URL)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &media, nil
+}
+
+func clearPrimaryMedia(tx *gorm.DB, productID uint) error {
+	return tx.Model(&models.ProductMedia{}).Where("product_id = ? AND is_primary = ?", productID, true).
+		Update("is_primary", false).Error
+}
+
+// syncProductImage keeps the product's imageUrl pointing at its primary image
+// for clients that do not read the gallery
+func syncProductImage(tx *gorm.DB, productID uint, url string) error {
+	return tx.Model(&models.Product{}).Where("id = ?", productID).Update("image_url", url).Error
+}
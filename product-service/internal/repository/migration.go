@@ -0,0 +1,34 @@
+// internal/repository/migration.go
+package repository
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+
+	"phone-accessories/internal/models"
+)
+
+// runOnce applies a one-off data migration and records it under its name in
+// the same transaction. An advisory lock makes instances that start together
+// wait for the first one, which then leaves them nothing to do.
+func runOnce(db *gorm.DB, name string, migrate func(tx *gorm.DB) error) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(hashtext(?))", name).Error; err != nil {
+			return err
+		}
+
+		var applied int64
+		if err := tx.Model(&models.DataMigration{}).Where("name = ?", name).Count(&applied).Error; err != nil {
+			return err
+		}
+		if applied > 0 {
+			return nil
+		}
+
+		if err := migrate(tx); err != nil {
+			return err
+		}
+		return tx.Create(&models.DataMigration{Name: name, AppliedAt: time.Now().UTC()}).Error
+	})
+}
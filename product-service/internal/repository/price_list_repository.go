@@ -0,0 +1,296 @@
+// internal/repository/price_list_repository.go
+package repository
+
+import (
+	"errors"
+	"sort"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"phone-accessories/internal/models"
+)
+
+type PriceListRepository interface {
+	Create(list *models.PriceList) error
+	GetByID(id uint) (*models.PriceList, error)
+	Update(list *models.PriceList) error
+	Delete(id uint) error
+	List(filter models.PriceListFilter) ([]models.PriceList, error)
+	GetDefault(currency string) (*models.PriceList, error)
+	SetPrice(price *models.ProductPrice) error
+	DeletePrice(priceListID, productID uint) error
+	ListPrices(priceListID uint) ([]models.ProductPrice, error)
+	CreateRate(rate *models.ExchangeRate) error
+	DeleteRate(id uint) error
+	ListRates(filter models.ExchangeRateFilter) ([]models.ExchangeRate, error)
+	LatestRates(currency string, asOf time.Time) ([]models.ExchangeRate, error)
+}
+
+type priceListRepository struct {
+	db *gorm.DB
+}
+
+func NewPriceListRepository(db *gorm.DB) PriceListRepository {
+	return &priceListRepository{db: db}
+}
+
+func (r *priceListRepository) Create(list *models.PriceList) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if list.IsDefault {
+			if err := clearDefaultPriceList(tx, list.Currency); err != nil {
+				return err
+			}
+		}
+		return tx.Create(list).Error
+	})
+}
+
+func (r *priceListRepository) GetByID(id uint) (*models.PriceList, error) {
+	var list models.PriceList
+	if err := r.db.First(&list, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, models.ErrPriceListNotFound
+		}
+		return nil, err
+	}
+	return &list, nil
+}
+
+func (r *priceListRepository) Update(list *models.PriceList) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var current models.PriceList
+		if err := tx.First(&current, list.ID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return models.ErrPriceListNotFound
+			}
+			return err
+		}
+
+		// The prices already in the list are amounts of its currency
+		if list.Currency != current.Currency {
+			var count int64
+			if err := tx.Model(&models.ProductPrice{}).Where("price_list_id = ?", list.ID).Count(&count).Error; err != nil {
+				return err
+			}
+			if count > 0 {
+				return errors.New("cannot change the currency of a price list that has prices")
+			}
+		}
+		if list.IsDefault && (!current.IsDefault || list.Currency != current.Currency) {
+			if err := clearDefaultPriceList(tx, list.Currency); err != nil {
+				return err
+			}
+		}
+		return tx.Model(list).Select("*").Omit("id", "created_at").Updates(list).Error
+	})
+}
+
+func (r *priceListRepository) Delete(id uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("price_list_id = ?", id).Delete(&models.ProductPrice{}).Error; err != nil {
+			return err
+		}
+		result := tx.Delete(&models.PriceList{}, id)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return models.ErrPriceListNotFound
+		}
+		return nil
+	})
+}
+
+func (r *priceListRepository) List(filter models.PriceListFilter) ([]models.PriceList, error) {
+	query := r.db.Model(&models.PriceList{})
+	if filter.Currency != "" {
+		query = query.Where("currency = ?", filter.Currency)
+	}
+	if filter.Active != nil {
+		query = query.Where("is_active = ?", *filter.Active)
+	}
+
+	var lists []models.PriceList
+	if err := query.Order("currency ASC, name ASC").Find(&lists).Error; err != nil {
+		return nil, err
+	}
+	return lists, nil
+}
+
+// GetDefault returns the active default price list of a currency, or nil when
+// the currency has none
+func (r *priceListRepository) GetDefault(currency string) (*models.PriceList, error) {
+	var list models.PriceList
+	if err := r.db.Where("currency = ? AND is_default AND is_active", currency).
+		Limit(1).Find(&list).Error; err != nil {
+		return nil, err
+	}
+	if list.ID == 0 {
+		return nil, nil
+	}
+	return &list, nil
+}
+
+// SetPrice sets the price of a product in a price list, replacing any price
+// it had there
+func (r *priceListRepository) SetPrice(price *models.ProductPrice) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "price_list_id"}, {Name: "product_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"price_amount", "price_currency", "updated_at"}),
+	}).Create(price).Error
+}
+
+func (r *priceListRepository) DeletePrice(priceListID, productID uint) error {
+	result := r.db.Where("price_list_id = ? AND product_id = ?", priceListID, productID).Delete(&models.ProductPrice{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return models.ErrProductPriceNotFound
+	}
+	return nil
+}
+
+func (r *priceListRepository) ListPrices(priceListID uint) ([]models.ProductPrice, error) {
+	var prices []models.ProductPrice
+	if err := r.db.Where("price_list_id = ?", priceListID).Order("product_id ASC").Find(&prices).Error; err != nil {
+		return nil, err
+	}
+	return prices, nil
+}
+
+func (r *priceListRepository) CreateRate(rate *models.ExchangeRate) error {
+	var count int64
+	if err := r.db.Model(&models.ExchangeRate{}).
+		Where("base_currency = ? AND quote_currency = ? AND effective_from = ?",
+			rate.BaseCurrency, rate.QuoteCurrency, rate.EffectiveFrom).
+		Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return models.ErrExchangeRateExists
+	}
+	return r.db.Create(rate).Error
+}
+
+func (r *priceListRepository) DeleteRate(id uint) error {
+	result := r.db.Delete(&models.ExchangeRate{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return models.ErrExchangeRateNotFound
+	}
+	return nil
+}
+
+func (r *priceListRepository) ListRates(filter models.ExchangeRateFilter) ([]models.ExchangeRate, error) {
+	query := r.db.Model(&models.ExchangeRate{})
+	if filter.Currency != "" {
+		query = query.Where("base_currency = ? OR quote_currency = ?", filter.Currency, filter.Currency)
+	}
+
+	var rates []models.ExchangeRate
+	if err := query.Order("base_currency ASC, quote_currency ASC, effective_from DESC").Find(&rates).Error; err != nil {
+		return nil, err
+	}
+	return rates, nil
+}
+
+// LatestRates returns, for every currency pair involving the currency in
+// either direction, the rate in effect on the given date
+func (r *priceListRepository) LatestRates(currency string, asOf time.Time) ([]models.ExchangeRate, error) {
+	var rates []models.ExchangeRate
+	if err := r.db.Raw(`SELECT DISTINCT ON (base_currency, quote_currency) * FROM exchange_rates
+		WHERE (base_currency = ? OR quote_currency = ?) AND effective_from <= ?
+		ORDER BY base_currency, quote_currency, effective_from DESC`,
+		currency, currency, asOf).Scan(&rates).Error; err != nil {
+		return nil, err
+	}
+	return rates, nil
+}
+
+func clearDefaultPriceList(tx *gorm.DB, currency string) error {
+	return tx.Model(&models.PriceList{}).Where("currency = ? AND is_default", currency).
+		Update("is_default", false).Error
+}
+
+// resolvedPriceSQL returns an SQL expression for the price of a product row,
+// under the given alias, in the pricing's currency: its price in the price
+// list, or else its base price converted with the pricing's factors. It is
+// NULL for products whose base price has no exchange rate into the currency.
+func resolvedPriceSQL(pricing *models.PriceContext, alias string) (string, []interface{}) {
+	expr, args := convertedAmountSQL(pricing, alias+".price_amount", alias+".price_currency")
+	if pricing.PriceList == nil {
+		return expr, args
+	}
+	return `COALESCE((SELECT pp.price_amount FROM product_prices pp
+		WHERE pp.price_list_id = ? AND pp.product_id = ` + alias + `.id), ` + expr + ")",
+		append([]interface{}{pricing.PriceList.ID}, args...)
+}
+
+// convertedAmountSQL returns an SQL expression converting an amount column in
+// a currency column into the pricing's currency, the way PriceContext.Convert
+// does. It is NULL when there is no exchange rate for the currency.
+func convertedAmountSQL(pricing *models.PriceContext, amount, currency string) (string, []interface{}) {
+	currencies := make([]string, 0, len(pricing.Factors))
+	for code := range pricing.Factors {
+		currencies = append(currencies, code)
+	}
+	sort.Strings(currencies)
+
+	var expr strings.Builder
+	args := []interface{}{pricing.Currency}
+	expr.WriteString("CASE " + currency + " WHEN ? THEN " + amount)
+	for _, code := range currencies {
+		expr.WriteString(" WHEN ? THEN ROUND(" + amount + " * CAST(? AS numeric))")
+		args = append(args, code, pricing.Factors[code])
+	}
+	expr.WriteString(" END")
+	return expr.String(), args
+}
+
+// attachPrices resolves the price of each product and of its variants. Sale
+// prices in the pricing's currency are left to attachSales.
+func attachPrices(db *gorm.DB, products []models.Product, pricing *models.PriceContext) error {
+	var targets []*models.Product
+	for i := range products {
+		targets = append(targets, &products[i])
+		for j := range products[i].Variants {
+			targets = append(targets, &products[i].Variants[j])
+		}
+	}
+	if len(targets) == 0 {
+		return nil
+	}
+
+	listPrices := make(map[uint]*models.Money)
+	if pricing.PriceList != nil {
+		ids := make([]uint, len(targets))
+		for i, product := range targets {
+			ids[i] = product.ID
+		}
+		var prices []models.ProductPrice
+		if err := db.Where("price_list_id = ? AND product_id IN ?", pricing.PriceList.ID, ids).
+			Find(&prices).Error; err != nil {
+			return err
+		}
+		for i := range prices {
+			listPrices[prices[i].ProductID] = &prices[i].Price
+		}
+	}
+
+	for _, product := range targets {
+		product.ResolvedPrice = pricing.Resolve(product, listPrices[product.ID])
+		for i := range product.PriceTiers {
+			tier := &product.PriceTiers[i]
+			if unitPrice, ok := pricing.Convert(tier.UnitPrice); ok {
+				tier.ResolvedUnitPrice = &unitPrice
+			}
+		}
+	}
+	return nil
+}
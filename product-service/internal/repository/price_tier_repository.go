@@ -0,0 +1,46 @@
+// internal/repository/price_tier_repository.go
+package repository
+
+import (
+	"gorm.io/gorm"
+
+	"phone-accessories/internal/models"
+)
+
+type PriceTierRepository interface {
+	List(productID uint) ([]models.PriceTier, error)
+	Replace(productID uint, tiers []models.PriceTier) error
+}
+
+type priceTierRepository struct {
+	db *gorm.DB
+}
+
+func NewPriceTierRepository(db *gorm.DB) PriceTierRepository {
+	return &priceTierRepository{db: db}
+}
+
+func (r *priceTierRepository) List(productID uint) ([]models.PriceTier, error) {
+	var tiers []models.PriceTier
+	if err := r.db.Where("product_id = ?", productID).Order("min_quantity ASC").Find(&tiers).Error; err != nil {
+		return nil, err
+	}
+	return tiers, nil
+}
+
+// Replace swaps the quantity tiers of a product for the given ones
+func (r *priceTierRepository) Replace(productID uint, tiers []models.PriceTier) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("product_id = ?", productID).Delete(&models.PriceTier{}).Error; err != nil {
+			return err
+		}
+		for i := range tiers {
+			tiers[i].ID = 0
+			tiers[i].ProductID = productID
+		}
+		if len(tiers) == 0 {
+			return nil
+		}
+		return tx.Create(&tiers).Error
+	})
+}
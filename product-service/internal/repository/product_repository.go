@@ -3,13 +3,17 @@ package repository
 
 import (
 	"errors"
+	"fmt"
 	"math"
+	"strconv"
 	"strings"
+	"time"
 
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
 
 	"phone-accessories/internal/models"
+	"phone-accessories/internal/slug"
 )
 
 type ProductRepository interface {
@@ -19,7 +23,15 @@ type ProductRepository interface {
 	Delete(id uint) error
 	List(filter models.ProductFilter) (*models.PaginatedResponse, error)
 	Search(query string, page, pageSize int) (*models.PaginatedResponse, error)
-	UpdateStock(id uint, quantity int) error
+	UpdateStock(change models.StockChange) (*models.StockMovement, error)
+	ListLowStock() ([]models.Product, error)
+	AdjustStock(lines []models.StockAdjustmentLine, referenceID, actor string) ([]models.StockAdjustmentResult, error)
+	InBundle(id uint) (bool, error)
+	AttachPrices(products []models.Product, pricing *models.PriceContext) error
+	VariantOptionsTaken(parentID uint, options models.JSON, excludeID uint) (bool, error)
+	GetBySlug(slug string) (*models.Product, error)
+	EnsureSlugs() error
+	MigratePrices() error
 }
 
 type productRepository struct {
@@ -31,46 +43,296 @@ func NewProductRepository(db *gorm.DB) ProductRepository {
 }
 
 func (r *productRepository) Create(product *models.Product) error {
-	return r.db.Create(product).Error
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		// Insert with an empty stock level so the opening stock goes through the ledger
+		initialStock := product.StockLevel
+		product.StockLevel = 0
+		var err error
+		product.Slug, err = assignSlug(tx, models.SlugEntityProduct, "products", 0,
+			slug.Derive(product.Slug, product.Name, "", "", "product"), "")
+		if err != nil {
+			return err
+		}
+		if err := tx.Omit("Brand", "Locations", "Variants", "Components", "Media", "CompatibleDevices", "PriceTiers").Create(product).Error; err != nil {
+			return err
+		}
+		if product.IsBundle {
+			if err := replaceBundleComponents(tx, product); err != nil {
+				return err
+			}
+		}
+		if product.CompatibleDeviceIDs != nil {
+			if err := replaceCompatibleDevices(tx, product); err != nil {
+				return err
+			}
+		}
+		if initialStock == 0 {
+			return nil
+		}
+
+		movement, err := applyStockChange(tx, models.StockChange{
+			ProductID: product.ID,
+			Delta:     initialStock,
+			Reason:    models.StockReasonRestock,
+		})
+		if err != nil {
+			return err
+		}
+		product.StockLevel = movement.ResultingLevel
+		return nil
+	})
 }
 
 func (r *productRepository) GetByID(id uint) (*models.Product, error) {
 	var product models.Product
-	if err := r.db.Preload("Category").First(&product, id).Error; err != nil {
+	if err := r.db.Preload("Category").Preload("Brand").Preload("Locations.Location").
+		Preload("PriceTiers", func(db *gorm.DB) *gorm.DB {
+			return db.Order("min_quantity ASC")
+		}).
+		Preload("Options", func(db *gorm.DB) *gorm.DB {
+			return db.Order("position ASC, id ASC")
+		}).
+		Preload("Variants", func(db *gorm.DB) *gorm.DB {
+			return db.Order("id ASC")
+		}).
+		Preload("Variants.Locations.Location").
+		Preload("Media", func(db *gorm.DB) *gorm.DB {
+			return db.Order("position ASC, id ASC")
+		}).
+		Preload("CompatibleDevices", func(db *gorm.DB) *gorm.DB {
+			return db.Order("brand_id ASC, name ASC")
+		}).
+		Preload("CompatibleDevices.Brand").
+		First(&product, id).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("product not found")
+			return nil, models.ErrProductNotFound
 		}
 		return nil, err
 	}
-	return &product, nil
+	if err := r.attachIncoming(product.Variants); err != nil {
+		return nil, err
+	}
+	products := []models.Product{product}
+	if err := r.attachIncoming(products); err != nil {
+		return nil, err
+	}
+	if err := attachBundleAvailability(r.db, products); err != nil {
+		return nil, err
+	}
+	if err := attachSales(r.db, products, time.Now().UTC(), nil); err != nil {
+		return nil, err
+	}
+	return &products[0], nil
 }
 
 func (r *productRepository) Update(product *models.Product) error {
-	return r.db.Save(product).Error
+	// Only apply the update if nobody changed the product since the caller read
+	// it. Requests without a version, from clients written before versions
+	// existed, are not checked and update whatever version is stored.
+	sent := product.Version
+	version := sent
+	product.Version = version + 1
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		// A renamed product gets a new slug and keeps the old one as a redirect
+		var current models.Product
+		query := tx.Select("slug", "name", "version").Where("id = ?", product.ID)
+		if sent == 0 {
+			// Lock the row so that the version read is the one updated
+			query = query.Clauses(clause.Locking{Strength: "UPDATE"})
+		}
+		if err := query.Limit(1).Find(&current).Error; err != nil {
+			return err
+		}
+		if sent == 0 {
+			version = current.Version
+			product.Version = version + 1
+		}
+		var err error
+		product.Slug, err = assignSlug(tx, models.SlugEntityProduct, "products", product.ID,
+			slug.Derive(product.Slug, product.Name, current.Slug, current.Name, "product"), current.Slug)
+		if err != nil {
+			return err
+		}
+
+		// Stock levels only change through UpdateStock and reservations, so every
+		// change is recorded in the stock ledger. A variant stays with its parent.
+		result := tx.Model(product).Where("version = ?", version).
+			Select("*").Omit("id", "stock_level", "reserved_level", "parent_id", "created_at", "deleted_at",
+			clause.Associations).
+			Updates(product)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			if _, err := r.GetByID(product.ID); err != nil {
+				return err
+			}
+			return models.ErrVersionConflict
+		}
+
+		// Options, bundle components and compatible devices are only replaced
+		// when the request carries them
+		if product.CompatibleDeviceIDs != nil {
+			if err := replaceCompatibleDevices(tx, product); err != nil {
+				return err
+			}
+		}
+		if product.Components != nil {
+			if err := replaceBundleComponents(tx, product); err != nil {
+				return err
+			}
+		}
+		if product.Options == nil {
+			return nil
+		}
+		if err := tx.Where("product_id = ?", product.ID).Delete(&models.ProductOption{}).Error; err != nil {
+			return err
+		}
+		for i := range product.Options {
+			product.Options[i].ID = 0
+			product.Options[i].ProductID = product.ID
+		}
+		if len(product.Options) == 0 {
+			return nil
+		}
+		return tx.Create(&product.Options).Error
+	})
+	if err != nil {
+		product.Version = sent
+		return err
+	}
+	return nil
 }
 
 func (r *productRepository) Delete(id uint) error {
-	return r.db.Delete(&models.Product{}, id).Error
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		// Bundles would no longer be complete without the product or its variants
+		var count int64
+		if err := tx.Model(&models.BundleComponent{}).
+			Where("component_id = ? OR component_id IN (SELECT id FROM products WHERE parent_id = ?)", id, id).
+			Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			return models.ErrProductInBundle
+		}
+		if err := tx.Where("bundle_id = ?", id).Delete(&models.BundleComponent{}).Error; err != nil {
+			return err
+		}
+		// Relations of the product or its variants, either way, have nothing left to show
+		if err := tx.Where(`product_id = ? OR target_id = ? OR product_id IN (SELECT id FROM products WHERE parent_id = ?)
+			OR target_id IN (SELECT id FROM products WHERE parent_id = ?)`, id, id, id, id).
+			Delete(&models.ProductRelation{}).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Where("product_id = ? OR product_id IN (SELECT id FROM products WHERE parent_id = ?)", id, id).
+			Delete(&models.ProductPrice{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("product_id = ? OR product_id IN (SELECT id FROM products WHERE parent_id = ?)", id, id).
+			Delete(&models.PriceTier{}).Error; err != nil {
+			return err
+		}
+
+		// Variants cannot be sold without their parent
+		if err := tx.Where("parent_id = ?", id).Delete(&models.Product{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.Product{}, id).Error
+	})
 }
 
 func (r *productRepository) List(filter models.ProductFilter) (*models.PaginatedResponse, error) {
 	var products []models.Product
 	var totalItems int64
 
-	query := r.db.Model(&models.Product{})
+	// Variants are listed under their parent
+	query := r.db.Model(&models.Product{}).Where("parent_id IS NULL")
+	now := time.Now().UTC()
 
 	// Apply filters
 	if filter.CategoryID != nil {
-		query = query.Where("category_id = ?", *filter.CategoryID)
+		if filter.IncludeDescendants {
+			query = query.Where("category_id IN (?)", r.db.Raw(categorySubtreeSQL, *filter.CategoryID))
+		} else {
+			query = query.Where("category_id = ?", *filter.CategoryID)
+		}
 	}
-	if filter.MinPrice != nil {
-		query = query.Where("price >= ?", *filter.MinPrice)
+	if filter.BrandID != nil {
+		query = query.Where("brand_id = ?", *filter.BrandID)
 	}
-	if filter.MaxPrice != nil {
-		query = query.Where("price <= ?", *filter.MaxPrice)
+	// Prices are compared in minor units: the resolved price when prices were
+	// asked for in a price list or currency, otherwise base prices in the same currency
+	var priceExpr string
+	var priceArgs []interface{}
+	if filter.Pricing != nil {
+		priceExpr, priceArgs = resolvedPriceSQL(filter.Pricing, "products")
+	}
+	if bound := filter.PriceRange.Min; bound != nil {
+		if filter.Pricing != nil {
+			query = query.Where("("+priceExpr+") >= ?", append(priceArgs, bound.Amount)...)
+		} else {
+			query = query.Where("price_currency = ? AND price_amount >= ?", bound.Currency, bound.Amount)
+		}
+	}
+	if bound := filter.PriceRange.Max; bound != nil {
+		if filter.Pricing != nil {
+			query = query.Where("("+priceExpr+") <= ?", append(priceArgs, bound.Amount)...)
+		} else {
+			query = query.Where("price_currency = ? AND price_amount <= ?", bound.Currency, bound.Amount)
+		}
 	}
 	if filter.InStock != nil && *filter.InStock {
-		query = query.Where("stock_level > 0")
+		// A parent product is in stock when any of its variants is
+		if filter.LocationID != nil {
+			// Only what is held at the requested location and not reserved there
+			locationStock := "EXISTS (SELECT 1 FROM location_stocks ls WHERE ls.product_id = %s.id AND ls.location_id = ? AND ls.stock_level - ls.reserved_level >= %s)"
+			query = query.Where(fmt.Sprintf(`(%s OR EXISTS (SELECT 1 FROM products v WHERE v.parent_id = products.id AND v.deleted_at IS NULL AND %s)
+				OR (products.is_bundle AND NOT EXISTS (SELECT 1 FROM bundle_components bc JOIN products c ON c.id = bc.component_id
+					WHERE bc.bundle_id = products.id AND NOT %s)))`,
+				fmt.Sprintf(locationStock, "products", "1"), fmt.Sprintf(locationStock, "v", "1"), fmt.Sprintf(locationStock, "c", "bc.quantity")),
+				*filter.LocationID, *filter.LocationID, *filter.LocationID)
+		} else {
+			// Products that sell ahead of stock count as in stock while they
+			// have backorder allowance left, and bundles while every component
+			// can supply its share of one bundle
+			sellable := `(%[1]s.stock_level - %[1]s.reserved_level >= %[2]s OR (%[1]s.availability_policy IN ? AND
+				(%[1]s.backorder_limit IS NULL OR %[1]s.stock_level - %[1]s.reserved_level + %[1]s.backorder_limit >= %[2]s)))`
+			query = query.Where(fmt.Sprintf(`(%s OR EXISTS (SELECT 1 FROM products v WHERE v.parent_id = products.id AND v.deleted_at IS NULL AND %s)
+				OR (products.is_bundle AND NOT EXISTS (SELECT 1 FROM bundle_components bc JOIN products c ON c.id = bc.component_id
+					WHERE bc.bundle_id = products.id AND NOT %s)))`,
+				fmt.Sprintf(sellable, "products", "1"), fmt.Sprintf(sellable, "v", "1"), fmt.Sprintf(sellable, "c", "bc.quantity")),
+				sellAheadPolicies, sellAheadPolicies, sellAheadPolicies)
+		}
+	}
+	if filter.OnSale != nil {
+		// A parent product is on sale when it or any of its variants is
+		onSale, onSaleArgs := onSaleSQL("products", now, filter.Pricing)
+		variantOnSale, variantArgs := onSaleSQL("v", now, filter.Pricing)
+		condition := fmt.Sprintf("(%s OR EXISTS (SELECT 1 FROM products v WHERE v.parent_id = products.id AND v.deleted_at IS NULL AND %s))",
+			onSale, variantOnSale)
+		if !*filter.OnSale {
+			condition = "NOT " + condition
+		}
+		query = query.Where(condition, append(onSaleArgs, variantArgs...)...)
+	}
+	if filter.DeviceID != nil {
+		// A product fits a device when it, or any of its variants, is linked to it
+		query = query.Where(`EXISTS (SELECT 1 FROM product_devices pd WHERE pd.device_model_id = ? AND (pd.product_id = products.id
+			OR pd.product_id IN (SELECT v.id FROM products v WHERE v.parent_id = products.id AND v.deleted_at IS NULL)))`,
+			*filter.DeviceID)
+	}
+	if len(filter.Options) > 0 {
+		options := make(models.JSON, len(filter.Options))
+		for name, value := range filter.Options {
+			options[name] = value
+		}
+		query = query.Where("EXISTS (SELECT 1 FROM products v WHERE v.parent_id = products.id AND v.deleted_at IS NULL AND v.variant_options @> ?)", options)
+	}
+	for _, attribute := range filter.Attributes {
+		query = applyAttributeFilter(query, attribute)
 	}
 	if filter.SearchQuery != "" {
 		search := "%" + filter.SearchQuery + "%"
@@ -88,7 +350,20 @@ func (r *productRepository) List(filter models.ProductFilter) (*models.Paginated
 		if strings.ToUpper(filter.SortDirection) == "DESC" {
 			direction = "DESC"
 		}
-		query = query.Order(clause.OrderByColumn{Column: clause.Column{Name: filter.SortBy}, Desc: direction == "DESC"})
+		column := filter.SortBy
+		switch {
+		case column == "price" && filter.Pricing != nil:
+			// Products that cannot be priced in the currency come last
+			query = query.Clauses(clause.OrderBy{Expression: clause.Expr{
+				SQL:                "(" + priceExpr + ") " + direction + " NULLS LAST, products.id ASC",
+				Vars:               priceArgs,
+				WithoutParentheses: true,
+			}})
+		case column == "price":
+			query = query.Order(clause.OrderByColumn{Column: clause.Column{Name: "price_amount"}, Desc: direction == "DESC"})
+		default:
+			query = query.Order(clause.OrderByColumn{Column: clause.Column{Name: column}, Desc: direction == "DESC"})
+		}
 	} else {
 		query = query.Order("id ASC")
 	}
@@ -102,7 +377,28 @@ func (r *productRepository) List(filter models.ProductFilter) (*models.Paginated
 	}
 	offset := (filter.Page - 1) * filter.PageSize
 
-	if err := query.Preload("Category").Offset(offset).Limit(filter.PageSize).Find(&products).Error; err != nil {
+	if err := query.Preload("Category").Preload("Brand").
+		Preload("Options", func(db *gorm.DB) *gorm.DB {
+			return db.Order("position ASC, id ASC")
+		}).
+		Preload("Variants", func(db *gorm.DB) *gorm.DB {
+			return db.Order("id ASC")
+		}).
+		Offset(offset).Limit(filter.PageSize).Find(&products).Error; err != nil {
+		return nil, err
+	}
+	if err := r.attachIncoming(products); err != nil {
+		return nil, err
+	}
+	if err := attachBundleAvailability(r.db, products); err != nil {
+		return nil, err
+	}
+	if filter.Pricing != nil {
+		if err := attachPrices(r.db, products, filter.Pricing); err != nil {
+			return nil, err
+		}
+	}
+	if err := attachSales(r.db, products, now, filter.Pricing); err != nil {
 		return nil, err
 	}
 
@@ -123,7 +419,8 @@ func (r *productRepository) Search(query string, page, pageSize int) (*models.Pa
 	searchQuery := "%" + query + "%"
 
 	db := r.db.Model(&models.Product{}).
-		Where("name ILIKE ? OR description ILIKE ? OR sku ILIKE ?", searchQuery, searchQuery, searchQuery)
+		Where("name ILIKE ? OR description ILIKE ? OR sku ILIKE ? OR brand_id IN (SELECT id FROM brands WHERE name ILIKE ?)",
+			searchQuery, searchQuery, searchQuery, searchQuery)
 
 	if err := db.Count(&totalItems).Error; err != nil {
 		return nil, err
@@ -138,7 +435,16 @@ func (r *productRepository) Search(query string, page, pageSize int) (*models.Pa
 
 	offset := (page - 1) * pageSize
 
-	if err := db.Preload("Category").Offset(offset).Limit(pageSize).Find(&products).Error; err != nil {
+	if err := db.Preload("Category").Preload("Brand").Offset(offset).Limit(pageSize).Find(&products).Error; err != nil {
+		return nil, err
+	}
+	if err := r.attachIncoming(products); err != nil {
+		return nil, err
+	}
+	if err := attachBundleAvailability(r.db, products); err != nil {
+		return nil, err
+	}
+	if err := attachSales(r.db, products, time.Now().UTC(), nil); err != nil {
 		return nil, err
 	}
 
@@ -152,7 +458,267 @@ func (r *productRepository) Search(query string, page, pageSize int) (*models.Pa
 	}, nil
 }
 
-func (r *productRepository) UpdateStock(id uint, quantity int) error {
-	return r.db.Model(&models.Product{}).Where("id = ?", id).
-		Update("stock_level", gorm.Expr("stock_level + ?", quantity)).Error
+func (r *productRepository) UpdateStock(change models.StockChange) (*models.StockMovement, error) {
+	var movement *models.StockMovement
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		movement, err = applyStockChange(tx, change)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return movement, nil
+}
+
+func (r *productRepository) ListLowStock() ([]models.Product, error) {
+	var products []models.Product
+	if err := r.db.Where("reorder_point > 0 AND stock_level < reorder_point").
+		Order("reorder_point - stock_level DESC, id ASC").Find(&products).Error; err != nil {
+		return nil, err
+	}
+	if err := r.attachIncoming(products); err != nil {
+		return nil, err
+	}
+	return products, nil
+}
+
+func (r *productRepository) AdjustStock(lines []models.StockAdjustmentLine, referenceID, actor string) ([]models.StockAdjustmentResult, error) {
+	var results []models.StockAdjustmentResult
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var lineErrors []models.StockAdjustmentLineError
+		for i, line := range lines {
+			// Keep going after a rejected line so the caller gets every problem at once
+			lineError := models.StockAdjustmentLineError{Line: i, ProductID: line.ProductID, SKU: line.SKU}
+			if err := line.Validate(); err != nil {
+				lineError.Error = err.Error()
+				lineErrors = append(lineErrors, lineError)
+				continue
+			}
+
+			productID := line.ProductID
+			if line.SKU != "" {
+				var product models.Product
+				if err := tx.Select("id").Where("sku = ?", line.SKU).Limit(1).Find(&product).Error; err != nil {
+					return err
+				}
+				switch {
+				case product.ID == 0:
+					lineError.Error = fmt.Sprintf("unknown SKU %q", line.SKU)
+				case productID != 0 && productID != product.ID:
+					lineError.Error = fmt.Sprintf("SKU %q belongs to product %d, not product %d", line.SKU, product.ID, productID)
+				}
+				if lineError.Error != "" {
+					lineErrors = append(lineErrors, lineError)
+					continue
+				}
+				productID = product.ID
+			}
+
+			movement, err := applyStockChange(tx, models.StockChange{
+				ProductID:   productID,
+				LocationID:  line.LocationID,
+				Delta:       line.Delta,
+				Reason:      line.Reason,
+				ReferenceID: referenceID,
+				Actor:       actor,
+			})
+			if err != nil {
+				if errors.Is(err, models.ErrInsufficientStock) ||
+					errors.Is(err, models.ErrProductNotFound) ||
+					errors.Is(err, models.ErrProductIsBundle) ||
+					errors.Is(err, models.ErrLocationNotFound) ||
+					errors.Is(err, models.ErrLocationInactive) {
+					lineError.Error = err.Error()
+					lineErrors = append(lineErrors, lineError)
+					continue
+				}
+				return err
+			}
+
+			// A bundle line moves the stock of each of its components
+			movements := []models.StockMovement{*movement}
+			if len(movement.Components) > 0 {
+				movements = movement.Components
+			}
+			for _, movement := range movements {
+				result := models.StockAdjustmentResult{
+					Line:           i,
+					ProductID:      movement.ProductID,
+					LocationID:     *movement.LocationID,
+					Delta:          movement.Delta,
+					ResultingLevel: movement.ResultingLevel,
+					LocationLevel:  movement.LocationLevel,
+					MovementID:     movement.ID,
+				}
+				if movement.ProductID == productID {
+					result.SKU = line.SKU
+				}
+				results = append(results, result)
+			}
+		}
+
+		if len(lineErrors) > 0 {
+			return &models.StockAdjustmentError{Lines: lineErrors}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// GetBySlug returns the product with the slug, or the product that used to
+// have it before being renamed
+func (r *productRepository) GetBySlug(value string) (*models.Product, error) {
+	id, err := findBySlug(r.db, models.SlugEntityProduct, "products", value)
+	if err != nil {
+		return nil, err
+	}
+	if id == 0 {
+		return nil, models.ErrProductNotFound
+	}
+	return r.GetByID(id)
+}
+
+// EnsureSlugs gives products created before slugs existed a slug
+func (r *productRepository) EnsureSlugs() error {
+	return backfillSlugs(r.db, models.SlugEntityProduct, "products", "product")
+}
+
+// InBundle reports whether the product is a component of any bundle
+func (r *productRepository) InBundle(id uint) (bool, error) {
+	var count int64
+	if err := r.db.Model(&models.BundleComponent{}).Where("component_id = ?", id).Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// AttachPrices resolves the price of each product, and of its variants, in
+// the pricing's price list or currency, along with its sale price there
+func (r *productRepository) AttachPrices(products []models.Product, pricing *models.PriceContext) error {
+	if err := attachPrices(r.db, products, pricing); err != nil {
+		return err
+	}
+	return attachSales(r.db, products, time.Now().UTC(), pricing)
+}
+
+// MigratePrices moves prices stored as decimal numbers, from before prices had
+// a currency, into minor units of the default currency and drops the old column
+func (r *productRepository) MigratePrices() error {
+	migrator := r.db.Migrator()
+	if migrator.HasColumn(&models.Product{}, "price") {
+		err := r.db.Transaction(func(tx *gorm.DB) error {
+			// Go through numeric so that 19.99 becomes 1999 and not 1998
+			if err := tx.Exec(`UPDATE products SET price_amount = ROUND(CAST(price AS numeric) * ?), price_currency = ?`,
+				int64(math.Pow10(models.CurrencyExponent(models.DefaultCurrency))), models.DefaultCurrency).Error; err != nil {
+				return err
+			}
+			return tx.Migrator().DropColumn(&models.Product{}, "price")
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return r.db.Exec("CREATE INDEX IF NOT EXISTS idx_products_price ON products (price_currency, price_amount)").Error
+}
+
+// VariantOptionsTaken reports whether another variant of the parent already
+// has exactly these option values
+func (r *productRepository) VariantOptionsTaken(parentID uint, options models.JSON, excludeID uint) (bool, error) {
+	var count int64
+	if err := r.db.Model(&models.Product{}).
+		Where("parent_id = ? AND id <> ? AND variant_options = ?", parentID, excludeID, options).
+		Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// attributeComparisons maps range operators to SQL; nothing from the request
+// is ever spliced into the query
+var attributeComparisons = map[models.AttributeOperator]string{
+	models.AttributeOperatorGt:  ">",
+	models.AttributeOperatorGte: ">=",
+	models.AttributeOperatorLt:  "<",
+	models.AttributeOperatorLte: "<=",
+}
+
+// applyAttributeFilter narrows a product query down by one attribute. Equality
+// uses jsonb containment, which the GIN index on attributes serves, and also
+// matches lists that contain the value; ranges only match numeric values.
+func applyAttributeFilter(query *gorm.DB, filter models.AttributeFilter) *gorm.DB {
+	if comparison, ok := attributeComparisons[filter.Operator]; ok {
+		return query.Where(`CASE WHEN jsonb_typeof(products.attributes -> CAST(? AS text)) = 'number'
+			THEN (products.attributes ->> CAST(? AS text))::numeric END `+comparison+` ?`,
+			filter.Key, filter.Key, filter.Number)
+	}
+
+	var conditions []string
+	var args []interface{}
+	for _, value := range filter.Values {
+		candidates := []interface{}{value, []string{value}}
+		// Query strings carry no types, so also try the value as a number or a bool
+		if number, err := strconv.ParseFloat(value, 64); err == nil {
+			candidates = append(candidates, number)
+		}
+		if value == "true" || value == "false" {
+			candidates = append(candidates, value == "true")
+		}
+		for _, candidate := range candidates {
+			conditions = append(conditions, "products.attributes @> ?")
+			args = append(args, models.JSON{filter.Key: candidate})
+		}
+	}
+	if len(conditions) == 0 {
+		return query
+	}
+	return query.Where("("+strings.Join(conditions, " OR ")+")", args...)
+}
+
+// attachIncoming fills in the quantity of each product that is still expected
+// on purchase orders sent to suppliers, and when the next delivery is due
+func (r *productRepository) attachIncoming(products []models.Product) error {
+	if len(products) == 0 {
+		return nil
+	}
+
+	ids := make([]uint, len(products))
+	for i, product := range products {
+		ids[i] = product.ID
+	}
+
+	var rows []struct {
+		ProductID  uint
+		Incoming   int
+		ExpectedAt *time.Time
+	}
+	if err := r.db.Table("purchase_order_lines l").
+		Select("l.product_id, SUM(l.quantity_ordered - l.quantity_received) AS incoming, MIN(po.expected_at) AS expected_at").
+		Joins("JOIN purchase_orders po ON po.id = l.purchase_order_id").
+		Where("po.status IN ?", []models.PurchaseOrderStatus{
+			models.PurchaseOrderStatusSent, models.PurchaseOrderStatusPartiallyReceived,
+		}).
+		Where("l.product_id IN ?", ids).
+		Group("l.product_id").Scan(&rows).Error; err != nil {
+		return err
+	}
+
+	incoming := make(map[uint]int, len(rows))
+	expected := make(map[uint]*time.Time, len(rows))
+	for _, row := range rows {
+		incoming[row.ProductID] = row.Incoming
+		expected[row.ProductID] = row.ExpectedAt
+	}
+	for i := range products {
+		products[i].IncomingQuantity = incoming[products[i].ID]
+		// Products that ran out become available again with the next delivery
+		switch products[i].AvailabilityStatus {
+		case models.AvailabilityStatusBackorder, models.AvailabilityStatusOutOfStock:
+			products[i].ExpectedAvailableAt = expected[products[i].ID]
+		}
+	}
+	return nil
 }
@@ -0,0 +1,239 @@
+// internal/repository/promotion_repository.go
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+
+	"phone-accessories/internal/models"
+)
+
+type PromotionRepository interface {
+	Create(promotion *models.Promotion) error
+	GetByID(id uint) (*models.Promotion, error)
+	Update(promotion *models.Promotion) error
+	Delete(id uint) error
+	List(filter models.PromotionFilter) ([]models.Promotion, error)
+}
+
+type promotionRepository struct {
+	db *gorm.DB
+}
+
+func NewPromotionRepository(db *gorm.DB) PromotionRepository {
+	return &promotionRepository{db: db}
+}
+
+func (r *promotionRepository) Create(promotion *models.Promotion) error {
+	return r.db.Create(promotion).Error
+}
+
+func (r *promotionRepository) GetByID(id uint) (*models.Promotion, error) {
+	var promotion models.Promotion
+	if err := r.db.First(&promotion, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, models.ErrPromotionNotFound
+		}
+		return nil, err
+	}
+	return &promotion, nil
+}
+
+func (r *promotionRepository) Update(promotion *models.Promotion) error {
+	if _, err := r.GetByID(promotion.ID); err != nil {
+		return err
+	}
+	return r.db.Model(promotion).Select("*").Omit("id", "created_at").Updates(promotion).Error
+}
+
+func (r *promotionRepository) Delete(id uint) error {
+	result := r.db.Delete(&models.Promotion{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return models.ErrPromotionNotFound
+	}
+	return nil
+}
+
+func (r *promotionRepository) List(filter models.PromotionFilter) ([]models.Promotion, error) {
+	query := r.db.Model(&models.Promotion{})
+	if filter.Scope != "" {
+		query = query.Where("scope = ?", filter.Scope)
+	}
+	if filter.TargetID != nil {
+		query = query.Where("target_id = ?", *filter.TargetID)
+	}
+	if filter.Active != nil {
+		query = query.Where("is_active = ?", *filter.Active)
+	}
+	if filter.Current {
+		query = runningPromotions(query, time.Now().UTC())
+	}
+
+	var promotions []models.Promotion
+	if err := query.Order("starts_at DESC, id DESC").Find(&promotions).Error; err != nil {
+		return nil, err
+	}
+	return promotions, nil
+}
+
+// runningPromotions narrows a promotion query down to the promotions running at a moment
+func runningPromotions(query *gorm.DB, now time.Time) *gorm.DB {
+	return query.Where("is_active AND starts_at <= ? AND (ends_at IS NULL OR ends_at > ?)", now, now)
+}
+
+// onSaleSQL returns a condition matching product rows, under the given alias,
+// that a promotion running at the moment puts on sale. It mirrors
+// Promotion.Apply: a promotion only counts when it lowers the price. With a
+// pricing, promotions apply to the resolved price, and amounts in another
+// currency are converted into it; without one they apply to the base price
+// and only amounts in its currency count.
+func onSaleSQL(alias string, now time.Time, pricing *models.PriceContext) (string, []interface{}) {
+	price := fmt.Sprintf("SELECT %[1]s.price_amount AS amount, %[1]s.price_currency AS currency", alias)
+	var priceArgs []interface{}
+	value := "CASE WHEN pr.value_currency = rp.currency THEN pr.value_amount END"
+	var valueArgs []interface{}
+	if pricing != nil {
+		var expr string
+		expr, priceArgs = resolvedPriceSQL(pricing, alias)
+		price = "SELECT " + expr + " AS amount, CAST(? AS text) AS currency"
+		priceArgs = append(priceArgs, pricing.Currency)
+		value, valueArgs = convertedAmountSQL(pricing, "pr.value_amount", "pr.value_currency")
+	}
+
+	condition := fmt.Sprintf(`EXISTS (SELECT 1 FROM (%[2]s) rp, promotions pr
+		WHERE pr.is_active AND pr.starts_at <= ? AND (pr.ends_at IS NULL OR pr.ends_at > ?)
+		AND ((pr.scope = ? AND (pr.target_id = %[1]s.id OR pr.target_id = %[1]s.parent_id))
+			OR (pr.scope = ? AND pr.target_id = %[1]s.brand_id)
+			OR (pr.scope = ? AND pr.target_id IN (WITH RECURSIVE ancestors AS (
+				SELECT id, parent_id FROM categories WHERE id = %[1]s.category_id
+				UNION
+				SELECT c.id, c.parent_id FROM categories c JOIN ancestors a ON c.id = a.parent_id
+			) SELECT id FROM ancestors)))
+		AND ((pr.type = ? AND ROUND(rp.amount * (100 - pr.percentage) / 100) < rp.amount)
+			OR (pr.type = ? AND (%[3]s) > 0 AND rp.amount > 0)
+			OR (pr.type = ? AND (%[3]s) < rp.amount)))`, alias, price, value)
+
+	args := append(priceArgs, now, now,
+		models.PromotionScopeProduct, models.PromotionScopeBrand, models.PromotionScopeCategory,
+		models.PromotionTypePercentage, models.PromotionTypeFixed)
+	args = append(args, valueArgs...)
+	args = append(args, models.PromotionTypeSalePrice)
+	return condition, append(args, valueArgs...)
+}
+
+// attachSales works out the sale price of each product, and of its variants,
+// from the promotions running at the moment. Products with a resolved price
+// also get the sale price in the pricing's currency, with the promotions
+// applied to the resolved price and their amounts converted into it.
+func attachSales(db *gorm.DB, products []models.Product, now time.Time, pricing *models.PriceContext) error {
+	var targets []*models.Product
+	for i := range products {
+		targets = append(targets, &products[i])
+		for j := range products[i].Variants {
+			targets = append(targets, &products[i].Variants[j])
+		}
+	}
+	if len(targets) == 0 {
+		return nil
+	}
+
+	// Only the promotions that can target these products are loaded: their
+	// own or their parent's, their brand's and their categories' or any
+	// category above
+	var productIDs, brandIDs, categoryIDs []uint
+	for _, product := range targets {
+		productIDs = append(productIDs, product.ID)
+		if product.ParentID != nil {
+			productIDs = append(productIDs, *product.ParentID)
+		}
+		if product.BrandID != nil {
+			brandIDs = append(brandIDs, *product.BrandID)
+		}
+		categoryIDs = append(categoryIDs, product.CategoryID)
+	}
+	var categories []models.Category
+	if err := db.Raw(`WITH RECURSIVE ancestors AS (
+			SELECT id, parent_id FROM categories WHERE id IN ?
+			UNION
+			SELECT c.id, c.parent_id FROM categories c JOIN ancestors a ON c.id = a.parent_id
+		) SELECT id, parent_id FROM ancestors`, categoryIDs).Scan(&categories).Error; err != nil {
+		return err
+	}
+	parents := make(map[uint]*uint, len(categories))
+	ancestorIDs := make([]uint, 0, len(categories))
+	for _, category := range categories {
+		parents[category.ID] = category.ParentID
+		ancestorIDs = append(ancestorIDs, category.ID)
+	}
+
+	var promotions []models.Promotion
+	if err := runningPromotions(db.Model(&models.Promotion{}), now).
+		Where("(scope = ? AND target_id IN ?) OR (scope = ? AND target_id IN ?) OR (scope = ? AND target_id IN ?)",
+			models.PromotionScopeProduct, productIDs, models.PromotionScopeBrand, brandIDs,
+			models.PromotionScopeCategory, ancestorIDs).
+		Find(&promotions).Error; err != nil {
+		return err
+	}
+	if len(promotions) == 0 {
+		return nil
+	}
+
+	for _, product := range targets {
+		ancestors := make(map[uint]bool)
+		for id := &product.CategoryID; id != nil && !ancestors[*id]; id = parents[*id] {
+			ancestors[*id] = true
+		}
+
+		var applicable []models.Promotion
+		for _, promotion := range promotions {
+			switch promotion.Scope {
+			case models.PromotionScopeProduct:
+				if promotion.TargetID != product.ID && (product.ParentID == nil || promotion.TargetID != *product.ParentID) {
+					continue
+				}
+			case models.PromotionScopeBrand:
+				if product.BrandID == nil || promotion.TargetID != *product.BrandID {
+					continue
+				}
+			case models.PromotionScopeCategory:
+				if !ancestors[promotion.TargetID] {
+					continue
+				}
+			default:
+				continue
+			}
+			applicable = append(applicable, promotion)
+		}
+
+		if best, sale := models.BestPromotion(product.Price, applicable); best != nil {
+			product.SalePrice = &sale
+			product.SaleEndsAt = best.EndsAt
+		}
+
+		if pricing == nil || product.ResolvedPrice == nil {
+			continue
+		}
+		converted := make([]models.Promotion, 0, len(applicable))
+		for _, promotion := range applicable {
+			if promotion.Type != models.PromotionTypePercentage {
+				value, ok := pricing.Convert(promotion.Value)
+				if !ok {
+					continue
+				}
+				promotion.Value = value
+			}
+			converted = append(converted, promotion)
+		}
+		if best, sale := models.BestPromotion(product.ResolvedPrice.Price, converted); best != nil {
+			product.ResolvedPrice.SalePrice = &sale
+			product.ResolvedPrice.SaleEndsAt = best.EndsAt
+		}
+	}
+	return nil
+}
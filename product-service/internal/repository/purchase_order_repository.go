@@ -0,0 +1,246 @@
+// internal/repository/purchase_order_repository.go
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"phone-accessories/internal/models"
+)
+
+type PurchaseOrderRepository interface {
+	Create(order *models.PurchaseOrder) error
+	GetByID(id uint) (*models.PurchaseOrder, error)
+	Update(order *models.PurchaseOrder) error
+	List(filter models.PurchaseOrderFilter) (*models.PaginatedResponse, error)
+	UpdateStatus(id uint, allowed []models.PurchaseOrderStatus, status models.PurchaseOrderStatus) (*models.PurchaseOrder, error)
+	Receive(id uint, receipts []models.PurchaseOrderReceipt, actor string) (*models.PurchaseOrder, []models.StockMovement, error)
+}
+
+type purchaseOrderRepository struct {
+	db *gorm.DB
+}
+
+func NewPurchaseOrderRepository(db *gorm.DB) PurchaseOrderRepository {
+	return &purchaseOrderRepository{db: db}
+}
+
+func (r *purchaseOrderRepository) Create(order *models.PurchaseOrder) error {
+	return r.db.Omit("Supplier").Create(order).Error
+}
+
+func (r *purchaseOrderRepository) GetByID(id uint) (*models.PurchaseOrder, error) {
+	var order models.PurchaseOrder
+	if err := r.db.Preload("Supplier").Preload("Lines", func(db *gorm.DB) *gorm.DB {
+		return db.Order("id ASC")
+	}).First(&order, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, models.ErrPurchaseOrderNotFound
+		}
+		return nil, err
+	}
+	return &order, nil
+}
+
+func (r *purchaseOrderRepository) Update(order *models.PurchaseOrder) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		current, err := lockPurchaseOrder(tx, order.ID)
+		if err != nil {
+			return err
+		}
+		// Once sent, the order is what the supplier works from and must not change
+		if current.Status != models.PurchaseOrderStatusDraft {
+			return fmt.Errorf("%w: only draft purchase orders can be edited", models.ErrPurchaseOrderStatus)
+		}
+
+		order.Status = current.Status
+		order.SentAt = current.SentAt
+		if err := tx.Model(order).
+			Select("reference", "supplier_id", "location_id", "expected_at", "notes").
+			Updates(order).Error; err != nil {
+			return err
+		}
+
+		// Replace the lines wholesale
+		if err := tx.Where("purchase_order_id = ?", order.ID).Delete(&models.PurchaseOrderLine{}).Error; err != nil {
+			return err
+		}
+		for i := range order.Lines {
+			order.Lines[i].ID = 0
+			order.Lines[i].PurchaseOrderID = order.ID
+			order.Lines[i].QuantityReceived = 0
+		}
+		if len(order.Lines) > 0 {
+			if err := tx.Create(&order.Lines).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (r *purchaseOrderRepository) List(filter models.PurchaseOrderFilter) (*models.PaginatedResponse, error) {
+	var orders []models.PurchaseOrder
+	var totalItems int64
+
+	query := r.db.Model(&models.PurchaseOrder{})
+
+	if filter.SupplierID != nil {
+		query = query.Where("supplier_id = ?", *filter.SupplierID)
+	}
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+
+	if err := query.Count(&totalItems).Error; err != nil {
+		return nil, err
+	}
+
+	if filter.Page < 1 {
+		filter.Page = 1
+	}
+	if filter.PageSize < 1 {
+		filter.PageSize = 20
+	}
+	offset := (filter.Page - 1) * filter.PageSize
+
+	if err := query.Preload("Supplier").Preload("Lines").Order("id DESC").
+		Offset(offset).Limit(filter.PageSize).Find(&orders).Error; err != nil {
+		return nil, err
+	}
+
+	totalPages := int(math.Ceil(float64(totalItems) / float64(filter.PageSize)))
+	return &models.PaginatedResponse{
+		Items:      orders,
+		Page:       filter.Page,
+		PageSize:   filter.PageSize,
+		TotalItems: totalItems,
+		TotalPages: totalPages,
+	}, nil
+}
+
+func (r *purchaseOrderRepository) UpdateStatus(id uint, allowed []models.PurchaseOrderStatus, status models.PurchaseOrderStatus) (*models.PurchaseOrder, error) {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		order, err := lockPurchaseOrder(tx, id)
+		if err != nil {
+			return err
+		}
+		if !containsStatus(allowed, order.Status) {
+			return fmt.Errorf("%w: cannot move a %s purchase order to %s", models.ErrPurchaseOrderStatus, order.Status, status)
+		}
+
+		updates := map[string]interface{}{"status": status}
+		if status == models.PurchaseOrderStatusSent {
+			updates["sent_at"] = time.Now().UTC()
+		}
+		return tx.Model(order).Updates(updates).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return r.GetByID(id)
+}
+
+// Receive books the quantities that arrived against the order's lines and adds
+// them to stock through the same ledgered stock change as UpdateStock, all in
+// one transaction. It returns the stock movements for the caller to pass on
+// to the stock service once they are committed.
+func (r *purchaseOrderRepository) Receive(id uint, receipts []models.PurchaseOrderReceipt, actor string) (*models.PurchaseOrder, []models.StockMovement, error) {
+	var movements []models.StockMovement
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		order, err := lockPurchaseOrder(tx, id)
+		if err != nil {
+			return err
+		}
+		if order.Status != models.PurchaseOrderStatusSent && order.Status != models.PurchaseOrderStatusPartiallyReceived {
+			return fmt.Errorf("%w: cannot receive goods on a %s purchase order", models.ErrPurchaseOrderStatus, order.Status)
+		}
+
+		var lines []models.PurchaseOrderLine
+		if err := tx.Where("purchase_order_id = ?", order.ID).Find(&lines).Error; err != nil {
+			return err
+		}
+		linesByID := make(map[uint]*models.PurchaseOrderLine, len(lines))
+		for i := range lines {
+			linesByID[lines[i].ID] = &lines[i]
+		}
+
+		var locationID uint
+		if order.LocationID != nil {
+			locationID = *order.LocationID
+		}
+
+		for _, receipt := range receipts {
+			line, ok := linesByID[receipt.LineID]
+			if !ok {
+				return fmt.Errorf("line %d does not belong to purchase order %d", receipt.LineID, order.ID)
+			}
+			remaining := line.QuantityOrdered - line.QuantityReceived
+			if receipt.Quantity > remaining {
+				return fmt.Errorf("cannot receive %d units on line %d; only %d outstanding", receipt.Quantity, line.ID, remaining)
+			}
+
+			line.QuantityReceived += receipt.Quantity
+			if err := tx.Model(line).Update("quantity_received", line.QuantityReceived).Error; err != nil {
+				return err
+			}
+
+			movement, err := applyStockChange(tx, models.StockChange{
+				ProductID:   line.ProductID,
+				LocationID:  locationID,
+				Delta:       receipt.Quantity,
+				Reason:      models.StockReasonRestock,
+				ReferenceID: fmt.Sprintf("po:%d", order.ID),
+				Actor:       actor,
+				UnitCost:    &line.UnitCost,
+			})
+			if err != nil {
+				return err
+			}
+			movements = append(movements, *movement)
+		}
+
+		status := models.PurchaseOrderStatusReceived
+		for _, line := range lines {
+			if line.QuantityReceived < line.QuantityOrdered {
+				status = models.PurchaseOrderStatusPartiallyReceived
+				break
+			}
+		}
+		return tx.Model(order).Update("status", status).Error
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	order, err := r.GetByID(id)
+	if err != nil {
+		return nil, nil, err
+	}
+	return order, movements, nil
+}
+
+// lockPurchaseOrder loads a purchase order, locking the row for the rest of the transaction
+func lockPurchaseOrder(tx *gorm.DB, id uint) (*models.PurchaseOrder, error) {
+	var order models.PurchaseOrder
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&order, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, models.ErrPurchaseOrderNotFound
+		}
+		return nil, err
+	}
+	return &order, nil
+}
+
+func containsStatus(statuses []models.PurchaseOrderStatus, status models.PurchaseOrderStatus) bool {
+	for _, s := range statuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,125 @@
+// internal/repository/relation_repository.go
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+
+	"phone-accessories/internal/models"
+)
+
+type RelationRepository interface {
+	Create(relation *models.ProductRelation) error
+	GetByID(productID, id uint) (*models.ProductRelation, error)
+	Update(relation *models.ProductRelation) error
+	Delete(productID, id uint) error
+	List(productID uint, relationType models.RelationType) ([]models.ProductRelation, error)
+}
+
+type relationRepository struct {
+	db *gorm.DB
+}
+
+func NewRelationRepository(db *gorm.DB) RelationRepository {
+	return &relationRepository{db: db}
+}
+
+// Create adds a relation after the product's other relations of the same type
+func (r *relationRepository) Create(relation *models.ProductRelation) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := checkRelationUnique(tx, relation); err != nil {
+			return err
+		}
+
+		if err := tx.Model(&models.ProductRelation{}).Select("COALESCE(MAX(position) + 1, 0)").
+			Where("product_id = ? AND type = ?", relation.ProductID, relation.Type).
+			Scan(&relation.Position).Error; err != nil {
+			return err
+		}
+		return tx.Omit("Target").Create(relation).Error
+	})
+}
+
+func (r *relationRepository) GetByID(productID, id uint) (*models.ProductRelation, error) {
+	var relation models.ProductRelation
+	if err := r.db.Where("product_id = ?", productID).First(&relation, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, models.ErrRelationNotFound
+		}
+		return nil, err
+	}
+	return &relation, nil
+}
+
+func (r *relationRepository) Update(relation *models.ProductRelation) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := checkRelationUnique(tx, relation); err != nil {
+			return err
+		}
+		return tx.Model(relation).Select("type", "position").Updates(relation).Error
+	})
+}
+
+func (r *relationRepository) Delete(productID, id uint) error {
+	result := r.db.Where("product_id = ?", productID).Delete(&models.ProductRelation{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return models.ErrRelationNotFound
+	}
+	return nil
+}
+
+// List returns a product's relations, optionally of a single type, with their
+// targets and the targets' availability
+func (r *relationRepository) List(productID uint, relationType models.RelationType) ([]models.ProductRelation, error) {
+	query := r.db.Where("product_id = ?", productID)
+	if relationType != "" {
+		query = query.Where("type = ?", relationType)
+	}
+
+	var relations []models.ProductRelation
+	if err := query.Preload("Target").Preload("Target.Variants", func(db *gorm.DB) *gorm.DB {
+		return db.Order("id ASC")
+	}).Order("type ASC, position ASC, id ASC").Find(&relations).Error; err != nil {
+		return nil, err
+	}
+
+	targets := make([]models.Product, 0, len(relations))
+	for _, relation := range relations {
+		if relation.Target != nil {
+			targets = append(targets, *relation.Target)
+		}
+	}
+	if err := attachBundleAvailability(r.db, targets); err != nil {
+		return nil, err
+	}
+	if err := attachSales(r.db, targets, time.Now().UTC(), nil); err != nil {
+		return nil, err
+	}
+	byID := make(map[uint]*models.Product, len(targets))
+	for i := range targets {
+		byID[targets[i].ID] = &targets[i]
+	}
+	for i := range relations {
+		relations[i].Target = byID[relations[i].TargetID]
+	}
+	return relations, nil
+}
+
+func checkRelationUnique(tx *gorm.DB, relation *models.ProductRelation) error {
+	var count int64
+	if err := tx.Model(&models.ProductRelation{}).
+		Where("product_id = ? AND type = ? AND target_id = ? AND id <> ?",
+			relation.ProductID, relation.Type, relation.TargetID, relation.ID).
+		Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return models.ErrRelationExists
+	}
+	return nil
+}
@@ -0,0 +1,117 @@
+// internal/repository/report_repository.go
+package repository
+
+import (
+	"math"
+	"time"
+
+	"gorm.io/gorm"
+
+	"phone-accessories/internal/models"
+)
+
+type ReportRepository interface {
+	InventoryValuation(before time.Time) ([]models.InventoryValuationLine, error)
+	MigrateCosts() error
+	CostDrift() ([]models.CostDrift, error)
+}
+
+type reportRepository struct {
+	db *gorm.DB
+}
+
+func NewReportRepository(db *gorm.DB) ReportRepository {
+	return &reportRepository{db: db}
+}
+
+// InventoryValuation values the stock of every product as it stood just before
+// the given time, by replaying its cost layers and what had been consumed from
+// them by then
+func (r *reportRepository) InventoryValuation(before time.Time) ([]models.InventoryValuationLine, error) {
+	consumed := r.db.Model(&models.CostConsumption{}).
+		Select("layer_id, SUM(quantity) AS quantity").
+		Where("created_at < ?", before).
+		Group("layer_id")
+
+	var lines []models.InventoryValuationLine
+	if err := r.db.Table("cost_layers l").
+		Select(`l.product_id, p.sku, p.name, p.category_id, COALESCE(c.name, '') AS category_name,
+			SUM(l.quantity - COALESCE(cc.quantity, 0)) AS quantity,
+			CAST(SUM((l.quantity - COALESCE(cc.quantity, 0)) * l.unit_cost_amount) AS bigint) AS value`).
+		Joins("LEFT JOIN (?) cc ON cc.layer_id = l.id", consumed).
+		Joins("JOIN products p ON p.id = l.product_id").
+		Joins("LEFT JOIN categories c ON c.id = p.category_id").
+		Where("l.created_at < ?", before).
+		Group("l.product_id, p.sku, p.name, p.category_id, c.name").
+		Having("SUM(l.quantity - COALESCE(cc.quantity, 0)) > 0").
+		Order("c.name ASC, p.sku ASC").
+		Scan(&lines).Error; err != nil {
+		return nil, err
+	}
+	return lines, nil
+}
+
+// costTables lists the tables whose unit cost moved from a decimal number to
+// minor units of the default currency
+var costTables = []interface{}{&models.CostLayer{}, &models.CostConsumption{}, &models.PurchaseOrderLine{}}
+
+// MigrateCosts moves unit costs stored as decimal numbers into minor units of
+// the default currency and drops the old columns. Once, it then opens a cost
+// layer for stock that no layer accounts for, such as stock that was on hand
+// before cost layers existed, at the product's latest cost or zero when it has
+// none, so that selling it does not leave later layers short. Stock and layers
+// that disagree after that are reported by CostDrift rather than patched over.
+func (r *reportRepository) MigrateCosts() error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		migrator := tx.Migrator()
+		for _, table := range costTables {
+			if !migrator.HasColumn(table, "unit_cost") {
+				continue
+			}
+			// Go through numeric so that 12.99 becomes 1299 and not 1298
+			if err := tx.Model(table).Where("1 = 1").Update("unit_cost_amount",
+				gorm.Expr("ROUND(CAST(unit_cost AS numeric) * ?)",
+					int64(math.Pow10(models.CurrencyExponent(models.DefaultCurrency))))).Error; err != nil {
+				return err
+			}
+			if err := migrator.DropColumn(table, "unit_cost"); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return runOnce(r.db, "opening-cost-layers", func(tx *gorm.DB) error {
+		return tx.Exec(`INSERT INTO cost_layers (product_id, movement_id, quantity, remaining, unit_cost_amount, created_at)
+			SELECT p.id, 0, p.stock_level - COALESCE(l.remaining, 0), p.stock_level - COALESCE(l.remaining, 0),
+				COALESCE((SELECT x.unit_cost_amount FROM cost_layers x WHERE x.product_id = p.id ORDER BY x.id DESC LIMIT 1), 0),
+				NOW()
+			FROM products p
+			LEFT JOIN (SELECT product_id, SUM(remaining) AS remaining FROM cost_layers GROUP BY product_id) l
+				ON l.product_id = p.id
+			WHERE p.deleted_at IS NULL AND NOT p.is_bundle
+				AND NOT EXISTS (SELECT 1 FROM products v WHERE v.parent_id = p.id AND v.deleted_at IS NULL)
+				AND p.stock_level > COALESCE(l.remaining, 0)`).Error
+	})
+}
+
+// CostDrift lists the products whose stock on hand differs from the units left
+// in their cost layers, which the valuation report would value wrongly
+func (r *reportRepository) CostDrift() ([]models.CostDrift, error) {
+	var drift []models.CostDrift
+	if err := r.db.Table("products p").
+		Select("p.id AS product_id, p.sku, p.stock_level, COALESCE(l.remaining, 0) AS layered").
+		Joins("LEFT JOIN (SELECT product_id, SUM(remaining) AS remaining FROM cost_layers GROUP BY product_id) l ON l.product_id = p.id").
+		Where("p.deleted_at IS NULL AND NOT p.is_bundle").
+		Where("NOT EXISTS (SELECT 1 FROM products v WHERE v.parent_id = p.id AND v.deleted_at IS NULL)").
+		// Products selling ahead of stock can go below zero; layers cannot
+		Where("GREATEST(p.stock_level, 0) <> COALESCE(l.remaining, 0)").
+		Order("p.sku ASC").
+		Scan(&drift).Error; err != nil {
+		return nil, err
+	}
+	return drift, nil
+}
@@ -0,0 +1,408 @@
+// internal/repository/reservation_repository.go
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"phone-accessories/internal/models"
+)
+
+type ReservationRepository interface {
+	Create(reservation *models.Reservation) error
+	GetByID(id uint) (*models.Reservation, error)
+	Confirm(id uint) (*models.Reservation, []models.StockMovement, error)
+	Release(id uint) (*models.Reservation, error)
+	ReleaseExpired(now time.Time) (int, error)
+}
+
+type reservationRepository struct {
+	db *gorm.DB
+}
+
+func NewReservationRepository(db *gorm.DB) ReservationRepository {
+	return &reservationRepository{db: db}
+}
+
+func (r *reservationRepository) Create(reservation *models.Reservation) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		items, err := expandBundleItems(tx, reservation.Items)
+		if err != nil {
+			return err
+		}
+
+		reservation.Items = make([]models.ReservationItem, 0, len(items))
+		for _, item := range items {
+			// Only hold the units if they are still available, so concurrent
+			// checkouts cannot reserve the same stock twice. Products on
+			// backorder or preorder may be reserved ahead of stock up to their cap.
+			result := tx.Model(&models.Product{}).
+				Where("id = ?", item.ProductID).
+				Where("NOT EXISTS (SELECT 1 FROM products v WHERE v.parent_id = products.id AND v.deleted_at IS NULL)").
+				Where(`stock_level - reserved_level >= ? OR (availability_policy IN ? AND
+					(backorder_limit IS NULL OR stock_level - reserved_level + backorder_limit >= ?))`,
+					item.Quantity, sellAheadPolicies, item.Quantity).
+				Update("reserved_level", gorm.Expr("reserved_level + ?", item.Quantity))
+			if result.Error != nil {
+				return result.Error
+			}
+			if result.RowsAffected == 0 {
+				return stockUnavailableError(tx, item.ProductID, item.Quantity)
+			}
+			held, err := holdLocations(tx, item)
+			if err != nil {
+				return err
+			}
+			reservation.Items = append(reservation.Items, held...)
+		}
+
+		reservation.Status = models.ReservationStatusHeld
+		return tx.Create(reservation).Error
+	})
+}
+
+func (r *reservationRepository) GetByID(id uint) (*models.Reservation, error) {
+	var reservation models.Reservation
+	if err := r.db.Preload("Items").First(&reservation, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, models.ErrReservationNotFound
+		}
+		return nil, err
+	}
+	return &reservation, nil
+}
+
+func (r *reservationRepository) Confirm(id uint) (*models.Reservation, []models.StockMovement, error) {
+	var reservation *models.Reservation
+	var movements []models.StockMovement
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		reservation, err = lockReservation(tx, id)
+		if err != nil {
+			return err
+		}
+		if reservation.Status != models.ReservationStatusHeld {
+			return models.ErrReservationNotHeld
+		}
+		// Holds past their TTL are left for the sweeper to release
+		if time.Now().UTC().After(reservation.ExpiresAt) {
+			return models.ErrReservationExpired
+		}
+
+		for _, item := range reservation.Items {
+			// The hold is lifted before the sale so that the sale takes the
+			// units the reservation held rather than other available stock
+			if err := releaseHold(tx, item); err != nil {
+				return err
+			}
+			// Items held before reservations were held at locations sell from
+			// wherever their units are available
+			shares := []locationShare{{Quantity: item.Quantity}}
+			if item.LocationID != nil {
+				shares[0].LocationID = *item.LocationID
+			} else if shares, err = locationShares(tx, item.ProductID, item.Quantity); err != nil {
+				return err
+			}
+			for _, share := range shares {
+				movement, err := applyStockChange(tx, models.StockChange{
+					ProductID:   item.ProductID,
+					LocationID:  share.LocationID,
+					Delta:       -share.Quantity,
+					Reason:      models.StockReasonSale,
+					ReferenceID: fmt.Sprintf("reservation:%d", reservation.ID),
+				})
+				if err != nil {
+					return err
+				}
+				movements = append(movements, *movement)
+			}
+		}
+
+		reservation.Status = models.ReservationStatusConfirmed
+		return tx.Model(reservation).Update("status", reservation.Status).Error
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return reservation, movements, nil
+}
+
+func (r *reservationRepository) Release(id uint) (*models.Reservation, error) {
+	var reservation *models.Reservation
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		reservation, err = lockReservation(tx, id)
+		if err != nil {
+			return err
+		}
+		return releaseReservation(tx, reservation, models.ReservationStatusReleased)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return reservation, nil
+}
+
+func (r *reservationRepository) ReleaseExpired(now time.Time) (int, error) {
+	var ids []uint
+	if err := r.db.Model(&models.Reservation{}).
+		Where("status = ? AND expires_at <= ?", models.ReservationStatusHeld, now).
+		Pluck("id", &ids).Error; err != nil {
+		return 0, err
+	}
+
+	released := 0
+	for _, id := range ids {
+		err := r.db.Transaction(func(tx *gorm.DB) error {
+			reservation, err := lockReservation(tx, id)
+			if err != nil {
+				return err
+			}
+			return releaseReservation(tx, reservation, models.ReservationStatusExpired)
+		})
+		if errors.Is(err, models.ErrReservationNotHeld) {
+			// Confirmed or released by another request since we listed it
+			continue
+		}
+		if err != nil {
+			return released, err
+		}
+		released++
+	}
+	return released, nil
+}
+
+// lockReservation loads a reservation and its items, locking the row for the
+// rest of the transaction
+func lockReservation(tx *gorm.DB, id uint) (*models.Reservation, error) {
+	var reservation models.Reservation
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&reservation, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, models.ErrReservationNotFound
+		}
+		return nil, err
+	}
+	if err := tx.Where("reservation_id = ?", id).Find(&reservation.Items).Error; err != nil {
+		return nil, err
+	}
+	return &reservation, nil
+}
+
+// releaseReservation returns the held units of a reservation to available
+// stock and moves it to the given final status
+func releaseReservation(tx *gorm.DB, reservation *models.Reservation, status models.ReservationStatus) error {
+	if reservation.Status != models.ReservationStatusHeld {
+		return models.ErrReservationNotHeld
+	}
+
+	for _, item := range reservation.Items {
+		if err := releaseHold(tx, item); err != nil {
+			return err
+		}
+	}
+
+	reservation.Status = status
+	return tx.Model(reservation).Update("status", status).Error
+}
+
+// holdLocations holds a reservation item's units at locations, so that
+// confirming the reservation sells the units it held. An item naming a
+// location is held there; any other is held where its units are available,
+// split into one item per location when no single location has them all.
+func holdLocations(tx *gorm.DB, item models.ReservationItem) ([]models.ReservationItem, error) {
+	if item.LocationID != nil {
+		return []models.ReservationItem{item}, holdLocationStock(tx, item)
+	}
+
+	shares, err := locationShares(tx, item.ProductID, item.Quantity)
+	if err != nil {
+		return nil, err
+	}
+	items := make([]models.ReservationItem, 0, len(shares))
+	for _, share := range shares {
+		locationID := share.LocationID
+		held := item
+		held.LocationID = &locationID
+		held.Quantity = share.Quantity
+		if err := holdLocationStock(tx, held); err != nil {
+			return nil, err
+		}
+		items = append(items, held)
+	}
+	return items, nil
+}
+
+// locationShare is the part of a quantity taken from one location
+type locationShare struct {
+	LocationID uint
+	Quantity   int
+}
+
+// locationShares works out which active locations a quantity of a product is
+// taken from when no location is named
+func locationShares(tx *gorm.DB, productID uint, quantity int) ([]locationShare, error) {
+	var stocks []models.LocationStock
+	if err := tx.Joins("JOIN locations ON locations.id = location_stocks.location_id AND locations.is_active").
+		Where("location_stocks.product_id = ? AND location_stocks.stock_level > location_stocks.reserved_level", productID).
+		Order("location_stocks.location_id ASC").
+		Find(&stocks).Error; err != nil {
+		return nil, err
+	}
+	var defaultLocation models.Location
+	if err := tx.Select("id").Where("is_default AND is_active").Limit(1).Find(&defaultLocation).Error; err != nil {
+		return nil, err
+	}
+	return splitQuantity(stocks, defaultLocation.ID, quantity), nil
+}
+
+// splitQuantity shares a quantity out over the locations it is available at.
+// The default location takes it all when it has it available, then any
+// location that does; otherwise the locations with the most available go
+// first. Whatever no location has, which only products selling ahead of stock
+// may take, falls to the default location.
+func splitQuantity(stocks []models.LocationStock, defaultID uint, quantity int) []locationShare {
+	available := func(stock models.LocationStock) int {
+		return stock.StockLevel - stock.ReservedLevel
+	}
+	sorted := make([]models.LocationStock, len(stocks))
+	copy(sorted, stocks)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return available(sorted[i]) > available(sorted[j])
+	})
+
+	for _, stock := range sorted {
+		if stock.LocationID == defaultID && available(stock) >= quantity {
+			return []locationShare{{LocationID: defaultID, Quantity: quantity}}
+		}
+	}
+	if len(sorted) > 0 && available(sorted[0]) >= quantity {
+		return []locationShare{{LocationID: sorted[0].LocationID, Quantity: quantity}}
+	}
+
+	var shares []locationShare
+	remaining := quantity
+	for _, stock := range sorted {
+		if remaining == 0 {
+			break
+		}
+		share := available(stock)
+		if share <= 0 {
+			continue
+		}
+		if share > remaining {
+			share = remaining
+		}
+		shares = append(shares, locationShare{LocationID: stock.LocationID, Quantity: share})
+		remaining -= share
+	}
+	if remaining == 0 {
+		return shares
+	}
+	for i := range shares {
+		if shares[i].LocationID == defaultID {
+			shares[i].Quantity += remaining
+			return shares
+		}
+	}
+	return append(shares, locationShare{LocationID: defaultID, Quantity: remaining})
+}
+
+// holdLocationStock holds a reservation item's units at the location it
+// names, which must exist and be active, so that other changes and transfers
+// cannot take them from there. Products that sell ahead of stock may be held
+// beyond what the location has, as they may be sold beyond it.
+func holdLocationStock(tx *gorm.DB, item models.ReservationItem) error {
+	locationID, err := resolveLocationID(tx, *item.LocationID)
+	if err != nil {
+		return err
+	}
+	// A location that never held the product has no row to hold units in yet
+	if err := tx.Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&models.LocationStock{ProductID: item.ProductID, LocationID: locationID}).Error; err != nil {
+		return err
+	}
+
+	result := tx.Model(&models.LocationStock{}).
+		Where("product_id = ? AND location_id = ?", item.ProductID, locationID).
+		Where(`stock_level - reserved_level >= ? OR EXISTS (SELECT 1 FROM products p
+			WHERE p.id = location_stocks.product_id AND p.availability_policy IN ?)`, item.Quantity, sellAheadPolicies).
+		Update("reserved_level", gorm.Expr("reserved_level + ?", item.Quantity))
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected > 0 {
+		return nil
+	}
+
+	var stock models.LocationStock
+	if err := tx.Where("product_id = ? AND location_id = ?", item.ProductID, locationID).
+		Limit(1).Find(&stock).Error; err != nil {
+		return err
+	}
+	return &models.InsufficientStockError{
+		ProductID: item.ProductID,
+		Requested: item.Quantity,
+		Available: stock.StockLevel - stock.ReservedLevel,
+	}
+}
+
+// releaseHold returns the units a reservation item holds to available stock,
+// for the product and at the item's location
+func releaseHold(tx *gorm.DB, item models.ReservationItem) error {
+	if err := tx.Model(&models.Product{}).Where("id = ?", item.ProductID).
+		Update("reserved_level", gorm.Expr("reserved_level - ?", item.Quantity)).Error; err != nil {
+		return err
+	}
+	if item.LocationID == nil {
+		return nil
+	}
+	// Holds placed before locations tracked them have nothing to release there
+	return tx.Model(&models.LocationStock{}).
+		Where("product_id = ? AND location_id = ?", item.ProductID, *item.LocationID).
+		Update("reserved_level", gorm.Expr("GREATEST(reserved_level - ?, 0)", item.Quantity)).Error
+}
+
+// stockUnavailableError explains why a guarded reservation update matched no rows
+func stockUnavailableError(tx *gorm.DB, productID uint, requested int) error {
+	var product models.Product
+	if err := tx.Select("id", "stock_level", "reserved_level", "availability_policy", "backorder_limit").
+		First(&product, productID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("%w: %d", models.ErrProductNotFound, productID)
+		}
+		return err
+	}
+	var variants int64
+	if err := tx.Model(&models.Product{}).Where("parent_id = ?", productID).Count(&variants).Error; err != nil {
+		return err
+	}
+	if variants > 0 {
+		return fmt.Errorf("%w: %d", models.ErrProductHasVariants, productID)
+	}
+	return &models.InsufficientStockError{
+		ProductID: productID,
+		Requested: requested,
+		Available: sellableQuantity(&product),
+	}
+}
+
+// sellAheadPolicies are the availability policies that allow selling a
+// product before its stock arrives
+var sellAheadPolicies = []models.AvailabilityPolicy{
+	models.AvailabilityPolicyBackorder,
+	models.AvailabilityPolicyPreorder,
+}
+
+// sellableQuantity returns how many more units of a product can be reserved,
+// counting any capped backorder allowance
+func sellableQuantity(product *models.Product) int {
+	if product.AvailabilityPolicy.SellsAheadOfStock() && product.BackorderLimit != nil {
+		return product.AvailableQuantity + *product.BackorderLimit
+	}
+	return product.AvailableQuantity
+}
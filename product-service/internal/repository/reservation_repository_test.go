@@ -0,0 +1,98 @@
+// internal/repository/reservation_repository_test.go
+package repository
+
+import (
+	"reflect"
+	"testing"
+
+	"phone-accessories/internal/models"
+)
+
+func TestSplitQuantity(t *testing.T) {
+	const defaultID = 1
+
+	tests := []struct {
+		name     string
+		stocks   []models.LocationStock
+		quantity int
+		want     []locationShare
+	}{
+		{
+			name: "default location has it all",
+			stocks: []models.LocationStock{
+				{LocationID: 1, StockLevel: 5},
+				{LocationID: 2, StockLevel: 50},
+			},
+			quantity: 5,
+			want:     []locationShare{{LocationID: 1, Quantity: 5}},
+		},
+		{
+			name: "units held at the default location are not available",
+			stocks: []models.LocationStock{
+				{LocationID: 1, StockLevel: 5, ReservedLevel: 3},
+				{LocationID: 2, StockLevel: 4},
+			},
+			quantity: 4,
+			want:     []locationShare{{LocationID: 2, Quantity: 4}},
+		},
+		{
+			name: "another location has it all",
+			stocks: []models.LocationStock{
+				{LocationID: 2, StockLevel: 3},
+				{LocationID: 3, StockLevel: 8},
+			},
+			quantity: 6,
+			want:     []locationShare{{LocationID: 3, Quantity: 6}},
+		},
+		{
+			name: "split over the locations with the most available",
+			stocks: []models.LocationStock{
+				{LocationID: 1, StockLevel: 2},
+				{LocationID: 2, StockLevel: 3},
+				{LocationID: 3, StockLevel: 4, ReservedLevel: 1},
+			},
+			quantity: 7,
+			want: []locationShare{
+				{LocationID: 2, Quantity: 3},
+				{LocationID: 3, Quantity: 3},
+				{LocationID: 1, Quantity: 1},
+			},
+		},
+		{
+			name: "shortfall falls to the default location it is already taken from",
+			stocks: []models.LocationStock{
+				{LocationID: 1, StockLevel: 2},
+				{LocationID: 2, StockLevel: 3},
+			},
+			quantity: 8,
+			want: []locationShare{
+				{LocationID: 2, Quantity: 3},
+				{LocationID: 1, Quantity: 5},
+			},
+		},
+		{
+			name: "shortfall falls to the default location",
+			stocks: []models.LocationStock{
+				{LocationID: 2, StockLevel: 3},
+			},
+			quantity: 5,
+			want: []locationShare{
+				{LocationID: 2, Quantity: 3},
+				{LocationID: 1, Quantity: 2},
+			},
+		},
+		{
+			name:     "nothing in stock",
+			quantity: 2,
+			want:     []locationShare{{LocationID: 1, Quantity: 2}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := splitQuantity(tt.stocks, defaultID, tt.quantity); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("splitQuantity() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
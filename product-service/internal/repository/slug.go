@@ -0,0 +1,104 @@
+// internal/repository/slug.go
+package repository
+
+import (
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"phone-accessories/internal/models"
+	"phone-accessories/internal/slug"
+)
+
+// assignSlug returns a slug for the record that no other record uses, now or
+// as an old slug, adding a numeric suffix to the wanted slug when needed. When
+// the record moves away from a previous slug, that slug is kept as a redirect.
+func assignSlug(tx *gorm.DB, entity models.SlugEntity, table string, id uint, wanted, previous string) (string, error) {
+	if wanted == previous {
+		return previous, nil
+	}
+
+	var candidate string
+	for n := 1; ; n++ {
+		candidate = slug.WithSuffix(wanted, n)
+
+		// Soft-deleted records keep their slug, so they are counted as well
+		var count int64
+		if err := tx.Table(table).Where("slug = ? AND id <> ?", candidate, id).Count(&count).Error; err != nil {
+			return "", err
+		}
+		if count == 0 {
+			if err := tx.Model(&models.SlugRedirect{}).
+				Where("entity = ? AND slug = ? AND target_id <> ?", entity, candidate, id).
+				Count(&count).Error; err != nil {
+				return "", err
+			}
+		}
+		if count == 0 {
+			break
+		}
+	}
+
+	if id == 0 {
+		return candidate, nil
+	}
+	if previous != "" {
+		redirect := models.SlugRedirect{Entity: entity, Slug: previous, TargetID: id}
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&redirect).Error; err != nil {
+			return "", err
+		}
+	}
+	// A record taking back one of its own old slugs no longer needs the redirect
+	if err := tx.Where("entity = ? AND slug = ? AND target_id = ?", entity, candidate, id).
+		Delete(&models.SlugRedirect{}).Error; err != nil {
+		return "", err
+	}
+	return candidate, nil
+}
+
+// findBySlug returns the ID of the record that has the slug, or used to have
+// it, or 0 when there is none
+func findBySlug(db *gorm.DB, entity models.SlugEntity, table, value string) (uint, error) {
+	var ids []uint
+	if err := db.Table(table).Where("slug = ? AND deleted_at IS NULL", value).Limit(1).Pluck("id", &ids).Error; err != nil {
+		return 0, err
+	}
+	if len(ids) > 0 {
+		return ids[0], nil
+	}
+
+	if err := db.Model(&models.SlugRedirect{}).Where("entity = ? AND slug = ?", entity, value).
+		Limit(1).Pluck("target_id", &ids).Error; err != nil {
+		return 0, err
+	}
+	if len(ids) > 0 {
+		return ids[0], nil
+	}
+	return 0, nil
+}
+
+// backfillSlugs gives every record created before slugs existed a slug
+// generated from its name
+func backfillSlugs(db *gorm.DB, entity models.SlugEntity, table, fallback string) error {
+	var rows []struct {
+		ID   uint
+		Name string
+	}
+	if err := db.Table(table).Select("id, name").
+		Where("(slug IS NULL OR slug = '') AND deleted_at IS NULL").
+		Order("id ASC").Scan(&rows).Error; err != nil {
+		return err
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		for _, row := range rows {
+			value, err := assignSlug(tx, entity, table, row.ID, slug.Derive("", row.Name, "", "", fallback), "")
+			if err != nil {
+				return err
+			}
+			if err := tx.Table(table).Where("id = ?", row.ID).Update("slug", value).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
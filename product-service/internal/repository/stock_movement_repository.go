@@ -0,0 +1,276 @@
+// internal/repository/stock_movement_repository.go
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"phone-accessories/internal/models"
+)
+
+type StockMovementRepository interface {
+	List(productID uint, filter models.StockMovementFilter) (*models.PaginatedResponse, error)
+}
+
+type stockMovementRepository struct {
+	db *gorm.DB
+}
+
+func NewStockMovementRepository(db *gorm.DB) StockMovementRepository {
+	return &stockMovementRepository{db: db}
+}
+
+func (r *stockMovementRepository) List(productID uint, filter models.StockMovementFilter) (*models.PaginatedResponse, error) {
+	var movements []models.StockMovement
+	var totalItems int64
+
+	query := r.db.Model(&models.StockMovement{}).Where("product_id = ?", productID)
+
+	if filter.From != nil {
+		query = query.Where("created_at >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		// The end date is inclusive, so match everything before the next day
+		query = query.Where("created_at < ?", filter.To.AddDate(0, 0, 1))
+	}
+	if filter.Reason != "" {
+		query = query.Where("reason = ?", filter.Reason)
+	}
+	if filter.LocationID != nil {
+		query = query.Where("location_id = ?", *filter.LocationID)
+	}
+
+	if err := query.Count(&totalItems).Error; err != nil {
+		return nil, err
+	}
+
+	if filter.Page < 1 {
+		filter.Page = 1
+	}
+	if filter.PageSize < 1 {
+		filter.PageSize = 20
+	}
+	offset := (filter.Page - 1) * filter.PageSize
+
+	if err := query.Order("created_at DESC, id DESC").Offset(offset).Limit(filter.PageSize).Find(&movements).Error; err != nil {
+		return nil, err
+	}
+
+	totalPages := int(math.Ceil(float64(totalItems) / float64(filter.PageSize)))
+	return &models.PaginatedResponse{
+		Items:      movements,
+		Page:       filter.Page,
+		PageSize:   filter.PageSize,
+		TotalItems: totalItems,
+		TotalPages: totalPages,
+	}, nil
+}
+
+// applyStockChange adjusts a product's stock level at a location, keeps the
+// product's aggregate level in step and records the movement in the ledger. It
+// must be called inside a transaction so that the level change and its ledger
+// entry are committed together.
+func applyStockChange(tx *gorm.DB, change models.StockChange) (*models.StockMovement, error) {
+	var product models.Product
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Select("id", "stock_level", "reserved_level", "availability_policy", "backorder_limit", "is_bundle").
+		First(&product, change.ProductID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("%w: %d", models.ErrProductNotFound, change.ProductID)
+		}
+		return nil, err
+	}
+	if product.IsBundle {
+		return applyBundleChange(tx, product.ID, change)
+	}
+
+	var variants int64
+	if err := tx.Model(&models.Product{}).Where("parent_id = ?", product.ID).Count(&variants).Error; err != nil {
+		return nil, err
+	}
+	if variants > 0 {
+		return nil, fmt.Errorf("%w: %d", models.ErrProductHasVariants, product.ID)
+	}
+
+	locationID, err := resolveLocationID(tx, change.LocationID)
+	if err != nil {
+		return nil, err
+	}
+
+	var stock models.LocationStock
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("product_id = ? AND location_id = ?", product.ID, locationID).
+		Limit(1).Find(&stock).Error; err != nil {
+		return nil, err
+	}
+
+	if change.Delta < 0 {
+		if err := checkOutgoingStock(&product, &stock, change); err != nil {
+			return nil, err
+		}
+	}
+	locationLevel := stock.StockLevel + change.Delta
+	level := product.StockLevel + change.Delta
+
+	if err := tx.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "product_id"}, {Name: "location_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"stock_level", "updated_at"}),
+	}).Create(&models.LocationStock{
+		ProductID:  product.ID,
+		LocationID: locationID,
+		StockLevel: locationLevel,
+	}).Error; err != nil {
+		return nil, err
+	}
+
+	if err := tx.Model(&models.Product{}).Where("id = ?", product.ID).
+		Update("stock_level", level).Error; err != nil {
+		return nil, err
+	}
+
+	movement := &models.StockMovement{
+		ProductID:      product.ID,
+		LocationID:     &locationID,
+		Delta:          change.Delta,
+		ResultingLevel: level,
+		LocationLevel:  locationLevel,
+		Reason:         change.Reason,
+		ReferenceID:    change.ReferenceID,
+		Actor:          change.Actor,
+	}
+	if err := tx.Create(movement).Error; err != nil {
+		return nil, err
+	}
+	if err := recordCost(tx, change, movement); err != nil {
+		return nil, err
+	}
+	return movement, nil
+}
+
+// checkOutgoingStock makes sure a change takes no more units out than are
+// available, both for the product and at the location. Units held by open
+// reservations are not available, except to counts, which record what is on
+// the shelf; transfers may not move units held at the location but keep them
+// with the product. Only sales of products on backorder or preorder may go
+// below zero, and no further than their backorder limit.
+func checkOutgoingStock(product *models.Product, stock *models.LocationStock, change models.StockChange) error {
+	requested := -change.Delta
+	available := product.StockLevel - product.ReservedLevel
+	locationAvailable := stock.StockLevel - stock.ReservedLevel
+	switch change.Reason {
+	case models.StockReasonTransfer:
+		available = product.StockLevel
+	case models.StockReasonCount:
+		available = product.StockLevel
+		locationAvailable = stock.StockLevel
+	}
+	if requested <= available && requested <= locationAvailable {
+		return nil
+	}
+
+	if change.Reason == models.StockReasonSale && product.AvailabilityPolicy.SellsAheadOfStock() {
+		// Backordered units are owed to customers and show up as negative stock
+		if product.BackorderLimit == nil || requested <= available+*product.BackorderLimit {
+			return nil
+		}
+		return &models.InsufficientStockError{
+			ProductID: product.ID,
+			Requested: requested,
+			Available: available + *product.BackorderLimit,
+		}
+	}
+
+	if locationAvailable < available {
+		available = locationAvailable
+	}
+	return &models.InsufficientStockError{ProductID: product.ID, Requested: requested, Available: available}
+}
+
+// recordCost keeps a product's FIFO cost layers in step with a stock movement:
+// incoming units open a new layer and outgoing units consume the oldest ones.
+// Transfers only move stock between locations and leave the layers alone.
+func recordCost(tx *gorm.DB, change models.StockChange, movement *models.StockMovement) error {
+	if change.Reason == models.StockReasonTransfer {
+		return nil
+	}
+
+	if movement.Delta > 0 {
+		var unitCost models.Cost
+		if change.UnitCost != nil {
+			unitCost = *change.UnitCost
+		} else {
+			var last models.CostLayer
+			if err := tx.Select("unit_cost_amount").Where("product_id = ?", movement.ProductID).
+				Order("id DESC").Limit(1).Find(&last).Error; err != nil {
+				return err
+			}
+			unitCost = last.UnitCost
+		}
+
+		// Units sold ahead of stock are covered by the new layer first
+		owed := 0
+		if previous := movement.ResultingLevel - movement.Delta; previous < 0 {
+			owed = -previous
+			if owed > movement.Delta {
+				owed = movement.Delta
+			}
+		}
+
+		layer := models.CostLayer{
+			ProductID:  movement.ProductID,
+			MovementID: movement.ID,
+			Quantity:   movement.Delta,
+			Remaining:  movement.Delta - owed,
+			UnitCost:   unitCost,
+		}
+		if err := tx.Create(&layer).Error; err != nil {
+			return err
+		}
+		if owed == 0 {
+			return nil
+		}
+		return tx.Create(&models.CostConsumption{
+			LayerID:    layer.ID,
+			ProductID:  movement.ProductID,
+			MovementID: movement.ID,
+			Quantity:   owed,
+			UnitCost:   unitCost,
+		}).Error
+	}
+
+	// Stock without layers, such as units sold on backorder, has no cost to consume
+	needed := -movement.Delta
+	var layers []models.CostLayer
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("product_id = ? AND remaining > 0", movement.ProductID).
+		Order("id ASC").Find(&layers).Error; err != nil {
+		return err
+	}
+	for _, layer := range layers {
+		if needed == 0 {
+			break
+		}
+		quantity := layer.Remaining
+		if quantity > needed {
+			quantity = needed
+		}
+		if err := tx.Model(&layer).Update("remaining", layer.Remaining-quantity).Error; err != nil {
+			return err
+		}
+		if err := tx.Create(&models.CostConsumption{
+			LayerID:    layer.ID,
+			ProductID:  movement.ProductID,
+			MovementID: movement.ID,
+			Quantity:   quantity,
+			UnitCost:   layer.UnitCost,
+		}).Error; err != nil {
+			return err
+		}
+		needed -= quantity
+	}
+	return nil
+}
@@ -0,0 +1,135 @@
+// internal/repository/stock_movement_repository_test.go
+package repository
+
+import (
+	"errors"
+	"testing"
+
+	"phone-accessories/internal/models"
+)
+
+func TestCheckOutgoingStock(t *testing.T) {
+	limit := 5
+
+	tests := []struct {
+		name    string
+		product models.Product
+		stock   models.LocationStock
+		change  models.StockChange
+		// wantAvailable is the availability reported when the change is
+		// refused; -1 means the change goes through
+		wantAvailable int
+	}{
+		{
+			name:          "within available stock",
+			product:       models.Product{StockLevel: 10},
+			stock:         models.LocationStock{StockLevel: 10},
+			change:        models.StockChange{Delta: -10, Reason: models.StockReasonSale},
+			wantAvailable: -1,
+		},
+		{
+			name:          "more than in stock",
+			product:       models.Product{StockLevel: 10},
+			stock:         models.LocationStock{StockLevel: 10},
+			change:        models.StockChange{Delta: -11, Reason: models.StockReasonSale},
+			wantAvailable: 10,
+		},
+		{
+			name:          "units held by reservations are not available",
+			product:       models.Product{StockLevel: 10, ReservedLevel: 4},
+			stock:         models.LocationStock{StockLevel: 10},
+			change:        models.StockChange{Delta: -7, Reason: models.StockReasonAdjustment},
+			wantAvailable: 6,
+		},
+		{
+			name:          "transfers may move held units",
+			product:       models.Product{StockLevel: 10, ReservedLevel: 4},
+			stock:         models.LocationStock{StockLevel: 10},
+			change:        models.StockChange{Delta: -10, Reason: models.StockReasonTransfer},
+			wantAvailable: -1,
+		},
+		{
+			name:          "counts may take out held units",
+			product:       models.Product{StockLevel: 10, ReservedLevel: 4},
+			stock:         models.LocationStock{StockLevel: 10},
+			change:        models.StockChange{Delta: -10, Reason: models.StockReasonCount},
+			wantAvailable: -1,
+		},
+		{
+			name:          "units held at the location are not available there",
+			product:       models.Product{StockLevel: 10, ReservedLevel: 4},
+			stock:         models.LocationStock{StockLevel: 5, ReservedLevel: 4},
+			change:        models.StockChange{Delta: -2, Reason: models.StockReasonSale},
+			wantAvailable: 1,
+		},
+		{
+			name:          "transfers may not move units held at the location",
+			product:       models.Product{StockLevel: 10, ReservedLevel: 4},
+			stock:         models.LocationStock{StockLevel: 10, ReservedLevel: 4},
+			change:        models.StockChange{Delta: -8, Reason: models.StockReasonTransfer},
+			wantAvailable: 6,
+		},
+		{
+			name:          "counts may take out units held at the location",
+			product:       models.Product{StockLevel: 10, ReservedLevel: 4},
+			stock:         models.LocationStock{StockLevel: 10, ReservedLevel: 4},
+			change:        models.StockChange{Delta: -10, Reason: models.StockReasonCount},
+			wantAvailable: -1,
+		},
+		{
+			name:          "limited by the location's stock",
+			product:       models.Product{StockLevel: 10},
+			stock:         models.LocationStock{StockLevel: 3},
+			change:        models.StockChange{Delta: -4, Reason: models.StockReasonDamage},
+			wantAvailable: 3,
+		},
+		{
+			name: "backorder sales may go below zero",
+			product: models.Product{StockLevel: 2,
+				AvailabilityPolicy: models.AvailabilityPolicyBackorder},
+			stock:         models.LocationStock{StockLevel: 2},
+			change:        models.StockChange{Delta: -20, Reason: models.StockReasonSale},
+			wantAvailable: -1,
+		},
+		{
+			name: "backorder sales stop at the backorder limit",
+			product: models.Product{StockLevel: 2, AvailabilityPolicy: models.AvailabilityPolicyPreorder,
+				BackorderLimit: &limit},
+			stock:         models.LocationStock{StockLevel: 2},
+			change:        models.StockChange{Delta: -8, Reason: models.StockReasonSale},
+			wantAvailable: 7,
+		},
+		{
+			name: "only sales go below zero on backorder",
+			product: models.Product{StockLevel: 2,
+				AvailabilityPolicy: models.AvailabilityPolicyBackorder},
+			stock:         models.LocationStock{StockLevel: 2},
+			change:        models.StockChange{Delta: -3, Reason: models.StockReasonDamage},
+			wantAvailable: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkOutgoingStock(&tt.product, &tt.stock, tt.change)
+			if tt.wantAvailable < 0 {
+				if err != nil {
+					t.Fatalf("checkOutgoingStock() returned error: %v", err)
+				}
+				return
+			}
+
+			var stockErr *models.InsufficientStockError
+			if !errors.As(err, &stockErr) {
+				t.Fatalf("checkOutgoingStock() = %v, want an InsufficientStockError", err)
+			}
+			if !errors.Is(err, models.ErrInsufficientStock) {
+				t.Errorf("checkOutgoingStock() error does not match ErrInsufficientStock")
+			}
+			if stockErr.Requested != -tt.change.Delta || stockErr.Available != tt.wantAvailable {
+				t.Errorf("checkOutgoingStock() reported %d requested, %d available, want %d and %d",
+					stockErr.Requested, stockErr.Available, -tt.change.Delta, tt.wantAvailable)
+			}
+		})
+	}
+}
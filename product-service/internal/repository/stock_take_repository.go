@@ -0,0 +1,485 @@
+// internal/repository/stock_take_repository.go
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"phone-accessories/internal/models"
+)
+
+type StockTakeRepository interface {
+	Open(take *models.StockTake) error
+	GetByID(id uint) (*models.StockTake, error)
+	List(filter models.StockTakeFilter) (*models.PaginatedResponse, error)
+	RecordCounts(id uint, counts []models.StockTakeCount, actor string) (*models.StockTake, error)
+	Variance(id uint) (*models.StockTakeVarianceReport, error)
+	Commit(id uint, actor string, force bool) (*models.StockTakeVarianceReport, []models.StockMovement, error)
+	Cancel(id uint) (*models.StockTake, error)
+}
+
+type stockTakeRepository struct {
+	db *gorm.DB
+}
+
+func NewStockTakeRepository(db *gorm.DB) StockTakeRepository {
+	return &stockTakeRepository{db: db}
+}
+
+// Open creates a stock take and snapshots the stock level of every product it
+// covers: the products of the category when one is given, otherwise every
+// product stocked at the location
+func (r *stockTakeRepository) Open(take *models.StockTake) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		locationID, err := resolveLocationID(tx, take.LocationID)
+		if err != nil {
+			return err
+		}
+		take.LocationID = locationID
+		take.Status = models.StockTakeStatusOpen
+		take.CommittedAt = nil
+
+		var productIDs []uint
+		query := tx.Table("products p").
+			Joins("LEFT JOIN location_stocks ls ON ls.product_id = p.id AND ls.location_id = ?", locationID).
+			Where("p.deleted_at IS NULL").
+			// Parents are counted through their variants and bundles through their components
+			Where("NOT EXISTS (SELECT 1 FROM products v WHERE v.parent_id = p.id AND v.deleted_at IS NULL)").
+			Where("NOT p.is_bundle")
+		if take.CategoryID != nil {
+			query = query.Where("p.category_id = ?", *take.CategoryID)
+		} else {
+			query = query.Where("ls.product_id IS NOT NULL")
+		}
+		if err := query.Order("p.id ASC").Pluck("p.id", &productIDs).Error; err != nil {
+			return err
+		}
+
+		// Stock changes lock the product before writing to the ledger, so once
+		// the covered products are locked no movement of theirs is still in
+		// flight and the last ledger entry is a safe cut-off
+		if err := lockProductsShared(tx, productIDs); err != nil {
+			return err
+		}
+		if err := tx.Model(&models.StockMovement{}).Select("COALESCE(MAX(id), 0)").
+			Scan(&take.SnapshotMovementID).Error; err != nil {
+			return err
+		}
+
+		take.Lines = make([]models.StockTakeLine, 0, len(productIDs))
+		if len(productIDs) > 0 {
+			var products []models.Product
+			if err := tx.Select("id", "sku").Order("id ASC").Find(&products, productIDs).Error; err != nil {
+				return err
+			}
+			levels, err := locationLevels(tx, locationID, productIDs)
+			if err != nil {
+				return err
+			}
+			for _, product := range products {
+				take.Lines = append(take.Lines, models.StockTakeLine{
+					ProductID:     product.ID,
+					SKU:           product.SKU,
+					ExpectedLevel: levels[product.ID],
+				})
+			}
+		}
+		return tx.Create(take).Error
+	})
+}
+
+func (r *stockTakeRepository) GetByID(id uint) (*models.StockTake, error) {
+	var take models.StockTake
+	if err := r.db.Preload("Lines", func(db *gorm.DB) *gorm.DB {
+		return db.Order("id ASC")
+	}).First(&take, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, models.ErrStockTakeNotFound
+		}
+		return nil, err
+	}
+	return &take, nil
+}
+
+func (r *stockTakeRepository) List(filter models.StockTakeFilter) (*models.PaginatedResponse, error) {
+	var takes []models.StockTake
+	var totalItems int64
+
+	query := r.db.Model(&models.StockTake{})
+
+	if filter.LocationID != nil {
+		query = query.Where("location_id = ?", *filter.LocationID)
+	}
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+
+	if err := query.Count(&totalItems).Error; err != nil {
+		return nil, err
+	}
+
+	if filter.Page < 1 {
+		filter.Page = 1
+	}
+	if filter.PageSize < 1 {
+		filter.PageSize = 20
+	}
+	offset := (filter.Page - 1) * filter.PageSize
+
+	// Lines are left out of the listing; a session can cover the whole catalogue
+	if err := query.Order("id DESC").Offset(offset).Limit(filter.PageSize).Find(&takes).Error; err != nil {
+		return nil, err
+	}
+
+	totalPages := int(math.Ceil(float64(totalItems) / float64(filter.PageSize)))
+	return &models.PaginatedResponse{
+		Items:      takes,
+		Page:       filter.Page,
+		PageSize:   filter.PageSize,
+		TotalItems: totalItems,
+		TotalPages: totalPages,
+	}, nil
+}
+
+// RecordCounts stores counted quantities against a stock take. Counting a
+// product again replaces its earlier count. A product that is in scope but
+// was not part of the snapshot, such as stock found at a location with no
+// recorded level, is added to the session.
+func (r *stockTakeRepository) RecordCounts(id uint, counts []models.StockTakeCount, actor string) (*models.StockTake, error) {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		take, err := lockOpenStockTake(tx, id)
+		if err != nil {
+			return err
+		}
+
+		now := time.Now().UTC()
+		for _, count := range counts {
+			var product models.Product
+			query := tx.Select("id", "sku", "category_id", "is_bundle")
+			if count.ProductID != 0 {
+				query = query.Where("id = ?", count.ProductID)
+			} else {
+				query = query.Where("sku = ?", count.SKU)
+			}
+			if err := query.Limit(1).Find(&product).Error; err != nil {
+				return err
+			}
+			if product.ID == 0 {
+				if count.ProductID != 0 {
+					return fmt.Errorf("%w: %d", models.ErrProductNotFound, count.ProductID)
+				}
+				return fmt.Errorf("%w: unknown SKU %q", models.ErrProductNotFound, count.SKU)
+			}
+			var variants int64
+			if err := tx.Model(&models.Product{}).Where("parent_id = ?", product.ID).Count(&variants).Error; err != nil {
+				return err
+			}
+			if variants > 0 {
+				return fmt.Errorf("%w: count the variants of product %d instead", models.ErrProductHasVariants, product.ID)
+			}
+			if product.IsBundle {
+				return fmt.Errorf("%w: count the components of bundle %d instead", models.ErrProductIsBundle, product.ID)
+			}
+			if take.CategoryID != nil && product.CategoryID != *take.CategoryID {
+				return fmt.Errorf("product %d is not in the category covered by stock take %d", product.ID, take.ID)
+			}
+
+			// Lock the product so the count is placed exactly in its ledger
+			if err := lockProductsShared(tx, []uint{product.ID}); err != nil {
+				return err
+			}
+			var countedMovementID uint
+			if err := tx.Model(&models.StockMovement{}).Select("COALESCE(MAX(id), 0)").
+				Where("product_id = ?", product.ID).Scan(&countedMovementID).Error; err != nil {
+				return err
+			}
+
+			quantity := count.Quantity
+			var line models.StockTakeLine
+			if err := tx.Where("stock_take_id = ? AND product_id = ?", take.ID, product.ID).
+				Limit(1).Find(&line).Error; err != nil {
+				return err
+			}
+			if line.ID == 0 {
+				levels, err := locationLevels(tx, take.LocationID, []uint{product.ID})
+				if err != nil {
+					return err
+				}
+				// The expected level is the one at the snapshot, like the
+				// other lines, so the movements since are taken back off
+				moved, err := movementsSinceSnapshot(tx, take, []uint{product.ID})
+				if err != nil {
+					return err
+				}
+				expected := levels[product.ID]
+				for _, movement := range moved {
+					expected -= movement.Delta
+				}
+				line = models.StockTakeLine{
+					StockTakeID:   take.ID,
+					ProductID:     product.ID,
+					SKU:           product.SKU,
+					ExpectedLevel: expected,
+				}
+			}
+			line.CountedQuantity = &quantity
+			line.CountedMovementID = countedMovementID
+			line.CountedAt = &now
+			line.CountedBy = actor
+			if err := tx.Save(&line).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Model(take).Update("updated_at", now).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return r.GetByID(id)
+}
+
+func (r *stockTakeRepository) Variance(id uint) (*models.StockTakeVarianceReport, error) {
+	take, err := r.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	return stockTakeVariance(r.db, take)
+}
+
+// Commit applies the variance of each counted line as a "count" movement and
+// closes the stock take. Uncounted lines are left untouched. Counted products
+// whose stock moved after the session opened fail the commit with
+// ErrStockTakeChanged unless force is set. The returned report is the
+// variance that was applied.
+func (r *stockTakeRepository) Commit(id uint, actor string, force bool) (*models.StockTakeVarianceReport, []models.StockMovement, error) {
+	var report *models.StockTakeVarianceReport
+	var movements []models.StockMovement
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		take, err := lockOpenStockTake(tx, id)
+		if err != nil {
+			return err
+		}
+		if err := tx.Where("stock_take_id = ?", take.ID).Order("id ASC").Find(&take.Lines).Error; err != nil {
+			return err
+		}
+
+		// Lock the counted products up front so the variance cannot go stale
+		// between computing and applying it
+		var productIDs []uint
+		for _, line := range take.Lines {
+			if line.CountedQuantity != nil {
+				productIDs = append(productIDs, line.ProductID)
+			}
+		}
+		if len(productIDs) > 0 {
+			if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").
+				Order("id ASC").Find(&[]models.Product{}, productIDs).Error; err != nil {
+				return err
+			}
+		}
+
+		report, err = stockTakeVariance(tx, take)
+		if err != nil {
+			return err
+		}
+		if !force {
+			var changed []string
+			for _, line := range report.Lines {
+				if line.Variance != nil && line.ChangedSinceOpen {
+					changed = append(changed, line.SKU)
+				}
+			}
+			if len(changed) > 0 {
+				return fmt.Errorf("%w: recount %s or force the commit",
+					models.ErrStockTakeChanged, strings.Join(changed, ", "))
+			}
+		}
+
+		reference := fmt.Sprintf("stocktake:%d", take.ID)
+		for _, line := range report.Lines {
+			if line.Variance == nil || *line.Variance == 0 {
+				continue
+			}
+			movement, err := applyStockChange(tx, models.StockChange{
+				ProductID:   line.ProductID,
+				LocationID:  take.LocationID,
+				Delta:       *line.Variance,
+				Reason:      models.StockReasonCount,
+				ReferenceID: reference,
+				Actor:       actor,
+			})
+			if err != nil {
+				return err
+			}
+			movements = append(movements, *movement)
+		}
+
+		now := time.Now().UTC()
+		report.Status = models.StockTakeStatusCommitted
+		return tx.Model(take).Updates(map[string]interface{}{
+			"status":       models.StockTakeStatusCommitted,
+			"committed_at": now,
+		}).Error
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return report, movements, nil
+}
+
+func (r *stockTakeRepository) Cancel(id uint) (*models.StockTake, error) {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		take, err := lockOpenStockTake(tx, id)
+		if err != nil {
+			return err
+		}
+		return tx.Model(take).Update("status", models.StockTakeStatusCancelled).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return r.GetByID(id)
+}
+
+// stockTakeVariance compares each counted quantity with the stock level the
+// product had when it was counted, which is its snapshot level plus the
+// movements between the snapshot and the count, and flags products whose
+// stock moved since the session opened
+func stockTakeVariance(tx *gorm.DB, take *models.StockTake) (*models.StockTakeVarianceReport, error) {
+	report := &models.StockTakeVarianceReport{
+		StockTakeID: take.ID,
+		Status:      take.Status,
+		LocationID:  take.LocationID,
+		Lines:       make([]models.StockTakeVarianceLine, 0, len(take.Lines)),
+	}
+	if len(take.Lines) == 0 {
+		return report, nil
+	}
+
+	productIDs := make([]uint, 0, len(take.Lines))
+	for _, line := range take.Lines {
+		productIDs = append(productIDs, line.ProductID)
+	}
+
+	levels, err := locationLevels(tx, take.LocationID, productIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	var products []models.Product
+	if err := tx.Unscoped().Select("id", "name").Find(&products, productIDs).Error; err != nil {
+		return nil, err
+	}
+	names := make(map[uint]string, len(products))
+	for _, product := range products {
+		names[product.ID] = product.Name
+	}
+
+	moved, err := movementsSinceSnapshot(tx, take, productIDs)
+	if err != nil {
+		return nil, err
+	}
+	movements := make(map[uint][]models.StockMovement, len(moved))
+	for _, movement := range moved {
+		movements[movement.ProductID] = append(movements[movement.ProductID], movement)
+	}
+
+	for _, line := range take.Lines {
+		varianceLine := models.StockTakeVarianceLine{
+			ProductID:        line.ProductID,
+			SKU:              line.SKU,
+			Name:             names[line.ProductID],
+			ExpectedLevel:    line.ExpectedLevel,
+			CurrentLevel:     levels[line.ProductID],
+			CountedQuantity:  line.CountedQuantity,
+			ChangedSinceOpen: len(movements[line.ProductID]) > 0,
+		}
+		if line.CountedQuantity != nil {
+			countedLevel := line.ExpectedLevel
+			for _, movement := range movements[line.ProductID] {
+				if movement.ID <= line.CountedMovementID {
+					countedLevel += movement.Delta
+				}
+			}
+			varianceLine.CountedLevel = &countedLevel
+
+			// A negative level is backordered units owed to customers, not
+			// stock on the shelf, so the count is compared with what was held
+			onHand := countedLevel
+			if onHand < 0 {
+				onHand = 0
+			}
+			variance := *line.CountedQuantity - onHand
+			varianceLine.Variance = &variance
+			report.CountedLines++
+		} else {
+			report.UncountedLines++
+		}
+		if varianceLine.ChangedSinceOpen {
+			report.ChangedLines++
+		}
+		report.Lines = append(report.Lines, varianceLine)
+	}
+	return report, nil
+}
+
+// movementsSinceSnapshot returns the movements of products at the stock
+// take's location after it was opened, leaving out the ones the stock take
+// booked itself
+func movementsSinceSnapshot(tx *gorm.DB, take *models.StockTake, productIDs []uint) ([]models.StockMovement, error) {
+	var movements []models.StockMovement
+	if err := tx.Select("id", "product_id", "delta").
+		Where("id > ? AND location_id = ? AND product_id IN ?", take.SnapshotMovementID, take.LocationID, productIDs).
+		Where("reference_id <> ?", fmt.Sprintf("stocktake:%d", take.ID)).
+		Order("id ASC").Find(&movements).Error; err != nil {
+		return nil, err
+	}
+	return movements, nil
+}
+
+// lockProductsShared takes a share lock on products, which waits for stock
+// changes in flight on them and holds off new ones until the transaction ends
+func lockProductsShared(tx *gorm.DB, productIDs []uint) error {
+	if len(productIDs) == 0 {
+		return nil
+	}
+	return tx.Clauses(clause.Locking{Strength: "SHARE"}).Select("id").
+		Order("id ASC").Find(&[]models.Product{}, productIDs).Error
+}
+
+// locationLevels returns the stock level of each product at a location;
+// products without stock there are missing from the map
+func locationLevels(tx *gorm.DB, locationID uint, productIDs []uint) (map[uint]int, error) {
+	var stocks []models.LocationStock
+	if err := tx.Where("location_id = ? AND product_id IN ?", locationID, productIDs).
+		Find(&stocks).Error; err != nil {
+		return nil, err
+	}
+	levels := make(map[uint]int, len(stocks))
+	for _, stock := range stocks {
+		levels[stock.ProductID] = stock.StockLevel
+	}
+	return levels, nil
+}
+
+// lockOpenStockTake loads a stock take, locking the row for the rest of the
+// transaction, and checks that it is still open
+func lockOpenStockTake(tx *gorm.DB, id uint) (*models.StockTake, error) {
+	var take models.StockTake
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&take, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, models.ErrStockTakeNotFound
+		}
+		return nil, err
+	}
+	if take.Status != models.StockTakeStatusOpen {
+		return nil, fmt.Errorf("%w: stock take %d is %s", models.ErrStockTakeClosed, take.ID, take.Status)
+	}
+	return &take, nil
+}
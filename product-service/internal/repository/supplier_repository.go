@@ -0,0 +1,70 @@
+// internal/repository/supplier_repository.go
+package repository
+
+import (
+	"errors"
+
+	"gorm.io/gorm"
+
+	"phone-accessories/internal/models"
+)
+
+type SupplierRepository interface {
+	Create(supplier *models.Supplier) error
+	GetByID(id uint) (*models.Supplier, error)
+	Update(supplier *models.Supplier) error
+	Delete(id uint) error
+	List() ([]models.Supplier, error)
+}
+
+type supplierRepository struct {
+	db *gorm.DB
+}
+
+func NewSupplierRepository(db *gorm.DB) SupplierRepository {
+	return &supplierRepository{db: db}
+}
+
+func (r *supplierRepository) Create(supplier *models.Supplier) error {
+	return r.db.Create(supplier).Error
+}
+
+func (r *supplierRepository) GetByID(id uint) (*models.Supplier, error) {
+	var supplier models.Supplier
+	if err := r.db.First(&supplier, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, models.ErrSupplierNotFound
+		}
+		return nil, err
+	}
+	return &supplier, nil
+}
+
+func (r *supplierRepository) Update(supplier *models.Supplier) error {
+	if _, err := r.GetByID(supplier.ID); err != nil {
+		return err
+	}
+	return r.db.Model(supplier).Select("*").Omit("id", "created_at", "deleted_at").Updates(supplier).Error
+}
+
+func (r *supplierRepository) Delete(id uint) error {
+	// Check if there are purchase orders placed with this supplier
+	var count int64
+	if err := r.db.Model(&models.PurchaseOrder{}).Where("supplier_id = ?", id).Count(&count).Error; err != nil {
+		return err
+	}
+
+	if count > 0 {
+		return errors.New("cannot delete supplier with purchase orders; deactivate it instead")
+	}
+
+	return r.db.Delete(&models.Supplier{}, id).Error
+}
+
+func (r *supplierRepository) List() ([]models.Supplier, error) {
+	var suppliers []models.Supplier
+	if err := r.db.Order("name ASC").Find(&suppliers).Error; err != nil {
+		return nil, err
+	}
+	return suppliers, nil
+}
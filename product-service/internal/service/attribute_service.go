@@ -0,0 +1,221 @@
+// internal/service/attribute_service.go
+package service
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"phone-accessories/internal/models"
+	"phone-accessories/internal/repository"
+)
+
+type AttributeService interface {
+	CreateAttribute(definition *models.AttributeDefinition) error
+	UpdateAttribute(definition *models.AttributeDefinition) error
+	DeleteAttribute(categoryID, id uint) error
+	GetSchema(categoryID uint) ([]models.AttributeDefinition, error)
+}
+
+type attributeService struct {
+	repo         repository.AttributeRepository
+	categoryRepo repository.CategoryRepository
+}
+
+func NewAttributeService(repo repository.AttributeRepository, categoryRepo repository.CategoryRepository) AttributeService {
+	return &attributeService{repo: repo, categoryRepo: categoryRepo}
+}
+
+func (s *attributeService) CreateAttribute(definition *models.AttributeDefinition) error {
+	if _, err := s.categoryRepo.GetByID(definition.CategoryID); err != nil {
+		return err
+	}
+	if err := validateDefinition(definition); err != nil {
+		return err
+	}
+
+	return s.repo.Create(definition)
+}
+
+func (s *attributeService) UpdateAttribute(definition *models.AttributeDefinition) error {
+	current, err := s.repo.GetByID(definition.ID)
+	if err != nil {
+		return err
+	}
+	if current.CategoryID != definition.CategoryID {
+		return models.ErrAttributeNotFound
+	}
+	if err := validateDefinition(definition); err != nil {
+		return err
+	}
+
+	return s.repo.Update(definition)
+}
+
+func (s *attributeService) DeleteAttribute(categoryID, id uint) error {
+	current, err := s.repo.GetByID(id)
+	if err != nil {
+		return err
+	}
+	if current.CategoryID != categoryID {
+		return models.ErrAttributeNotFound
+	}
+
+	return s.repo.Delete(id)
+}
+
+// GetSchema returns the attribute definitions that apply to products of the
+// category, including those inherited from its parent categories
+func (s *attributeService) GetSchema(categoryID uint) ([]models.AttributeDefinition, error) {
+	if _, err := s.categoryRepo.GetByID(categoryID); err != nil {
+		return nil, err
+	}
+
+	return s.repo.EffectiveSchema(categoryID)
+}
+
+func validateDefinition(definition *models.AttributeDefinition) error {
+	if !models.AttributeKeyPattern.MatchString(definition.Key) {
+		return errors.New("attribute key must start with a lowercase letter and contain only lowercase letters, digits and underscores")
+	}
+	if definition.Label == "" {
+		definition.Label = definition.Key
+	}
+	if !definition.Type.IsValid() {
+		return fmt.Errorf("invalid attribute type %q", definition.Type)
+	}
+
+	switch definition.Type {
+	case models.AttributeTypeEnum:
+		if len(definition.AllowedValues) == 0 {
+			return errors.New("enum attributes require allowed values")
+		}
+	case models.AttributeTypeList:
+	default:
+		if len(definition.AllowedValues) > 0 {
+			return errors.New("only enum and list attributes have allowed values")
+		}
+	}
+	seen := make(map[string]bool, len(definition.AllowedValues))
+	for _, value := range definition.AllowedValues {
+		if strings.TrimSpace(value) == "" {
+			return errors.New("allowed values must not be empty")
+		}
+		if seen[value] {
+			return fmt.Errorf("allowed value %q is listed twice", value)
+		}
+		seen[value] = true
+	}
+	return nil
+}
+
+// validateAttributes checks product attributes against a category's attribute
+// schema and returns an AttributeValidationError listing every invalid field.
+// Only defined keys are checked; other keys, such as the free-form ones that
+// products carried before their category had a schema, are kept as they are.
+func validateAttributes(schema []models.AttributeDefinition, attributes models.JSON) error {
+	if len(schema) == 0 {
+		return nil
+	}
+
+	var fields []models.AttributeFieldError
+	invalid := func(key, format string, args ...interface{}) {
+		fields = append(fields, models.AttributeFieldError{
+			Field: "attributes." + key,
+			Error: fmt.Sprintf(format, args...),
+		})
+	}
+
+	for _, definition := range schema {
+		value, ok := attributes[definition.Key]
+		if !ok || value == nil || value == "" {
+			if definition.Required {
+				invalid(definition.Key, "is required")
+			}
+			continue
+		}
+
+		switch definition.Type {
+		case models.AttributeTypeString:
+			if _, ok := value.(string); !ok {
+				invalid(definition.Key, "must be a string")
+			}
+		case models.AttributeTypeNumber:
+			if !isNumber(value) {
+				invalid(definition.Key, "must be a number")
+			}
+		case models.AttributeTypeBool:
+			if _, ok := value.(bool); !ok {
+				invalid(definition.Key, "must be true or false")
+			}
+		case models.AttributeTypeEnum:
+			text, ok := value.(string)
+			if !ok || !containsString(definition.AllowedValues, text) {
+				invalid(definition.Key, "must be one of %s", strings.Join(definition.AllowedValues, ", "))
+			}
+		case models.AttributeTypeList:
+			values, ok := stringList(value)
+			if !ok {
+				invalid(definition.Key, "must be a list of strings")
+				continue
+			}
+			if definition.Required && len(values) == 0 {
+				invalid(definition.Key, "is required")
+			}
+			if len(definition.AllowedValues) == 0 {
+				continue
+			}
+			for _, text := range values {
+				if !containsString(definition.AllowedValues, text) {
+					invalid(definition.Key, "%q is not one of %s", text, strings.Join(definition.AllowedValues, ", "))
+				}
+			}
+		}
+	}
+
+	if len(fields) > 0 {
+		// Map iteration order is random, so sort for stable responses
+		sort.SliceStable(fields, func(i, j int) bool {
+			return fields[i].Field < fields[j].Field
+		})
+		return &models.AttributeValidationError{Fields: fields}
+	}
+	return nil
+}
+
+func isNumber(value interface{}) bool {
+	switch value.(type) {
+	case float64, float32, int, int32, int64, uint, uint32, uint64:
+		return true
+	}
+	return false
+}
+
+// stringList accepts a list as decoded from JSON or as built in Go code
+func stringList(value interface{}) ([]string, bool) {
+	switch list := value.(type) {
+	case []string:
+		return list, true
+	case []interface{}:
+		values := make([]string, 0, len(list))
+		for _, item := range list {
+			text, ok := item.(string)
+			if !ok {
+				return nil, false
+			}
+			values = append(values, text)
+		}
+		return values, true
+	}
+	return nil, false
+}
+
+func containsString(values []string, value string) bool {
+	for _, candidate := range values {
+		if candidate == value {
+			return true
+		}
+	}
+	return false
+}
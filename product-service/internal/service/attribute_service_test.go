@@ -0,0 +1,111 @@
+// internal/service/attribute_service_test.go
+package service
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"phone-accessories/internal/models"
+)
+
+func TestValidateAttributes(t *testing.T) {
+	schema := []models.AttributeDefinition{
+		{Key: "color", Type: models.AttributeTypeEnum, Required: true, AllowedValues: models.StringList{"black", "white"}},
+		{Key: "length_m", Type: models.AttributeTypeNumber},
+		{Key: "braided", Type: models.AttributeTypeBool},
+		{Key: "material", Type: models.AttributeTypeString},
+		{Key: "ports", Type: models.AttributeTypeList, AllowedValues: models.StringList{"usb-a", "usb-c"}},
+	}
+
+	tests := []struct {
+		name       string
+		schema     []models.AttributeDefinition
+		attributes models.JSON
+		// wantFields lists the invalid fields, in the order they are reported
+		wantFields []string
+	}{
+		{
+			name:       "no schema accepts anything",
+			attributes: models.JSON{"anything": 1},
+		},
+		{
+			name:   "valid attributes",
+			schema: schema,
+			attributes: models.JSON{
+				"color": "black", "length_m": 1.5, "braided": true, "material": "nylon",
+				"ports": []interface{}{"usb-a", "usb-c"},
+			},
+		},
+		{
+			name:       "keys outside the schema are kept",
+			schema:     schema,
+			attributes: models.JSON{"color": "white", "legacy_note": 12},
+		},
+		{
+			name:       "missing required attribute",
+			schema:     schema,
+			attributes: models.JSON{"length_m": 1},
+			wantFields: []string{"attributes.color"},
+		},
+		{
+			name:       "empty required attribute",
+			schema:     schema,
+			attributes: models.JSON{"color": ""},
+			wantFields: []string{"attributes.color"},
+		},
+		{
+			name:   "wrong types",
+			schema: schema,
+			attributes: models.JSON{
+				"color": "red", "length_m": "1.5", "braided": "yes", "material": 3,
+				"ports": []interface{}{"usb-a", "lightning"},
+			},
+			wantFields: []string{
+				"attributes.braided", "attributes.color", "attributes.length_m",
+				"attributes.material", "attributes.ports",
+			},
+		},
+		{
+			name:       "list that is not a list of strings",
+			schema:     schema,
+			attributes: models.JSON{"color": "black", "ports": []interface{}{1}},
+			wantFields: []string{"attributes.ports"},
+		},
+		{
+			name: "required list must not be empty",
+			schema: []models.AttributeDefinition{
+				{Key: "ports", Type: models.AttributeTypeList, Required: true},
+			},
+			attributes: models.JSON{"ports": []interface{}{}},
+			wantFields: []string{"attributes.ports"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateAttributes(tt.schema, tt.attributes)
+			if len(tt.wantFields) == 0 {
+				if err != nil {
+					t.Fatalf("validateAttributes() returned error: %v", err)
+				}
+				return
+			}
+
+			var validationErr *models.AttributeValidationError
+			if !errors.As(err, &validationErr) {
+				t.Fatalf("validateAttributes() = %v, want an AttributeValidationError", err)
+			}
+			if !errors.Is(err, models.ErrInvalidAttributes) {
+				t.Errorf("validateAttributes() error does not match ErrInvalidAttributes")
+			}
+			var fields []string
+			for _, field := range validationErr.Fields {
+				fields = append(fields, field.Field)
+			}
+			if !reflect.DeepEqual(fields, tt.wantFields) {
+				t.Errorf("invalid fields = %v, want %v", fields, tt.wantFields)
+			}
+		})
+	}
+}
@@ -0,0 +1,56 @@
+// internal/service/brand_service.go
+package service
+
+import (
+	"errors"
+	"strings"
+
+	"phone-accessories/internal/models"
+	"phone-accessories/internal/repository"
+)
+
+type BrandService interface {
+	CreateBrand(brand *models.Brand) error
+	GetBrandByID(id uint) (*models.Brand, error)
+	UpdateBrand(brand *models.Brand) error
+	DeleteBrand(id uint) error
+	ListBrands(filter models.BrandFilter) ([]models.Brand, error)
+}
+
+type brandService struct {
+	repo repository.BrandRepository
+}
+
+func NewBrandService(repo repository.BrandRepository) BrandService {
+	return &brandService{repo: repo}
+}
+
+func (s *brandService) CreateBrand(brand *models.Brand) error {
+	brand.Name = strings.TrimSpace(brand.Name)
+	if brand.Name == "" {
+		return errors.New("brand name is required")
+	}
+
+	return s.repo.Create(brand)
+}
+
+func (s *brandService) GetBrandByID(id uint) (*models.Brand, error) {
+	return s.repo.GetByID(id)
+}
+
+func (s *brandService) UpdateBrand(brand *models.Brand) error {
+	brand.Name = strings.TrimSpace(brand.Name)
+	if brand.Name == "" {
+		return errors.New("brand name is required")
+	}
+
+	return s.repo.Update(brand)
+}
+
+func (s *brandService) DeleteBrand(id uint) error {
+	return s.repo.Delete(id)
+}
+
+func (s *brandService) ListBrands(filter models.BrandFilter) ([]models.Brand, error) {
+	return s.repo.List(filter)
+}
@@ -3,6 +3,7 @@ package service
 
 import (
 	"errors"
+	"sort"
 
 	"phone-accessories/internal/models"
 	"phone-accessories/internal/repository"
@@ -11,9 +12,12 @@ import (
 type CategoryService interface {
 	CreateCategory(category *models.Category) error
 	GetCategoryByID(id uint) (*models.Category, error)
+	GetCategoryBySlug(slug string) (*models.Category, error)
 	UpdateCategory(category *models.Category) error
 	DeleteCategory(id uint) error
 	ListCategories() ([]models.Category, error)
+	GetCategoryTree() ([]*models.CategoryNode, error)
+	GetBreadcrumbs(id uint) ([]models.Category, error)
 }
 
 type categoryService struct {
@@ -28,6 +32,12 @@ func (s *categoryService) CreateCategory(category *models.Category) error {
 	if category.Name == "" {
 		return errors.New("category name is required")
 	}
+	if err := s.checkParent(category); err != nil {
+		return err
+	}
+	if category.TaxRate != nil && (*category.TaxRate < 0 || *category.TaxRate >= 100) {
+		return errors.New("category tax rate must be at least 0 and below 100")
+	}
 	
 	return s.repo.Create(category)
 }
@@ -36,10 +46,19 @@ func (s *categoryService) GetCategoryByID(id uint) (*models.Category, error) {
 	return s.repo.GetByID(id)
 }
 
+// GetCategoryBySlug returns the category with the slug, or the category that
+// used to have it before being renamed
+func (s *categoryService) GetCategoryBySlug(slug string) (*models.Category, error) {
+	return s.repo.GetBySlug(slug)
+}
+
 func (s *categoryService) UpdateCategory(category *models.Category) error {
 	if category.Name == "" {
 		return errors.New("category name is required")
 	}
+	if category.TaxRate != nil && (*category.TaxRate < 0 || *category.TaxRate >= 100) {
+		return errors.New("category tax rate must be at least 0 and below 100")
+	}
 	
 	return s.repo.Update(category)
 }
@@ -51,3 +70,108 @@ func (s *categoryService) DeleteCategory(id uint) error {
 func (s *categoryService) ListCategories() ([]models.Category, error) {
 	return s.repo.List()
 }
+
+// GetCategoryTree returns the category hierarchy, with root categories and
+// the children of each category sorted by name
+func (s *categoryService) GetCategoryTree() ([]*models.CategoryNode, error) {
+	categories, err := s.repo.List()
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(categories, func(i, j int) bool {
+		return categories[i].Name < categories[j].Name
+	})
+
+	nodes := make(map[uint]*models.CategoryNode, len(categories))
+	for _, category := range categories {
+		category.Parent = nil
+		nodes[category.ID] = &models.CategoryNode{Category: category, Children: []*models.CategoryNode{}}
+	}
+
+	roots := []*models.CategoryNode{}
+	for _, category := range categories {
+		node := nodes[category.ID]
+		// A category whose parent has been deleted is shown at the top level
+		var parent *models.CategoryNode
+		if category.ParentID != nil {
+			parent = nodes[*category.ParentID]
+		}
+		if parent != nil {
+			parent.Children = append(parent.Children, node)
+		} else {
+			roots = append(roots, node)
+		}
+	}
+
+	// Categories in a loop of parents, and the ones below them, cannot be
+	// reached from a root. Each loop is broken at one of its categories,
+	// which is shown at the top level, so that no category goes missing.
+	reached := make(map[uint]bool, len(categories))
+	for _, root := range roots {
+		markCategoryNodes(root, reached)
+	}
+	for _, category := range categories {
+		if reached[category.ID] {
+			continue
+		}
+		// Walking up from an unreached category ends up going round its loop
+		seen := make(map[uint]bool)
+		id := category.ID
+		for !seen[id] {
+			seen[id] = true
+			id = *nodes[id].ParentID
+		}
+		node := nodes[id]
+		parent := nodes[*node.ParentID]
+		for i, child := range parent.Children {
+			if child == node {
+				parent.Children = append(parent.Children[:i], parent.Children[i+1:]...)
+				break
+			}
+		}
+		roots = append(roots, node)
+		markCategoryNodes(node, reached)
+	}
+	sort.SliceStable(roots, func(i, j int) bool {
+		return roots[i].Name < roots[j].Name
+	})
+	return roots, nil
+}
+
+// markCategoryNodes records a node and every node below it as reached
+func markCategoryNodes(node *models.CategoryNode, reached map[uint]bool) {
+	reached[node.ID] = true
+	for _, child := range node.Children {
+		markCategoryNodes(child, reached)
+	}
+}
+
+// GetBreadcrumbs returns the categories from the root down to the given one
+func (s *categoryService) GetBreadcrumbs(id uint) ([]models.Category, error) {
+	breadcrumbs, err := s.repo.Ancestors(id)
+	if err != nil {
+		return nil, err
+	}
+	if len(breadcrumbs) == 0 {
+		return nil, models.ErrCategoryNotFound
+	}
+	return breadcrumbs, nil
+}
+
+// checkParent makes sure a new category's parent exists. Moving an existing
+// category is checked for cycles by the repository, under the same lock as
+// the update.
+func (s *categoryService) checkParent(category *models.Category) error {
+	if category.ParentID == nil {
+		return nil
+	}
+
+	ancestors, err := s.repo.Ancestors(*category.ParentID)
+	if err != nil {
+		return err
+	}
+	if len(ancestors) == 0 {
+		return errors.New("parent category does not exist")
+	}
+	return nil
+}
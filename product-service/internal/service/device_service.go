@@ -0,0 +1,206 @@
+// internal/service/device_service.go
+package service
+
+import (
+	"errors"
+	"log"
+	"strings"
+
+	"phone-accessories/internal/models"
+	"phone-accessories/internal/repository"
+)
+
+type DeviceService interface {
+	CreateDevice(device *models.DeviceModel) error
+	GetDeviceByID(id uint) (*models.DeviceModel, error)
+	UpdateDevice(device *models.DeviceModel) error
+	DeleteDevice(id uint) error
+	ListDevices(filter models.DeviceFilter) (*models.PaginatedResponse, error)
+	ListDeviceProducts(id uint, filter models.ProductFilter) (*models.PaginatedResponse, error)
+	BackfillCompatibility() (int, error)
+}
+
+type deviceService struct {
+	repo          repository.DeviceRepository
+	brandRepo     repository.BrandRepository
+	productRepo   repository.ProductRepository
+	priceListRepo repository.PriceListRepository
+}
+
+func NewDeviceService(repo repository.DeviceRepository, brandRepo repository.BrandRepository,
+	productRepo repository.ProductRepository, priceListRepo repository.PriceListRepository) DeviceService {
+	return &deviceService{repo: repo, brandRepo: brandRepo, productRepo: productRepo, priceListRepo: priceListRepo}
+}
+
+func (s *deviceService) CreateDevice(device *models.DeviceModel) error {
+	if err := s.validateDevice(device); err != nil {
+		return err
+	}
+
+	return s.repo.Create(device)
+}
+
+func (s *deviceService) GetDeviceByID(id uint) (*models.DeviceModel, error) {
+	return s.repo.GetByID(id)
+}
+
+func (s *deviceService) UpdateDevice(device *models.DeviceModel) error {
+	if err := s.validateDevice(device); err != nil {
+		return err
+	}
+
+	return s.repo.Update(device)
+}
+
+func (s *deviceService) DeleteDevice(id uint) error {
+	return s.repo.Delete(id)
+}
+
+func (s *deviceService) ListDevices(filter models.DeviceFilter) (*models.PaginatedResponse, error) {
+	return s.repo.List(filter)
+}
+
+// ListDeviceProducts lists the products that fit a device, narrowed down by
+// the usual product filters
+func (s *deviceService) ListDeviceProducts(id uint, filter models.ProductFilter) (*models.PaginatedResponse, error) {
+	if _, err := s.repo.GetByID(id); err != nil {
+		return nil, err
+	}
+
+	if err := preparePriceFilter(s.priceListRepo, &filter); err != nil {
+		return nil, err
+	}
+	filter.DeviceID = &id
+	return s.productRepo.List(filter)
+}
+
+// BackfillCompatibility turns the free-text compatible attribute of products
+// that have no device links yet into links, creating the brands and device
+// models it names. Values that do not name a specific device, like
+// "Universel", are left as they are. The backfill only runs once, so links
+// removed later stay removed. It returns the number of products linked.
+func (s *deviceService) BackfillCompatibility() (int, error) {
+	linked := 0
+	err := s.repo.BackfillOnce(func(repo repository.DeviceRepository) error {
+		values, err := repo.ListUnlinkedCompatibility()
+		if err != nil {
+			return err
+		}
+
+		for _, value := range values {
+			devices, unmatched := parseCompatibility(value.Value)
+			if len(unmatched) > 0 {
+				log.Printf("Product %d: no device model recognised in compatible value(s) %q", value.ProductID, unmatched)
+			}
+			if len(devices) == 0 {
+				continue
+			}
+			if err := repo.LinkCompatibility(value.ProductID, devices); err != nil {
+				return err
+			}
+			linked++
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return linked, nil
+}
+
+func (s *deviceService) validateDevice(device *models.DeviceModel) error {
+	device.Name = strings.TrimSpace(device.Name)
+	if device.Name == "" {
+		return errors.New("device name is required")
+	}
+	if device.ReleaseYear != nil && (*device.ReleaseYear < 1990 || *device.ReleaseYear > 2100) {
+		return errors.New("release year is out of range")
+	}
+	if _, err := s.brandRepo.GetByID(device.BrandID); err != nil {
+		return err
+	}
+	return nil
+}
+
+// deviceLines maps the way a device line is written in free text to the brand
+// that makes it. When keepPrefix is set the prefix is part of the model name
+// ("iPhone 15"), otherwise it only names the brand ("Samsung Galaxy S23").
+var deviceLines = []struct {
+	prefix     string
+	brand      string
+	keepPrefix bool
+}{
+	{prefix: "iPhone", brand: "Apple", keepPrefix: true},
+	{prefix: "iPad", brand: "Apple", keepPrefix: true},
+	{prefix: "Apple", brand: "Apple"},
+	{prefix: "Samsung", brand: "Samsung"},
+	{prefix: "Galaxy", brand: "Samsung", keepPrefix: true},
+	{prefix: "Google", brand: "Google"},
+	{prefix: "Pixel", brand: "Google", keepPrefix: true},
+	{prefix: "Xiaomi", brand: "Xiaomi"},
+	{prefix: "Redmi", brand: "Xiaomi", keepPrefix: true},
+	{prefix: "Huawei", brand: "Huawei"},
+	{prefix: "OnePlus", brand: "OnePlus"},
+	{prefix: "Oppo", brand: "Oppo"},
+	{prefix: "Sony", brand: "Sony"},
+	{prefix: "Motorola", brand: "Motorola"},
+}
+
+// parseCompatibility splits a free-text compatible value such as
+// "iPhone 15, Samsung Galaxy S23" into device models. Parts that only name a
+// brand or a family of devices are returned as unmatched.
+func parseCompatibility(value string) ([]models.DeviceModel, []string) {
+	var devices []models.DeviceModel
+	var unmatched []string
+	seen := make(map[string]bool)
+
+	parts := strings.FieldsFunc(value, func(r rune) bool {
+		return r == ',' || r == ';' || r == '/' || r == '|'
+	})
+	for _, part := range parts {
+		for _, name := range strings.Split(part, " et ") {
+			name = strings.Join(strings.Fields(name), " ")
+			if name == "" || strings.EqualFold(name, "etc.") || strings.EqualFold(name, "etc") {
+				continue
+			}
+
+			device, ok := parseDevice(name)
+			if !ok {
+				unmatched = append(unmatched, name)
+				continue
+			}
+			key := device.Brand.Name + "/" + device.Name
+			if !seen[key] {
+				seen[key] = true
+				devices = append(devices, device)
+			}
+		}
+	}
+	return devices, unmatched
+}
+
+func parseDevice(name string) (models.DeviceModel, bool) {
+	for _, line := range deviceLines {
+		if len(name) < len(line.prefix) || !strings.EqualFold(name[:len(line.prefix)], line.prefix) {
+			continue
+		}
+		rest := name[len(line.prefix):]
+		if rest != "" && rest[0] != ' ' {
+			continue
+		}
+		rest = strings.TrimSpace(rest)
+		// A bare brand or family name does not say which devices it fits
+		if rest == "" {
+			return models.DeviceModel{}, false
+		}
+
+		model := rest
+		if line.keepPrefix {
+			model = line.prefix + " " + rest
+		} else if strings.EqualFold(model, "Galaxy") || strings.EqualFold(model, "Pixel") {
+			return models.DeviceModel{}, false
+		}
+		return models.DeviceModel{Brand: &models.Brand{Name: line.brand}, Name: model}, true
+	}
+	return models.DeviceModel{}, false
+}
@@ -0,0 +1,84 @@
+// internal/service/device_service_test.go
+package service
+
+import (
+	"testing"
+
+	"phone-accessories/internal/models"
+	"phone-accessories/internal/repository"
+)
+
+// fakeDeviceRepository keeps compatibility links in memory and remembers
+// whether the backfill has run. Only the methods the tests call are
+// implemented.
+type fakeDeviceRepository struct {
+	repository.DeviceRepository
+	values     []models.CompatibilityValue
+	links      map[uint][]models.DeviceModel
+	backfilled bool
+}
+
+func (r *fakeDeviceRepository) ListUnlinkedCompatibility() ([]models.CompatibilityValue, error) {
+	var values []models.CompatibilityValue
+	for _, value := range r.values {
+		if _, ok := r.links[value.ProductID]; !ok {
+			values = append(values, value)
+		}
+	}
+	return values, nil
+}
+
+func (r *fakeDeviceRepository) LinkCompatibility(productID uint, devices []models.DeviceModel) error {
+	r.links[productID] = append(r.links[productID], devices...)
+	return nil
+}
+
+func (r *fakeDeviceRepository) BackfillOnce(backfill func(repo repository.DeviceRepository) error) error {
+	if r.backfilled {
+		return nil
+	}
+	if err := backfill(r); err != nil {
+		return err
+	}
+	r.backfilled = true
+	return nil
+}
+
+func TestBackfillCompatibility(t *testing.T) {
+	repo := &fakeDeviceRepository{
+		values: []models.CompatibilityValue{
+			{ProductID: 1, Value: "iPhone 15, Samsung Galaxy S23"},
+			{ProductID: 2, Value: "Universel"},
+			{ProductID: 3, Value: "Google Pixel 8"},
+		},
+		links: make(map[uint][]models.DeviceModel),
+	}
+	service := &deviceService{repo: repo}
+
+	linked, err := service.BackfillCompatibility()
+	if err != nil {
+		t.Fatalf("BackfillCompatibility() error = %v", err)
+	}
+	if linked != 2 {
+		t.Errorf("BackfillCompatibility() linked %d products, want 2", linked)
+	}
+	if got := len(repo.links[1]); got != 2 {
+		t.Errorf("product 1 has %d devices, want 2", got)
+	}
+	if _, ok := repo.links[2]; ok {
+		t.Errorf("product 2 was linked to %v, want no devices", repo.links[2])
+	}
+
+	// Devices removed from a product afterwards stay removed
+	delete(repo.links, 1)
+	linked, err = service.BackfillCompatibility()
+	if err != nil {
+		t.Fatalf("second BackfillCompatibility() error = %v", err)
+	}
+	if linked != 0 {
+		t.Errorf("second BackfillCompatibility() linked %d products, want none", linked)
+	}
+	if _, ok := repo.links[1]; ok {
+		t.Errorf("product 1 was linked again to %v", repo.links[1])
+	}
+}
@@ -0,0 +1,145 @@
+// internal/service/discount_service.go
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"phone-accessories/internal/models"
+	"phone-accessories/internal/repository"
+)
+
+type DiscountService interface {
+	CreateDiscountRule(rule *models.DiscountRule) error
+	GetDiscountRuleByID(id uint) (*models.DiscountRule, error)
+	UpdateDiscountRule(rule *models.DiscountRule) error
+	DeleteDiscountRule(id uint) error
+	ListDiscountRules(filter models.DiscountRuleFilter) ([]models.DiscountRule, error)
+}
+
+type discountService struct {
+	repo         repository.DiscountRepository
+	productRepo  repository.ProductRepository
+	categoryRepo repository.CategoryRepository
+	brandRepo    repository.BrandRepository
+}
+
+func NewDiscountService(repo repository.DiscountRepository, productRepo repository.ProductRepository,
+	categoryRepo repository.CategoryRepository, brandRepo repository.BrandRepository) DiscountService {
+	return &discountService{repo: repo, productRepo: productRepo, categoryRepo: categoryRepo, brandRepo: brandRepo}
+}
+
+func (s *discountService) CreateDiscountRule(rule *models.DiscountRule) error {
+	if err := s.validateDiscountRule(rule); err != nil {
+		return err
+	}
+
+	return s.repo.Create(rule)
+}
+
+func (s *discountService) GetDiscountRuleByID(id uint) (*models.DiscountRule, error) {
+	return s.repo.GetByID(id)
+}
+
+func (s *discountService) UpdateDiscountRule(rule *models.DiscountRule) error {
+	if err := s.validateDiscountRule(rule); err != nil {
+		return err
+	}
+
+	return s.repo.Update(rule)
+}
+
+func (s *discountService) DeleteDiscountRule(id uint) error {
+	return s.repo.Delete(id)
+}
+
+func (s *discountService) ListDiscountRules(filter models.DiscountRuleFilter) ([]models.DiscountRule, error) {
+	return s.repo.List(filter)
+}
+
+func (s *discountService) validateDiscountRule(rule *models.DiscountRule) error {
+	rule.Name = strings.TrimSpace(rule.Name)
+	if rule.Name == "" {
+		return errors.New("discount name is required")
+	}
+	if !rule.Scope.IsValid() {
+		return fmt.Errorf("invalid discount scope %q", rule.Scope)
+	}
+	if !rule.Type.IsValid() {
+		return fmt.Errorf("invalid discount type %q", rule.Type)
+	}
+
+	if rule.Value.Currency == "" {
+		rule.Value.Currency = models.DefaultCurrency
+	}
+	switch rule.Type {
+	case models.DiscountTypePercentage:
+		if rule.Percentage <= 0 || rule.Percentage > 100 {
+			return errors.New("percentage off must be greater than 0 and at most 100")
+		}
+		rule.Value = models.Money{Currency: rule.Value.Currency}
+		rule.BuyQuantity, rule.GetQuantity = 0, 0
+	case models.DiscountTypeFixed:
+		if rule.Value.Amount <= 0 {
+			return errors.New("amount off must be greater than zero")
+		}
+		rule.Percentage = 0
+		rule.BuyQuantity, rule.GetQuantity = 0, 0
+	case models.DiscountTypeBuyXGetY:
+		if rule.BuyQuantity < 1 || rule.GetQuantity < 1 {
+			return errors.New("buy and get quantities must be at least 1")
+		}
+		rule.Percentage = 0
+		rule.Value = models.Money{Currency: rule.Value.Currency}
+	}
+
+	if rule.MinBasket.Currency == "" {
+		rule.MinBasket.Currency = rule.Value.Currency
+	}
+	if rule.MinBasket.Amount < 0 {
+		return errors.New("minimum basket cannot be negative")
+	}
+	if rule.Type == models.DiscountTypeFixed && rule.MinBasket.Amount > 0 && rule.MinBasket.Currency != rule.Value.Currency {
+		return errors.New("minimum basket must be in the currency of the amount off")
+	}
+
+	if rule.CouponCode != nil {
+		code := strings.ToUpper(strings.TrimSpace(*rule.CouponCode))
+		if code == "" {
+			rule.CouponCode = nil
+		} else {
+			rule.CouponCode = &code
+		}
+	}
+	if rule.UsageLimit != nil && *rule.UsageLimit < 1 {
+		return errors.New("usage limit must be at least 1")
+	}
+
+	// Rules without a start apply from now on
+	if rule.StartsAt.IsZero() {
+		rule.StartsAt = time.Now().UTC()
+	}
+	if rule.EndsAt != nil && !rule.EndsAt.After(rule.StartsAt) {
+		return errors.New("discount must end after it starts")
+	}
+
+	if rule.Scope == models.DiscountScopeCart {
+		rule.TargetID = nil
+		return nil
+	}
+	if rule.TargetID == nil {
+		return fmt.Errorf("a %s discount needs a targetId", rule.Scope)
+	}
+	var err error
+	switch rule.Scope {
+	case models.DiscountScopeProduct:
+		_, err = s.productRepo.GetByID(*rule.TargetID)
+	case models.DiscountScopeCategory:
+		_, err = s.categoryRepo.GetByID(*rule.TargetID)
+	case models.DiscountScopeBrand:
+		_, err = s.brandRepo.GetByID(*rule.TargetID)
+	}
+	return err
+}
@@ -0,0 +1,137 @@
+// internal/service/inventory_service.go
+package service
+
+import (
+	"errors"
+	"time"
+
+	"phone-accessories/internal/models"
+	"phone-accessories/internal/repository"
+)
+
+type InventoryService interface {
+	TransferStock(transfer *models.StockTransfer) ([]models.StockMovement, error)
+	ListLowStock() ([]models.LowStockItem, error)
+	AdjustStock(lines []models.StockAdjustmentLine, referenceID, actor string) ([]models.StockAdjustmentResult, error)
+}
+
+type inventoryService struct {
+	productRepo  repository.ProductRepository
+	locationRepo repository.LocationRepository
+	stockService ProductService
+}
+
+func NewInventoryService(productRepo repository.ProductRepository, locationRepo repository.LocationRepository,
+	stockService ProductService) InventoryService {
+	return &inventoryService{productRepo: productRepo, locationRepo: locationRepo, stockService: stockService}
+}
+
+func (s *inventoryService) TransferStock(transfer *models.StockTransfer) ([]models.StockMovement, error) {
+	if transfer.ProductID == 0 {
+		return nil, errors.New("transfer product ID is required")
+	}
+	if transfer.Quantity <= 0 {
+		return nil, errors.New("transfer quantity must be greater than zero")
+	}
+	if transfer.FromLocationID == 0 || transfer.ToLocationID == 0 {
+		return nil, errors.New("transfer source and destination locations are required")
+	}
+	if transfer.FromLocationID == transfer.ToLocationID {
+		return nil, errors.New("transfer source and destination locations must differ")
+	}
+
+	return s.locationRepo.Transfer(transfer)
+}
+
+func (s *inventoryService) ListLowStock() ([]models.LowStockItem, error) {
+	products, err := s.productRepo.ListLowStock()
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]models.LowStockItem, 0, len(products))
+	for _, product := range products {
+		items = append(items, models.LowStockItem{
+			ProductID:         product.ID,
+			SKU:               product.SKU,
+			Name:              product.Name,
+			StockLevel:        product.StockLevel,
+			AvailableQuantity: product.AvailableQuantity,
+			ReorderPoint:      product.ReorderPoint,
+			ReorderQuantity:   product.ReorderQuantity,
+			IncomingQuantity:  product.IncomingQuantity,
+			SuggestedQuantity: models.SuggestedReorderQuantity(product),
+		})
+	}
+	return items, nil
+}
+
+func (s *inventoryService) AdjustStock(lines []models.StockAdjustmentLine, referenceID, actor string) ([]models.StockAdjustmentResult, error) {
+	if len(lines) == 0 {
+		return nil, errors.New("stock adjustment must contain at least one line")
+	}
+
+	// Lines are validated as they are applied, so that a rejected batch
+	// reports every failing line, whether its fields or its stock are at fault
+	for i := range lines {
+		if lines[i].Reason == "" {
+			lines[i].Reason = models.StockReasonAdjustment
+		}
+	}
+
+	results, err := s.productRepo.AdjustStock(lines, referenceID, actor)
+	if err != nil {
+		return nil, err
+	}
+
+	s.stockService.StockChanged(adjustedStock(results)...)
+	return results, nil
+}
+
+// adjustedStock sums up the lines of a batch adjustment into one movement per
+// product. A product can appear on several lines, so its level before the
+// first line is compared with its level after the last one.
+func adjustedStock(results []models.StockAdjustmentResult) []models.StockMovement {
+	first := make(map[uint]models.StockAdjustmentResult)
+	last := make(map[uint]models.StockAdjustmentResult)
+	var order []uint
+	for _, result := range results {
+		if _, ok := first[result.ProductID]; !ok {
+			first[result.ProductID] = result
+			order = append(order, result.ProductID)
+		}
+		last[result.ProductID] = result
+	}
+
+	movements := make([]models.StockMovement, 0, len(order))
+	for _, productID := range order {
+		start := first[productID]
+		movements = append(movements, models.StockMovement{
+			ProductID:      productID,
+			Delta:          last[productID].ResultingLevel - (start.ResultingLevel - start.Delta),
+			ResultingLevel: last[productID].ResultingLevel,
+			CreatedAt:      time.Now().UTC(),
+		})
+	}
+	return movements
+}
+
+// lowStockEvent reports whether a stock movement took a product from at or
+// above its reorder point to below it
+func lowStockEvent(product *models.Product, movement *models.StockMovement) (models.LowStockEvent, bool) {
+	previous := movement.ResultingLevel - movement.Delta
+	if product.ReorderPoint <= 0 || previous < product.ReorderPoint || movement.ResultingLevel >= product.ReorderPoint {
+		return models.LowStockEvent{}, false
+	}
+
+	return models.LowStockEvent{
+		ProductID:       product.ID,
+		SKU:             product.SKU,
+		Name:            product.Name,
+		StockLevel:      movement.ResultingLevel,
+		PreviousLevel:   previous,
+		ReorderPoint:    product.ReorderPoint,
+		ReorderQuantity: product.ReorderQuantity,
+		OccurredAt:      movement.CreatedAt,
+	}, true
+}
@@ -0,0 +1,37 @@
+// internal/service/inventory_service_test.go
+package service
+
+import (
+	"testing"
+
+	"phone-accessories/internal/models"
+)
+
+func TestAdjustedStock(t *testing.T) {
+	results := []models.StockAdjustmentResult{
+		{ProductID: 1, Delta: -4, ResultingLevel: 6},
+		{ProductID: 2, Delta: 5, ResultingLevel: 5},
+		{ProductID: 1, Delta: 3, ResultingLevel: 9},
+		{ProductID: 1, Delta: -7, ResultingLevel: 2},
+	}
+
+	movements := adjustedStock(results)
+	if len(movements) != 2 {
+		t.Fatalf("adjustedStock() = %d movements, want 2", len(movements))
+	}
+	want := []struct {
+		productID      uint
+		delta          int
+		resultingLevel int
+	}{
+		{productID: 1, delta: -8, resultingLevel: 2},
+		{productID: 2, delta: 5, resultingLevel: 5},
+	}
+	for i, w := range want {
+		got := movements[i]
+		if got.ProductID != w.productID || got.Delta != w.delta || got.ResultingLevel != w.resultingLevel {
+			t.Errorf("movement %d = product %d, delta %d, level %d; want product %d, delta %d, level %d",
+				i, got.ProductID, got.Delta, got.ResultingLevel, w.productID, w.delta, w.resultingLevel)
+		}
+	}
+}
@@ -0,0 +1,70 @@
+// internal/service/location_service.go
+package service
+
+import (
+	"errors"
+	"fmt"
+
+	"phone-accessories/internal/models"
+	"phone-accessories/internal/repository"
+)
+
+type LocationService interface {
+	CreateLocation(location *models.Location) error
+	GetLocationByID(id uint) (*models.Location, error)
+	UpdateLocation(location *models.Location) error
+	DeleteLocation(id uint) error
+	ListLocations() ([]models.Location, error)
+}
+
+type locationService struct {
+	repo repository.LocationRepository
+}
+
+func NewLocationService(repo repository.LocationRepository) LocationService {
+	return &locationService{repo: repo}
+}
+
+func (s *locationService) CreateLocation(location *models.Location) error {
+	if err := validateLocation(location); err != nil {
+		return err
+	}
+
+	return s.repo.Create(location)
+}
+
+func (s *locationService) GetLocationByID(id uint) (*models.Location, error) {
+	return s.repo.GetByID(id)
+}
+
+func (s *locationService) UpdateLocation(location *models.Location) error {
+	if err := validateLocation(location); err != nil {
+		return err
+	}
+
+	return s.repo.Update(location)
+}
+
+func (s *locationService) DeleteLocation(id uint) error {
+	return s.repo.Delete(id)
+}
+
+func (s *locationService) ListLocations() ([]models.Location, error) {
+	return s.repo.List()
+}
+
+func validateLocation(location *models.Location) error {
+	if location.Code == "" {
+		return errors.New("location code is required")
+	}
+	if location.Name == "" {
+		return errors.New("location name is required")
+	}
+	if location.Type == "" {
+		location.Type = models.LocationTypeWarehouse
+	}
+	if location.Type != models.LocationTypeWarehouse && location.Type != models.LocationTypeShop {
+		return fmt.Errorf("invalid location type %q", location.Type)
+	}
+	return nil
+}
@@ -0,0 +1,175 @@
+// internal/service/media_service.go
+package service
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+
+	"phone-accessories/internal/models"
+	"phone-accessories/internal/repository"
+	"phone-accessories/internal/storage"
+)
+
+// mediaExtensions lists the accepted image formats, keyed by sniffed content type
+var mediaExtensions = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/gif":  ".gif",
+	"image/webp": ".webp",
+}
+
+type MediaService interface {
+	UploadMedia(media *models.ProductMedia, content io.Reader, size int64) error
+	ListMedia(productID uint) ([]models.ProductMedia, error)
+	UpdateMedia(productID, id uint, update models.ProductMediaUpdate) (*models.ProductMedia, error)
+	DeleteMedia(productID, id uint) error
+	MaxUploadSize() int64
+}
+
+type mediaService struct {
+	repo          repository.MediaRepository
+	productRepo   repository.ProductRepository
+	storage       storage.Storage
+	maxUploadSize int64
+}
+
+func NewMediaService(repo repository.MediaRepository, productRepo repository.ProductRepository,
+	storage storage.Storage, maxUploadSize int64) MediaService {
+	return &mediaService{repo: repo, productRepo: productRepo, storage: storage, maxUploadSize: maxUploadSize}
+}
+
+// UploadMedia stores an uploaded image and adds it to the product's gallery.
+// The content type is sniffed from the file itself rather than trusted from
+// the client.
+func (s *mediaService) UploadMedia(media *models.ProductMedia, content io.Reader, size int64) error {
+	if _, err := s.productRepo.GetByID(media.ProductID); err != nil {
+		return err
+	}
+	if err := s.checkVariant(media.ProductID, media.VariantID); err != nil {
+		return err
+	}
+	if size > s.maxUploadSize {
+		return fmt.Errorf("%w: %d bytes exceeds the limit of %d bytes", models.ErrMediaTooLarge, size, s.maxUploadSize)
+	}
+
+	head := make([]byte, 512)
+	n, err := io.ReadFull(content, head)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) {
+		if errors.Is(err, io.EOF) {
+			return errors.New("uploaded file is empty")
+		}
+		return err
+	}
+	head = head[:n]
+
+	contentType := http.DetectContentType(head)
+	extension, ok := mediaExtensions[contentType]
+	if !ok {
+		return fmt.Errorf("%w: %s", models.ErrUnsupportedMediaType, contentType)
+	}
+
+	name := make([]byte, 16)
+	if _, err := rand.Read(name); err != nil {
+		return err
+	}
+	key := fmt.Sprintf("products/%d/%s%s", media.ProductID, hex.EncodeToString(name), extension)
+
+	// Guard against a body longer than the size it was announced with
+	body := io.LimitReader(io.MultiReader(bytes.NewReader(head), content), s.maxUploadSize)
+	url, err := s.storage.Save(key, body, contentType)
+	if err != nil {
+		return err
+	}
+
+	media.StorageKey = key
+	media.URL = url
+	media.ContentType = contentType
+	media.Size = size
+	if err := s.repo.Create(media); err != nil {
+		if deleteErr := s.storage.Delete(key); deleteErr != nil {
+			log.Printf("Failed to remove orphaned media %s: %v", key, deleteErr)
+		}
+		return err
+	}
+	return nil
+}
+
+func (s *mediaService) ListMedia(productID uint) ([]models.ProductMedia, error) {
+	if _, err := s.productRepo.GetByID(productID); err != nil {
+		return nil, err
+	}
+	return s.repo.List(productID)
+}
+
+func (s *mediaService) UpdateMedia(productID, id uint, update models.ProductMediaUpdate) (*models.ProductMedia, error) {
+	media, err := s.repo.GetByID(productID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if update.AltText != nil {
+		media.AltText = *update.AltText
+	}
+	if update.Position != nil {
+		if *update.Position < 0 {
+			return nil, errors.New("media position must not be negative")
+		}
+		media.Position = *update.Position
+	}
+	if update.IsPrimary != nil {
+		media.IsPrimary = *update.IsPrimary
+	}
+	if update.VariantID != nil {
+		// A variant ID of zero detaches the media from its variant
+		if *update.VariantID == 0 {
+			media.VariantID = nil
+		} else {
+			if err := s.checkVariant(productID, update.VariantID); err != nil {
+				return nil, err
+			}
+			media.VariantID = update.VariantID
+		}
+	}
+
+	if err := s.repo.Update(media); err != nil {
+		return nil, err
+	}
+	return media, nil
+}
+
+func (s *mediaService) DeleteMedia(productID, id uint) error {
+	media, err := s.repo.Delete(productID, id)
+	if err != nil {
+		return err
+	}
+	// The gallery no longer references the file, so a failure here only leaves an orphan behind
+	if err := s.storage.Delete(media.StorageKey); err != nil {
+		log.Printf("Failed to remove media file %s: %v", media.StorageKey, err)
+	}
+	return nil
+}
+
+func (s *mediaService) MaxUploadSize() int64 {
+	return s.maxUploadSize
+}
+
+// checkVariant makes sure media is only attached to a variant of the product itself
+func (s *mediaService) checkVariant(productID uint, variantID *uint) error {
+	if variantID == nil {
+		return nil
+	}
+	variant, err := s.productRepo.GetByID(*variantID)
+	if err != nil {
+		return fmt.Errorf("media variant %d: %v", *variantID, err)
+	}
+	if variant.ParentID == nil || *variant.ParentID != productID {
+		return fmt.Errorf("product %d is not a variant of product %d", *variantID, productID)
+	}
+	return nil
+}
@@ -0,0 +1,291 @@
+// internal/service/price_list_service.go
+package service
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"phone-accessories/internal/models"
+	"phone-accessories/internal/repository"
+)
+
+type PriceListService interface {
+	CreatePriceList(list *models.PriceList) error
+	GetPriceListByID(id uint) (*models.PriceList, error)
+	UpdatePriceList(list *models.PriceList) error
+	DeletePriceList(id uint) error
+	ListPriceLists(filter models.PriceListFilter) ([]models.PriceList, error)
+	SetProductPrice(priceListID, productID uint, amount string) (*models.ProductPrice, error)
+	DeleteProductPrice(priceListID, productID uint) error
+	ListProductPrices(priceListID uint) ([]models.ProductPrice, error)
+	CreateExchangeRate(rate *models.ExchangeRate) error
+	DeleteExchangeRate(id uint) error
+	ListExchangeRates(filter models.ExchangeRateFilter) ([]models.ExchangeRate, error)
+}
+
+type priceListService struct {
+	repo        repository.PriceListRepository
+	productRepo repository.ProductRepository
+}
+
+func NewPriceListService(repo repository.PriceListRepository, productRepo repository.ProductRepository) PriceListService {
+	return &priceListService{repo: repo, productRepo: productRepo}
+}
+
+func (s *priceListService) CreatePriceList(list *models.PriceList) error {
+	if err := validatePriceList(list); err != nil {
+		return err
+	}
+
+	return s.repo.Create(list)
+}
+
+func (s *priceListService) GetPriceListByID(id uint) (*models.PriceList, error) {
+	return s.repo.GetByID(id)
+}
+
+func (s *priceListService) UpdatePriceList(list *models.PriceList) error {
+	if err := validatePriceList(list); err != nil {
+		return err
+	}
+
+	return s.repo.Update(list)
+}
+
+func (s *priceListService) DeletePriceList(id uint) error {
+	return s.repo.Delete(id)
+}
+
+func (s *priceListService) ListPriceLists(filter models.PriceListFilter) ([]models.PriceList, error) {
+	if filter.Currency != "" {
+		currency, err := models.NormalizeCurrency(filter.Currency)
+		if err != nil {
+			return nil, err
+		}
+		filter.Currency = currency
+	}
+	return s.repo.List(filter)
+}
+
+// SetProductPrice sets the price of a product or variant in a price list. The
+// amount is a decimal in the list's currency.
+func (s *priceListService) SetProductPrice(priceListID, productID uint, amount string) (*models.ProductPrice, error) {
+	list, err := s.repo.GetByID(priceListID)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.productRepo.GetByID(productID); err != nil {
+		return nil, err
+	}
+
+	price, err := models.ParseMoney(amount, list.Currency)
+	if err != nil {
+		return nil, err
+	}
+	if price.Amount <= 0 {
+		return nil, errors.New("price must be greater than zero")
+	}
+
+	productPrice := &models.ProductPrice{PriceListID: list.ID, ProductID: productID, Price: price}
+	if err := s.repo.SetPrice(productPrice); err != nil {
+		return nil, err
+	}
+	return productPrice, nil
+}
+
+func (s *priceListService) DeleteProductPrice(priceListID, productID uint) error {
+	if _, err := s.repo.GetByID(priceListID); err != nil {
+		return err
+	}
+	return s.repo.DeletePrice(priceListID, productID)
+}
+
+func (s *priceListService) ListProductPrices(priceListID uint) ([]models.ProductPrice, error) {
+	if _, err := s.repo.GetByID(priceListID); err != nil {
+		return nil, err
+	}
+	return s.repo.ListPrices(priceListID)
+}
+
+func (s *priceListService) CreateExchangeRate(rate *models.ExchangeRate) error {
+	var err error
+	if rate.BaseCurrency, err = models.NormalizeCurrency(rate.BaseCurrency); err != nil {
+		return err
+	}
+	if rate.QuoteCurrency, err = models.NormalizeCurrency(rate.QuoteCurrency); err != nil {
+		return err
+	}
+	if rate.BaseCurrency == rate.QuoteCurrency {
+		return errors.New("an exchange rate needs two different currencies")
+	}
+	if value, ok := rate.Rate.Rat(); !ok || value.Sign() <= 0 {
+		return errors.New("exchange rate must be greater than zero")
+	}
+	// Rates take effect for whole days; without a date a rate applies from today
+	if rate.EffectiveFrom.IsZero() {
+		rate.EffectiveFrom = time.Now().UTC()
+	}
+	rate.EffectiveFrom = rate.EffectiveFrom.UTC().Truncate(24 * time.Hour)
+
+	return s.repo.CreateRate(rate)
+}
+
+func (s *priceListService) DeleteExchangeRate(id uint) error {
+	return s.repo.DeleteRate(id)
+}
+
+func (s *priceListService) ListExchangeRates(filter models.ExchangeRateFilter) ([]models.ExchangeRate, error) {
+	if filter.Currency != "" {
+		currency, err := models.NormalizeCurrency(filter.Currency)
+		if err != nil {
+			return nil, err
+		}
+		filter.Currency = currency
+	}
+	return s.repo.ListRates(filter)
+}
+
+func validatePriceList(list *models.PriceList) error {
+	list.Name = strings.TrimSpace(list.Name)
+	if list.Name == "" {
+		return errors.New("price list name is required")
+	}
+	if list.Currency == "" {
+		return errors.New("price list currency is required")
+	}
+	currency, err := models.NormalizeCurrency(list.Currency)
+	if err != nil {
+		return err
+	}
+	list.Currency = currency
+	return nil
+}
+
+// resolvePricing works out how prices are resolved for a price query: in the
+// price list asked for, or else in the currency's default price list if it
+// has one, with base prices in other currencies converted at the rates in
+// effect today
+func resolvePricing(repo repository.PriceListRepository, query models.PriceQuery) (*models.PriceContext, error) {
+	var currency string
+	if query.Currency != "" {
+		var err error
+		if currency, err = models.NormalizeCurrency(query.Currency); err != nil {
+			return nil, fmt.Errorf("%w: %v", models.ErrInvalidPriceQuery, err)
+		}
+	}
+
+	var list *models.PriceList
+	if query.PriceListID != nil {
+		var err error
+		if list, err = repo.GetByID(*query.PriceListID); err != nil {
+			return nil, err
+		}
+		if !list.IsActive {
+			return nil, fmt.Errorf("%w: price list %d is not active", models.ErrInvalidPriceQuery, list.ID)
+		}
+		if currency != "" && currency != list.Currency {
+			return nil, fmt.Errorf("%w: price list %d is in %s, not %s", models.ErrInvalidPriceQuery, list.ID, list.Currency, currency)
+		}
+		currency = list.Currency
+	} else {
+		var err error
+		if list, err = repo.GetDefault(currency); err != nil {
+			return nil, err
+		}
+	}
+
+	rates, err := repo.LatestRates(currency, time.Now().UTC())
+	if err != nil {
+		return nil, err
+	}
+	return &models.PriceContext{Currency: currency, PriceList: list, Factors: conversionFactors(currency, rates)}, nil
+}
+
+// conversionFactors turns the rates in effect between a currency and others
+// into factors converting minor units of the others into minor units of the
+// currency. A rate stored the other way round is inverted; when a pair has
+// rates both ways the more recent one wins.
+func conversionFactors(currency string, rates []models.ExchangeRate) map[string]string {
+	type candidate struct {
+		rate          *big.Rat
+		effectiveFrom time.Time
+		direct        bool
+	}
+	best := make(map[string]candidate)
+	for _, rate := range rates {
+		value, ok := rate.Rate.Rat()
+		if !ok || value.Sign() <= 0 {
+			continue
+		}
+		from, direct := rate.BaseCurrency, true
+		if rate.BaseCurrency == currency {
+			from, direct = rate.QuoteCurrency, false
+			value.Inv(value)
+		}
+		current, seen := best[from]
+		if seen && (current.effectiveFrom.After(rate.EffectiveFrom) ||
+			(current.effectiveFrom.Equal(rate.EffectiveFrom) && current.direct)) {
+			continue
+		}
+		best[from] = candidate{rate: value, effectiveFrom: rate.EffectiveFrom, direct: direct}
+	}
+
+	factors := make(map[string]string, len(best))
+	for from, candidate := range best {
+		shift := models.CurrencyExponent(currency) - models.CurrencyExponent(from)
+		scale := new(big.Rat).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(abs(shift))), nil))
+		factor := new(big.Rat).Set(candidate.rate)
+		if shift >= 0 {
+			factor.Mul(factor, scale)
+		} else {
+			factor.Quo(factor, scale)
+		}
+		factors[from] = factor.FloatString(12)
+	}
+	return factors
+}
+
+// preparePriceFilter resolves the pricing a product filter asks for and reads
+// its price bounds as exact amounts in the currency they are compared in
+func preparePriceFilter(repo repository.PriceListRepository, filter *models.ProductFilter) error {
+	currency := models.DefaultCurrency
+	query := models.PriceQuery{PriceListID: filter.PriceListID, Currency: filter.Currency}
+	if query.IsSet() {
+		pricing, err := resolvePricing(repo, query)
+		if err != nil {
+			return err
+		}
+		filter.Pricing = pricing
+		currency = pricing.Currency
+	}
+
+	bounds := []struct {
+		name  string
+		value string
+		price **models.Money
+	}{
+		{name: "minPrice", value: filter.MinPrice, price: &filter.PriceRange.Min},
+		{name: "maxPrice", value: filter.MaxPrice, price: &filter.PriceRange.Max},
+	}
+	for _, bound := range bounds {
+		if bound.value == "" {
+			continue
+		}
+		price, err := models.ParseMoney(bound.value, currency)
+		if err != nil {
+			return fmt.Errorf("%w: %s: %v", models.ErrInvalidPriceQuery, bound.name, err)
+		}
+		*bound.price = &price
+	}
+	return nil
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
@@ -0,0 +1,96 @@
+// internal/service/price_list_service_test.go
+package service
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"phone-accessories/internal/models"
+)
+
+func TestConversionFactors(t *testing.T) {
+	older := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	newer := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		currency string
+		rates    []models.ExchangeRate
+		want     map[string]string
+	}{
+		{
+			name:     "no rates",
+			currency: "PLN",
+			want:     map[string]string{},
+		},
+		{
+			name:     "direct rate",
+			currency: "PLN",
+			rates: []models.ExchangeRate{
+				{BaseCurrency: "EUR", QuoteCurrency: "PLN", Rate: "4.3", EffectiveFrom: older},
+			},
+			want: map[string]string{"EUR": "4.300000000000"},
+		},
+		{
+			name:     "rate stored the other way round is inverted",
+			currency: "EUR",
+			rates: []models.ExchangeRate{
+				{BaseCurrency: "EUR", QuoteCurrency: "USD", Rate: "1.25", EffectiveFrom: older},
+			},
+			want: map[string]string{"USD": "0.800000000000"},
+		},
+		{
+			name:     "into a currency without decimals",
+			currency: "JPY",
+			rates: []models.ExchangeRate{
+				{BaseCurrency: "EUR", QuoteCurrency: "JPY", Rate: "160", EffectiveFrom: older},
+			},
+			want: map[string]string{"EUR": "1.600000000000"},
+		},
+		{
+			name:     "from a currency without decimals",
+			currency: "EUR",
+			rates: []models.ExchangeRate{
+				{BaseCurrency: "JPY", QuoteCurrency: "EUR", Rate: "0.00625", EffectiveFrom: older},
+			},
+			want: map[string]string{"JPY": "0.625000000000"},
+		},
+		{
+			name:     "more recent rate wins",
+			currency: "PLN",
+			rates: []models.ExchangeRate{
+				{BaseCurrency: "EUR", QuoteCurrency: "PLN", Rate: "4.3", EffectiveFrom: older},
+				{BaseCurrency: "PLN", QuoteCurrency: "EUR", Rate: "0.25", EffectiveFrom: newer},
+			},
+			want: map[string]string{"EUR": "4.000000000000"},
+		},
+		{
+			name:     "direct rate wins on the same date",
+			currency: "PLN",
+			rates: []models.ExchangeRate{
+				{BaseCurrency: "PLN", QuoteCurrency: "EUR", Rate: "0.25", EffectiveFrom: newer},
+				{BaseCurrency: "EUR", QuoteCurrency: "PLN", Rate: "4.3", EffectiveFrom: newer},
+			},
+			want: map[string]string{"EUR": "4.300000000000"},
+		},
+		{
+			name:     "unusable rates are skipped",
+			currency: "PLN",
+			rates: []models.ExchangeRate{
+				{BaseCurrency: "EUR", QuoteCurrency: "PLN", Rate: "0", EffectiveFrom: newer},
+				{BaseCurrency: "USD", QuoteCurrency: "PLN", Rate: "abc", EffectiveFrom: newer},
+				{BaseCurrency: "EUR", QuoteCurrency: "PLN", Rate: "4.3", EffectiveFrom: older},
+			},
+			want: map[string]string{"EUR": "4.300000000000"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := conversionFactors(tt.currency, tt.rates); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("conversionFactors(%q) = %v, want %v", tt.currency, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,445 @@
+// internal/service/pricing_service.go
+package service
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"phone-accessories/internal/models"
+	"phone-accessories/internal/repository"
+)
+
+type PricingService interface {
+	ListPriceTiers(productID uint) ([]models.PriceTier, error)
+	SetPriceTiers(productID uint, tiers []models.PriceTier) ([]models.PriceTier, error)
+	Quote(items []models.QuoteItem, couponCode string, priceQuery models.PriceQuery) (*models.Quote, error)
+	Redeem(reference string, items []models.QuoteItem, couponCode string, priceQuery models.PriceQuery) (*models.Quote, error)
+}
+
+type pricingService struct {
+	tierRepo         repository.PriceTierRepository
+	productRepo      repository.ProductRepository
+	discountRepo     repository.DiscountRepository
+	categoryRepo     repository.CategoryRepository
+	priceListRepo    repository.PriceListRepository
+	defaultTaxRate   float64
+	pricesIncludeTax bool
+}
+
+func NewPricingService(tierRepo repository.PriceTierRepository, productRepo repository.ProductRepository,
+	discountRepo repository.DiscountRepository, categoryRepo repository.CategoryRepository,
+	priceListRepo repository.PriceListRepository, defaultTaxRate float64, pricesIncludeTax bool) PricingService {
+	return &pricingService{
+		tierRepo:         tierRepo,
+		productRepo:      productRepo,
+		discountRepo:     discountRepo,
+		categoryRepo:     categoryRepo,
+		priceListRepo:    priceListRepo,
+		defaultTaxRate:   defaultTaxRate,
+		pricesIncludeTax: pricesIncludeTax,
+	}
+}
+
+func (s *pricingService) ListPriceTiers(productID uint) ([]models.PriceTier, error) {
+	if _, err := s.productRepo.GetByID(productID); err != nil {
+		return nil, err
+	}
+	return s.tierRepo.List(productID)
+}
+
+// SetPriceTiers replaces the quantity tiers of a product. Tiers are unit
+// prices in the product's currency and must not overlap; quantities between
+// tiers are sold at the regular price.
+func (s *pricingService) SetPriceTiers(productID uint, tiers []models.PriceTier) ([]models.PriceTier, error) {
+	product, err := s.productRepo.GetByID(productID)
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(tiers, func(i, j int) bool { return tiers[i].MinQuantity < tiers[j].MinQuantity })
+	for i := range tiers {
+		tier := &tiers[i]
+		if tier.MinQuantity < 1 {
+			return nil, errors.New("tier minimum quantity must be at least 1")
+		}
+		if tier.MaxQuantity != nil && *tier.MaxQuantity < tier.MinQuantity {
+			return nil, fmt.Errorf("tier starting at %d ends before it starts", tier.MinQuantity)
+		}
+		if tier.UnitPrice.Amount <= 0 {
+			return nil, fmt.Errorf("tier starting at %d needs a unit price greater than zero", tier.MinQuantity)
+		}
+		if tier.UnitPrice.Currency != product.Price.Currency {
+			return nil, fmt.Errorf("tier unit prices must be in %s, the currency of the product's price", product.Price.Currency)
+		}
+		if i > 0 {
+			previous := tiers[i-1]
+			if previous.MaxQuantity == nil || *previous.MaxQuantity >= tier.MinQuantity {
+				return nil, fmt.Errorf("tier starting at %d overlaps the tier starting at %d", tier.MinQuantity, previous.MinQuantity)
+			}
+		}
+	}
+
+	if err := s.tierRepo.Replace(productID, tiers); err != nil {
+		return nil, err
+	}
+	return tiers, nil
+}
+
+// Quote prices a cart. Each line is sold at the lowest of its regular price,
+// its sale price and the unit price of the tier its quantity falls in, where
+// the quantity counts every line of the product; variants without tiers of
+// their own use their parent's. The running discount rules, and the rule of
+// the coupon code when one is given, then take money off the lines, and each
+// line carries the tax of its category. With a price list or currency, every
+// price is resolved there the way product reads resolve it.
+func (s *pricingService) Quote(items []models.QuoteItem, couponCode string, priceQuery models.PriceQuery) (*models.Quote, error) {
+	if len(items) == 0 {
+		return nil, errors.New("a quote needs at least one item")
+	}
+
+	var pricing *models.PriceContext
+	if priceQuery.IsSet() {
+		var err error
+		if pricing, err = resolvePricing(s.priceListRepo, priceQuery); err != nil {
+			return nil, err
+		}
+	}
+
+	quantities := make(map[uint]int)
+	for _, item := range items {
+		if item.Quantity < 1 {
+			return nil, fmt.Errorf("quantity of product %d must be at least 1", item.ProductID)
+		}
+		quantities[item.ProductID] += item.Quantity
+	}
+
+	quote := &models.Quote{
+		Lines:            make([]models.QuoteLine, 0, len(items)),
+		Discounts:        []models.AppliedDiscount{},
+		PricesIncludeTax: s.pricesIncludeTax,
+	}
+	if pricing != nil && pricing.PriceList != nil {
+		quote.PriceListID = &pricing.PriceList.ID
+	}
+	products := make(map[uint]*models.Product)
+	tiers := make(map[uint][]models.PriceTier)
+	lineProducts := make([]*models.Product, 0, len(items))
+	for i, item := range items {
+		product, ok := products[item.ProductID]
+		if !ok {
+			var err error
+			if product, err = s.productRepo.GetByID(item.ProductID); err != nil {
+				return nil, err
+			}
+			if !product.IsActive {
+				return nil, fmt.Errorf("product %d is not for sale", product.ID)
+			}
+			// Only variants are sold, never their parent
+			if len(product.Variants) > 0 {
+				return nil, fmt.Errorf("%w: %d", models.ErrProductHasVariants, product.ID)
+			}
+			tiers[product.ID] = product.PriceTiers
+			if len(product.PriceTiers) == 0 && product.ParentID != nil {
+				if tiers[product.ID], err = s.tierRepo.List(*product.ParentID); err != nil {
+					return nil, err
+				}
+			}
+			if pricing != nil {
+				if err := s.resolveQuotePrices(product, tiers[product.ID], pricing); err != nil {
+					return nil, err
+				}
+			}
+			products[item.ProductID] = product
+		}
+
+		regular, sale := product.Price, product.SalePrice
+		if pricing != nil {
+			regular, sale = product.ResolvedPrice.Price, product.ResolvedPrice.SalePrice
+		}
+		if i == 0 {
+			quote.Subtotal = models.NewMoney(0, regular.Currency)
+		} else if regular.Currency != quote.Subtotal.Currency {
+			return nil, fmt.Errorf("product %d is priced in %s, not %s like the rest of the quote",
+				product.ID, regular.Currency, quote.Subtotal.Currency)
+		}
+
+		line := models.QuoteLine{
+			ProductID:        product.ID,
+			SKU:              product.SKU,
+			Name:             product.Name,
+			Quantity:         item.Quantity,
+			RegularUnitPrice: regular,
+			UnitPrice:        regular,
+			PriceSource:      models.QuotePriceSourceRegular,
+		}
+		if sale != nil && sale.Currency == regular.Currency && sale.Amount < line.UnitPrice.Amount {
+			line.UnitPrice = *sale
+			line.PriceSource = models.QuotePriceSourceSale
+		}
+		if tier := models.TierFor(tiers[product.ID], quantities[product.ID]); tier != nil {
+			unitPrice := tier.UnitPrice
+			if pricing != nil && tier.ResolvedUnitPrice != nil {
+				unitPrice = *tier.ResolvedUnitPrice
+			}
+			if unitPrice.Currency == regular.Currency && unitPrice.Amount < line.UnitPrice.Amount {
+				line.UnitPrice = unitPrice
+				line.PriceSource = models.QuotePriceSourceTier
+				line.Tier = tier
+			}
+		}
+		line.LineTotal = line.UnitPrice.Mul(int64(item.Quantity))
+
+		quote.Subtotal.Amount += line.LineTotal.Amount
+		quote.Lines = append(quote.Lines, line)
+		lineProducts = append(lineProducts, product)
+	}
+
+	if err := s.applyDiscounts(quote, lineProducts, couponCode); err != nil {
+		return nil, err
+	}
+	if err := s.applyTaxes(quote, lineProducts); err != nil {
+		return nil, err
+	}
+	return quote, nil
+}
+
+// resolveQuotePrices resolves a product's price, sale price and tier prices in
+// a price list or currency, through the same resolution as product reads
+func (s *pricingService) resolveQuotePrices(product *models.Product, tiers []models.PriceTier,
+	pricing *models.PriceContext) error {
+	resolved := []models.Product{*product}
+	if err := s.productRepo.AttachPrices(resolved, pricing); err != nil {
+		return err
+	}
+	*product = resolved[0]
+	if product.ResolvedPrice == nil {
+		return fmt.Errorf("%w: product %d has no price in %s", models.ErrInvalidPriceQuery, product.ID, pricing.Currency)
+	}
+	// Tiers inherited from the parent are not attached to the product
+	for i := range tiers {
+		if unitPrice, ok := pricing.Convert(tiers[i].UnitPrice); ok {
+			tiers[i].ResolvedUnitPrice = &unitPrice
+		}
+	}
+	return nil
+}
+
+// Redeem quotes a cart again and records that the order with the reference
+// used the discounts applied to it, so that usage limits hold. The quote
+// returned is the one the order should charge; redeeming the reference again
+// returns the same quote without counting the discounts twice.
+func (s *pricingService) Redeem(reference string, items []models.QuoteItem, couponCode string,
+	priceQuery models.PriceQuery) (*models.Quote, error) {
+	reference = strings.TrimSpace(reference)
+	if reference == "" {
+		return nil, errors.New("an order reference is required")
+	}
+
+	if stored, err := s.discountRepo.GetRedemption(reference); err != nil || stored != nil {
+		return stored, err
+	}
+
+	quote, err := s.Quote(items, couponCode, priceQuery)
+	if err != nil {
+		return nil, err
+	}
+
+	redemptions := make([]models.DiscountRedemption, 0, len(quote.Discounts))
+	for _, discount := range quote.Discounts {
+		redemptions = append(redemptions, models.DiscountRedemption{RuleID: discount.RuleID, Amount: discount.Amount})
+	}
+	if err := s.discountRepo.Redeem(reference, quote, redemptions); err != nil {
+		// A concurrent request for the same order got there first
+		if errors.Is(err, models.ErrDiscountAlreadyRedeemed) {
+			if stored, lookupErr := s.discountRepo.GetRedemption(reference); lookupErr == nil && stored != nil {
+				return stored, nil
+			}
+		}
+		return nil, err
+	}
+	return quote, nil
+}
+
+// applyDiscounts runs the discount rules over a priced quote, highest
+// priority first, each taking money off what the rules before it left to pay
+func (s *pricingService) applyDiscounts(quote *models.Quote, products []*models.Product, couponCode string) error {
+	now := time.Now().UTC()
+	rules, err := s.discountRepo.Running(now)
+	if err != nil {
+		return err
+	}
+
+	var coupon *models.DiscountRule
+	if code := strings.ToUpper(strings.TrimSpace(couponCode)); code != "" {
+		coupon, err = s.discountRepo.GetByCoupon(code)
+		if errors.Is(err, models.ErrDiscountRuleNotFound) {
+			return models.ErrCouponInvalid
+		}
+		if err != nil {
+			return err
+		}
+		if !coupon.IsRunning(now) {
+			return models.ErrCouponInvalid
+		}
+		quote.CouponCode = code
+		rules = append(rules, *coupon)
+		sort.SliceStable(rules, func(i, j int) bool {
+			if rules[i].Priority != rules[j].Priority {
+				return rules[i].Priority > rules[j].Priority
+			}
+			return rules[i].ID < rules[j].ID
+		})
+	}
+
+	currency := quote.Subtotal.Currency
+	remaining := make([]int64, len(quote.Lines))
+	for i, line := range quote.Lines {
+		remaining[i] = line.LineTotal.Amount
+	}
+	ancestors := make(map[uint]map[uint]bool)
+	for _, rule := range rules {
+		isCoupon := coupon != nil && rule.ID == coupon.ID
+
+		if rule.MinBasket.Amount > 0 &&
+			(rule.MinBasket.Currency != currency || quote.Subtotal.Amount < rule.MinBasket.Amount) {
+			if isCoupon {
+				return fmt.Errorf("%w: the cart must come to at least %s %s",
+					models.ErrCouponNotApplicable, rule.MinBasket, rule.MinBasket.Currency)
+			}
+			continue
+		}
+		if rule.Type == models.DiscountTypeFixed && rule.Value.Currency != currency {
+			if isCoupon {
+				return fmt.Errorf("%w: it is for carts in %s", models.ErrCouponNotApplicable, rule.Value.Currency)
+			}
+			continue
+		}
+
+		var matching []int
+		var lines []models.DiscountableLine
+		for i, product := range products {
+			matches, err := s.discountMatches(&rule, product, ancestors)
+			if err != nil {
+				return err
+			}
+			if !matches {
+				continue
+			}
+			matching = append(matching, i)
+			lines = append(lines, models.DiscountableLine{
+				UnitPrice: quote.Lines[i].UnitPrice.Amount,
+				Quantity:  quote.Lines[i].Quantity,
+				Remaining: remaining[i],
+			})
+		}
+
+		applied := models.AppliedDiscount{
+			RuleID: rule.ID,
+			Name:   rule.Name,
+			Type:   rule.Type,
+			Amount: models.NewMoney(0, currency),
+		}
+		if rule.CouponCode != nil {
+			applied.CouponCode = *rule.CouponCode
+		}
+		for k, amount := range rule.Allocate(lines) {
+			if amount <= 0 {
+				continue
+			}
+			i := matching[k]
+			remaining[i] -= amount
+			applied.Amount.Amount += amount
+
+			lineDiscount := applied
+			lineDiscount.Amount = models.NewMoney(amount, currency)
+			quote.Lines[i].Discounts = append(quote.Lines[i].Discounts, lineDiscount)
+		}
+		if applied.Amount.Amount == 0 {
+			if isCoupon {
+				return fmt.Errorf("%w: nothing in the cart qualifies", models.ErrCouponNotApplicable)
+			}
+			continue
+		}
+		quote.Discounts = append(quote.Discounts, applied)
+	}
+
+	quote.DiscountTotal = models.NewMoney(0, currency)
+	for i := range quote.Lines {
+		line := &quote.Lines[i]
+		line.NetTotal = models.NewMoney(remaining[i], currency)
+		line.Discount = models.NewMoney(line.LineTotal.Amount-remaining[i], currency)
+		quote.DiscountTotal.Amount += line.Discount.Amount
+	}
+	return nil
+}
+
+// discountMatches reports whether a discount rule covers a product. ancestors
+// caches the categories above each category seen so far.
+func (s *pricingService) discountMatches(rule *models.DiscountRule, product *models.Product,
+	ancestors map[uint]map[uint]bool) (bool, error) {
+	switch rule.Scope {
+	case models.DiscountScopeCart:
+		return true, nil
+	case models.DiscountScopeProduct:
+		return rule.TargetID != nil &&
+			(*rule.TargetID == product.ID || (product.ParentID != nil && *rule.TargetID == *product.ParentID)), nil
+	case models.DiscountScopeBrand:
+		return rule.TargetID != nil && product.BrandID != nil && *rule.TargetID == *product.BrandID, nil
+	case models.DiscountScopeCategory:
+		if rule.TargetID == nil {
+			return false, nil
+		}
+		above, ok := ancestors[product.CategoryID]
+		if !ok {
+			categories, err := s.categoryRepo.Ancestors(product.CategoryID)
+			if err != nil {
+				return false, err
+			}
+			above = make(map[uint]bool, len(categories))
+			for _, category := range categories {
+				above[category.ID] = true
+			}
+			ancestors[product.CategoryID] = above
+		}
+		return above[*rule.TargetID], nil
+	}
+	return false, nil
+}
+
+// applyTaxes works out the tax on what is left to pay on each line, at the
+// rate of the line's category or the nearest category above it with one
+func (s *pricingService) applyTaxes(quote *models.Quote, products []*models.Product) error {
+	currency := quote.Subtotal.Currency
+	quote.TaxTotal = models.NewMoney(0, currency)
+	rates := make(map[uint]float64)
+	for i, product := range products {
+		rate, ok := rates[product.CategoryID]
+		if !ok {
+			categories, err := s.categoryRepo.Ancestors(product.CategoryID)
+			if err != nil {
+				return err
+			}
+			rate = s.defaultTaxRate
+			for _, category := range categories {
+				if category.TaxRate != nil {
+					rate = *category.TaxRate
+				}
+			}
+			rates[product.CategoryID] = rate
+		}
+
+		line := &quote.Lines[i]
+		line.TaxRate = rate
+		line.Tax = models.TaxOn(line.NetTotal, rate, s.pricesIncludeTax)
+		quote.TaxTotal.Amount += line.Tax.Amount
+	}
+
+	quote.Total = models.NewMoney(quote.Subtotal.Amount-quote.DiscountTotal.Amount, currency)
+	if !s.pricesIncludeTax {
+		quote.Total.Amount += quote.TaxTotal.Amount
+	}
+	return nil
+}
@@ -3,39 +3,165 @@ package service
 
 import (
 	"errors"
+	"fmt"
+	"log"
+	"strings"
 
 	"phone-accessories/internal/models"
+	"phone-accessories/internal/notification"
 	"phone-accessories/internal/repository"
 )
 
 type ProductService interface {
 	CreateProduct(product *models.Product) error
+	CreateVariant(parentID uint, variant *models.Product) error
 	GetProductByID(id uint) (*models.Product, error)
+	GetProductBySlug(slug string) (*models.Product, error)
 	UpdateProduct(product *models.Product) error
 	DeleteProduct(id uint) error
 	ListProducts(filter models.ProductFilter) (*models.PaginatedResponse, error)
-	UpdateStock(id uint, quantity int) error
+	PriceProduct(product *models.Product, query models.PriceQuery) error
+	UpdateStock(change models.StockChange) (*models.StockMovement, error)
+	StockChanged(movements ...models.StockMovement)
+	GetStockHistory(id uint, filter models.StockMovementFilter) (*models.PaginatedResponse, error)
 }
 
 type productService struct {
-	repo repository.ProductRepository
+	repo          repository.ProductRepository
+	movementRepo  repository.StockMovementRepository
+	attributeRepo repository.AttributeRepository
+	brandRepo     repository.BrandRepository
+	priceListRepo repository.PriceListRepository
+	notifier      *notification.Dispatcher
 }
 
-func NewProductService(repo repository.ProductRepository) ProductService {
-	return &productService{repo: repo}
+func NewProductService(repo repository.ProductRepository, movementRepo repository.StockMovementRepository,
+	attributeRepo repository.AttributeRepository, brandRepo repository.BrandRepository,
+	priceListRepo repository.PriceListRepository, notifier *notification.Dispatcher) ProductService {
+	return &productService{repo: repo, movementRepo: movementRepo, attributeRepo: attributeRepo,
+		brandRepo: brandRepo, priceListRepo: priceListRepo, notifier: notifier}
 }
 
 func (s *productService) CreateProduct(product *models.Product) error {
+	if product.ParentID != nil {
+		return s.CreateVariant(*product.ParentID, product)
+	}
+	if len(product.VariantOptions) > 0 {
+		return errors.New("only variants carry option values")
+	}
+	if err := validateOptions(product.Options); err != nil {
+		return err
+	}
+	product.IsBundle = len(product.Components) > 0
+	if product.IsBundle {
+		if len(product.Options) > 0 {
+			return errors.New("bundles cannot have variants")
+		}
+		if product.StockLevel != 0 {
+			return models.ErrProductIsBundle
+		}
+		if err := validateComponents(product); err != nil {
+			return err
+		}
+	}
+
+	return s.createProduct(product)
+}
+
+// CreateVariant adds a variant to a parent product. The variant's option
+// values must match the parent's options; fields it leaves empty are taken
+// from the parent.
+func (s *productService) CreateVariant(parentID uint, variant *models.Product) error {
+	parent, err := s.repo.GetByID(parentID)
+	if err != nil {
+		return err
+	}
+	if parent.ParentID != nil {
+		return errors.New("a variant cannot have variants of its own")
+	}
+	if parent.IsBundle {
+		return errors.New("bundles cannot have variants")
+	}
+	if len(variant.Components) > 0 {
+		return errors.New("a variant cannot be a bundle")
+	}
+	// Bundles sell the product itself, which would stop holding stock
+	inBundle, err := s.repo.InBundle(parent.ID)
+	if err != nil {
+		return err
+	}
+	if inBundle {
+		return fmt.Errorf("%w: add its variants to the bundles instead", models.ErrProductInBundle)
+	}
+	if len(parent.Options) == 0 {
+		return errors.New("define the parent product's options before adding variants")
+	}
+	// Once a product has variants its own stock can no longer be changed
+	if parent.StockLevel != 0 || parent.ReservedLevel != 0 {
+		return errors.New("parent product still holds stock; move it to its variants first")
+	}
+	if len(variant.Options) > 0 {
+		return errors.New("options are defined on the parent product")
+	}
+	if err := s.checkVariantOptions(parent, variant.VariantOptions, 0); err != nil {
+		return err
+	}
+
+	variant.ParentID = &parent.ID
+	variant.IsBundle = false
+	variant.CategoryID = parent.CategoryID
+	if variant.Name == "" {
+		variant.Name = variantName(parent, variant.VariantOptions)
+	}
+	if variant.Description == "" {
+		variant.Description = parent.Description
+	}
+	if variant.Price.IsZero() {
+		variant.Price = parent.Price
+	}
+	if variant.ImageURL == "" {
+		variant.ImageURL = parent.ImageURL
+	}
+	if variant.Attributes == nil {
+		variant.Attributes = parent.Attributes
+	}
+	if variant.BrandID == nil {
+		variant.BrandID = parent.BrandID
+	}
+	if variant.AvailabilityPolicy == "" {
+		variant.AvailabilityPolicy = parent.AvailabilityPolicy
+		variant.BackorderLimit = parent.BackorderLimit
+		variant.ExpectedShipDate = parent.ExpectedShipDate
+	}
+
+	return s.createProduct(variant)
+}
+
+func (s *productService) createProduct(product *models.Product) error {
 	// Add validation logic here if needed
 	if product.Name == "" {
 		return errors.New("product name is required")
 	}
-	if product.Price <= 0 {
+	if product.Price.Amount <= 0 {
 		return errors.New("product price must be greater than zero")
 	}
 	if product.SKU == "" {
 		return errors.New("product SKU is required")
 	}
+	if product.ReorderPoint < 0 || product.ReorderQuantity < 0 {
+		return errors.New("product reorder point and quantity must not be negative")
+	}
+	if err := validateAvailability(product); err != nil {
+		return err
+	}
+	if err := s.checkBrand(product); err != nil {
+		return err
+	}
+	if err := s.checkAttributes(product); err != nil {
+		return err
+	}
+	// Units can only be held through the reservation endpoints
+	product.ReservedLevel = 0
 	
 	return s.repo.Create(product)
 }
@@ -44,14 +170,92 @@ func (s *productService) GetProductByID(id uint) (*models.Product, error) {
 	return s.repo.GetByID(id)
 }
 
+// GetProductBySlug returns the product with the slug. A product found through
+// one of its old slugs comes back with its current slug, which differs from
+// the one asked for.
+func (s *productService) GetProductBySlug(slug string) (*models.Product, error) {
+	return s.repo.GetBySlug(slug)
+}
+
 func (s *productService) UpdateProduct(product *models.Product) error {
 	// Add validation logic here if needed
 	if product.Name == "" {
 		return errors.New("product name is required")
 	}
-	if product.Price <= 0 {
+	if product.Price.Amount <= 0 {
 		return errors.New("product price must be greater than zero")
 	}
+	if product.ReorderPoint < 0 || product.ReorderQuantity < 0 {
+		return errors.New("product reorder point and quantity must not be negative")
+	}
+
+	current, err := s.repo.GetByID(product.ID)
+	if err != nil {
+		return err
+	}
+	// Stock levels only change through UpdateStock and reservations; any sent
+	// here are ignored, and the product returned carries the stored ones
+	product.StockLevel = current.StockLevel
+	product.ReservedLevel = current.ReservedLevel
+	product.AvailableQuantity = current.AvailableQuantity
+	// Whether a product is a bundle is settled when it is created
+	product.IsBundle = current.IsBundle
+	if product.IsBundle {
+		if len(product.Options) > 0 {
+			return errors.New("bundles cannot have variants")
+		}
+		if product.Components != nil {
+			if err := validateComponents(product); err != nil {
+				return err
+			}
+		}
+	} else if len(product.Components) > 0 {
+		return errors.New("only bundles have components; create a new product for the bundle")
+	}
+	if err := validateAvailability(product); err != nil {
+		return err
+	}
+	if current.ParentID != nil {
+		if len(product.Options) > 0 {
+			return errors.New("options are defined on the parent product")
+		}
+		product.Options = nil
+		parent, err := s.repo.GetByID(*current.ParentID)
+		if err != nil {
+			return err
+		}
+		if err := s.checkVariantOptions(parent, product.VariantOptions, product.ID); err != nil {
+			return err
+		}
+		// Variants are always listed in their parent's category
+		product.CategoryID = parent.CategoryID
+	} else {
+		if len(product.VariantOptions) > 0 {
+			return errors.New("only variants carry option values")
+		}
+		if product.Options != nil {
+			if err := validateOptions(product.Options); err != nil {
+				return err
+			}
+			if len(current.Variants) > 0 && len(product.Options) == 0 {
+				return errors.New("cannot remove the options of a product that has variants")
+			}
+			// Every existing variant must still fit the new options
+			updated := *current
+			updated.Options = product.Options
+			for _, variant := range current.Variants {
+				if err := s.checkVariantOptions(&updated, variant.VariantOptions, variant.ID); err != nil {
+					return fmt.Errorf("variant %s: %v", variant.SKU, err)
+				}
+			}
+		}
+	}
+	if err := s.checkBrand(product); err != nil {
+		return err
+	}
+	if err := s.checkAttributes(product); err != nil {
+		return err
+	}
 	
 	return s.repo.Update(product)
 }
@@ -61,10 +265,229 @@ func (s *productService) DeleteProduct(id uint) error {
 }
 
 func (s *productService) ListProducts(filter models.ProductFilter) (*models.PaginatedResponse, error) {
+	if err := preparePriceFilter(s.priceListRepo, &filter); err != nil {
+		return nil, err
+	}
 	return s.repo.List(filter)
 }
 
-func (s *productService) UpdateStock(id uint, quantity int) error {
-	return s.repo.UpdateStock(id, quantity)
+// PriceProduct resolves the price of a product, and of its variants, in the
+// price list or currency asked for
+func (s *productService) PriceProduct(product *models.Product, query models.PriceQuery) error {
+	pricing, err := resolvePricing(s.priceListRepo, query)
+	if err != nil {
+		return err
+	}
+	products := []models.Product{*product}
+	if err := s.repo.AttachPrices(products, pricing); err != nil {
+		return err
+	}
+	*product = products[0]
+	return nil
 }
 
+func (s *productService) UpdateStock(change models.StockChange) (*models.StockMovement, error) {
+	if change.Delta == 0 {
+		return nil, errors.New("stock change quantity must not be zero")
+	}
+	if change.Reason == "" {
+		change.Reason = models.StockReasonAdjustment
+	}
+	if !change.Reason.IsValid() {
+		return nil, fmt.Errorf("invalid stock change reason %q", change.Reason)
+	}
+	if !change.Reason.IsPublic() {
+		return nil, fmt.Errorf("stock change reason %q is only used by transfers and stock takes", change.Reason)
+	}
+	if change.UnitCost != nil && (change.Delta < 0 || *change.UnitCost < 0) {
+		return nil, errors.New("unit cost must not be negative and only applies to incoming stock")
+	}
+
+	movement, err := s.repo.UpdateStock(change)
+	if err != nil {
+		return nil, err
+	}
+
+	s.StockChanged(*movement)
+	return movement, nil
+}
+
+// StockChanged runs what follows committed stock movements, such as low-stock
+// alerts, for changes made here and for those made by other services, such as
+// confirmed reservations, batch adjustments, stock takes and goods received on
+// a purchase order
+func (s *productService) StockChanged(movements ...models.StockMovement) {
+	for i := range movements {
+		movement := &movements[i]
+		// Selling a bundle draws down the stock of its components
+		if len(movement.Components) > 0 {
+			s.StockChanged(movement.Components...)
+			continue
+		}
+		s.notifyLowStock(movement)
+	}
+}
+
+// notifyLowStock raises a low-stock alert when a movement took its product
+// below the reorder point. The stock change is already committed by then, so
+// failing to look the product up only skips the alert.
+func (s *productService) notifyLowStock(movement *models.StockMovement) {
+	product, err := s.repo.GetByID(movement.ProductID)
+	if err != nil {
+		log.Printf("Skipped low-stock check for product %d: %v", movement.ProductID, err)
+		return
+	}
+	if event, ok := lowStockEvent(product, movement); ok {
+		s.notifier.LowStock(event)
+	}
+}
+
+func (s *productService) GetStockHistory(id uint, filter models.StockMovementFilter) (*models.PaginatedResponse, error) {
+	if _, err := s.repo.GetByID(id); err != nil {
+		return nil, err
+	}
+	if filter.From != nil && filter.To != nil && filter.To.Before(*filter.From) {
+		return nil, errors.New("history end date must not be before its start date")
+	}
+
+	return s.movementRepo.List(id, filter)
+}
+
+// checkAttributes validates a product's attributes against the attribute
+// schema of its category
+func (s *productService) checkAttributes(product *models.Product) error {
+	schema, err := s.attributeRepo.EffectiveSchema(product.CategoryID)
+	if err != nil {
+		return err
+	}
+	return validateAttributes(schema, product.Attributes)
+}
+
+// checkBrand checks that the brand a product is sold under exists
+func (s *productService) checkBrand(product *models.Product) error {
+	if product.BrandID == nil {
+		return nil
+	}
+	_, err := s.brandRepo.GetByID(*product.BrandID)
+	return err
+}
+
+// validateComponents checks the components of a bundle
+func validateComponents(bundle *models.Product) error {
+	if len(bundle.Components) == 0 {
+		return errors.New("a bundle must have at least one component")
+	}
+	seen := make(map[uint]bool, len(bundle.Components))
+	for _, component := range bundle.Components {
+		if component.ComponentID == 0 {
+			return errors.New("bundle component product ID is required")
+		}
+		if bundle.ID != 0 && component.ComponentID == bundle.ID {
+			return errors.New("a bundle cannot contain itself")
+		}
+		if seen[component.ComponentID] {
+			return fmt.Errorf("product %d is listed twice in the bundle", component.ComponentID)
+		}
+		seen[component.ComponentID] = true
+		if component.Quantity <= 0 {
+			return errors.New("bundle component quantity must be greater than zero")
+		}
+	}
+	return nil
+}
+
+// validateAvailability checks a product's availability policy, defaulting it
+// to deny so products only sell what is in stock unless told otherwise
+func validateAvailability(product *models.Product) error {
+	// A bundle is only available as its components are, so it never sells
+	// ahead of stock itself
+	if product.IsBundle {
+		product.AvailabilityPolicy = models.AvailabilityPolicyDeny
+		product.BackorderLimit = nil
+		product.ExpectedShipDate = nil
+	}
+	if product.AvailabilityPolicy == "" {
+		product.AvailabilityPolicy = models.AvailabilityPolicyDeny
+	}
+	if !product.AvailabilityPolicy.IsValid() {
+		return fmt.Errorf("invalid availability policy %q", product.AvailabilityPolicy)
+	}
+	if product.BackorderLimit != nil && *product.BackorderLimit < 0 {
+		return errors.New("product backorder limit must not be negative")
+	}
+	if product.AvailabilityPolicy == models.AvailabilityPolicyPreorder && product.ExpectedShipDate == nil {
+		return errors.New("pre-order products require an expected ship date")
+	}
+	return nil
+}
+
+// validateOptions checks the option axes defined on a parent product
+func validateOptions(options []models.ProductOption) error {
+	names := make(map[string]bool, len(options))
+	for _, option := range options {
+		if option.Name == "" {
+			return errors.New("product option name is required")
+		}
+		if names[option.Name] {
+			return fmt.Errorf("product option %q is defined more than once", option.Name)
+		}
+		names[option.Name] = true
+
+		if len(option.Values) == 0 {
+			return fmt.Errorf("product option %q must have at least one value", option.Name)
+		}
+		values := make(map[string]bool, len(option.Values))
+		for _, value := range option.Values {
+			if value == "" || values[value] {
+				return fmt.Errorf("product option %q has an empty or duplicate value", option.Name)
+			}
+			values[value] = true
+		}
+	}
+	return nil
+}
+
+// checkVariantOptions checks that a variant has exactly one allowed value for
+// each of its parent's options and that no other variant has the same values
+func (s *productService) checkVariantOptions(parent *models.Product, values models.JSON, variantID uint) error {
+	if len(values) != len(parent.Options) {
+		return fmt.Errorf("variant must have a value for each of the options of product %d", parent.ID)
+	}
+	for _, option := range parent.Options {
+		value, ok := values[option.Name].(string)
+		if !ok {
+			return fmt.Errorf("variant is missing a value for option %q", option.Name)
+		}
+		allowed := false
+		for _, candidate := range option.Values {
+			if candidate == value {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			return fmt.Errorf("%q is not a value of option %q", value, option.Name)
+		}
+	}
+
+	taken, err := s.repo.VariantOptionsTaken(parent.ID, values, variantID)
+	if err != nil {
+		return err
+	}
+	if taken {
+		return errors.New("another variant already has these option values")
+	}
+	return nil
+}
+
+// variantName names a variant after its parent and option values, such as
+// "Coque Silicone iPhone 15 - Noir"
+func variantName(parent *models.Product, values models.JSON) string {
+	parts := make([]string, 0, len(parent.Options))
+	for _, option := range parent.Options {
+		if value, ok := values[option.Name].(string); ok {
+			parts = append(parts, value)
+		}
+	}
+	return parent.Name + " - " + strings.Join(parts, " / ")
+}
@@ -0,0 +1,123 @@
+// internal/service/product_service_test.go
+package service
+
+import (
+	"sort"
+	"testing"
+	"time"
+
+	"phone-accessories/internal/models"
+	"phone-accessories/internal/notification"
+	"phone-accessories/internal/repository"
+)
+
+// fakeProductRepository records the stock changes applied through it. Only
+// the methods the tests call are implemented.
+type fakeProductRepository struct {
+	repository.ProductRepository
+	products map[uint]*models.Product
+	changes  []models.StockChange
+}
+
+func (r *fakeProductRepository) UpdateStock(change models.StockChange) (*models.StockMovement, error) {
+	r.changes = append(r.changes, change)
+	return &models.StockMovement{ProductID: change.ProductID, Delta: change.Delta, Reason: change.Reason}, nil
+}
+
+func (r *fakeProductRepository) GetByID(id uint) (*models.Product, error) {
+	if product, ok := r.products[id]; ok {
+		return product, nil
+	}
+	return nil, models.ErrProductNotFound
+}
+
+// channelSink passes the events it receives to a channel
+type channelSink chan models.LowStockEvent
+
+func (s channelSink) NotifyLowStock(event models.LowStockEvent) error {
+	s <- event
+	return nil
+}
+
+func TestUpdateStockReason(t *testing.T) {
+	tests := []struct {
+		name       string
+		reason     models.StockReason
+		wantReason models.StockReason
+		wantErr    bool
+	}{
+		{name: "no reason is an adjustment", wantReason: models.StockReasonAdjustment},
+		{name: "restock", reason: models.StockReasonRestock, wantReason: models.StockReasonRestock},
+		{name: "damage", reason: models.StockReasonDamage, wantReason: models.StockReasonDamage},
+		{name: "unknown reason", reason: "lost", wantErr: true},
+		{name: "transfer is kept for transfers", reason: models.StockReasonTransfer, wantErr: true},
+		{name: "count is kept for stock takes", reason: models.StockReasonCount, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeProductRepository{}
+			service := &productService{repo: repo}
+
+			movement, err := service.UpdateStock(models.StockChange{ProductID: 1, Delta: 2, Reason: tt.reason})
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("UpdateStock() succeeded, want an error")
+				}
+				if len(repo.changes) != 0 {
+					t.Errorf("UpdateStock() applied %d changes, want none", len(repo.changes))
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("UpdateStock() error = %v", err)
+			}
+			if movement.Reason != tt.wantReason {
+				t.Errorf("reason = %q, want %q", movement.Reason, tt.wantReason)
+			}
+		})
+	}
+}
+
+func TestStockChanged(t *testing.T) {
+	repo := &fakeProductRepository{products: map[uint]*models.Product{
+		1: {ID: 1, SKU: "CASE-1", ReorderPoint: 5},
+		2: {ID: 2, SKU: "CABLE-1", ReorderPoint: 5},
+		3: {ID: 3, SKU: "GLASS-1", ReorderPoint: 5},
+	}}
+	events := make(channelSink, 10)
+	service := &productService{repo: repo, notifier: notification.NewDispatcher(events)}
+
+	service.StockChanged(
+		// Falls below the reorder point
+		models.StockMovement{ProductID: 1, Delta: -3, ResultingLevel: 4},
+		// A bundle sale alerts on the components it took below their reorder point
+		models.StockMovement{ProductID: 10, Delta: -1, Components: []models.StockMovement{
+			{ProductID: 2, Delta: -2, ResultingLevel: 3},
+			{ProductID: 3, Delta: -1, ResultingLevel: 8},
+		}},
+		// Already below the reorder point
+		models.StockMovement{ProductID: 1, Delta: -1, ResultingLevel: 3},
+		// Unknown products are skipped
+		models.StockMovement{ProductID: 99, Delta: -1, ResultingLevel: 0},
+	)
+
+	var got []string
+	for len(got) < 2 {
+		select {
+		case event := <-events:
+			got = append(got, event.SKU)
+		case <-time.After(time.Second):
+			t.Fatalf("low-stock events = %v, want [CABLE-1 CASE-1]", got)
+		}
+	}
+	sort.Strings(got)
+	if got[0] != "CABLE-1" || got[1] != "CASE-1" {
+		t.Errorf("low-stock events = %v, want [CABLE-1 CASE-1]", got)
+	}
+	select {
+	case event := <-events:
+		t.Errorf("unexpected low-stock event for %s", event.SKU)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
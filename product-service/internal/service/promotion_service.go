@@ -0,0 +1,117 @@
+// internal/service/promotion_service.go
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"phone-accessories/internal/models"
+	"phone-accessories/internal/repository"
+)
+
+type PromotionService interface {
+	CreatePromotion(promotion *models.Promotion) error
+	GetPromotionByID(id uint) (*models.Promotion, error)
+	UpdatePromotion(promotion *models.Promotion) error
+	DeletePromotion(id uint) error
+	ListPromotions(filter models.PromotionFilter) ([]models.Promotion, error)
+}
+
+type promotionService struct {
+	repo         repository.PromotionRepository
+	productRepo  repository.ProductRepository
+	categoryRepo repository.CategoryRepository
+	brandRepo    repository.BrandRepository
+}
+
+func NewPromotionService(repo repository.PromotionRepository, productRepo repository.ProductRepository,
+	categoryRepo repository.CategoryRepository, brandRepo repository.BrandRepository) PromotionService {
+	return &promotionService{repo: repo, productRepo: productRepo, categoryRepo: categoryRepo, brandRepo: brandRepo}
+}
+
+func (s *promotionService) CreatePromotion(promotion *models.Promotion) error {
+	if err := s.validatePromotion(promotion); err != nil {
+		return err
+	}
+
+	return s.repo.Create(promotion)
+}
+
+func (s *promotionService) GetPromotionByID(id uint) (*models.Promotion, error) {
+	return s.repo.GetByID(id)
+}
+
+func (s *promotionService) UpdatePromotion(promotion *models.Promotion) error {
+	if err := s.validatePromotion(promotion); err != nil {
+		return err
+	}
+
+	return s.repo.Update(promotion)
+}
+
+func (s *promotionService) DeletePromotion(id uint) error {
+	return s.repo.Delete(id)
+}
+
+func (s *promotionService) ListPromotions(filter models.PromotionFilter) ([]models.Promotion, error) {
+	if filter.Scope != "" && !filter.Scope.IsValid() {
+		return nil, fmt.Errorf("invalid promotion scope %q", filter.Scope)
+	}
+	return s.repo.List(filter)
+}
+
+func (s *promotionService) validatePromotion(promotion *models.Promotion) error {
+	promotion.Name = strings.TrimSpace(promotion.Name)
+	if promotion.Name == "" {
+		return errors.New("promotion name is required")
+	}
+	if !promotion.Scope.IsValid() {
+		return fmt.Errorf("invalid promotion scope %q", promotion.Scope)
+	}
+	if !promotion.Type.IsValid() {
+		return fmt.Errorf("invalid promotion type %q", promotion.Type)
+	}
+
+	switch promotion.Type {
+	case models.PromotionTypeSalePrice:
+		// A sale price only makes sense for a product whose price it replaces
+		if promotion.Scope != models.PromotionScopeProduct {
+			return errors.New("sale price promotions can only target a product; use a percentage or fixed promotion for a category or brand")
+		}
+		if promotion.Value.Amount <= 0 {
+			return errors.New("sale price must be greater than zero")
+		}
+		promotion.Percentage = 0
+	case models.PromotionTypeFixed:
+		if promotion.Value.Amount <= 0 {
+			return errors.New("amount off must be greater than zero")
+		}
+		promotion.Percentage = 0
+	case models.PromotionTypePercentage:
+		if promotion.Percentage <= 0 || promotion.Percentage >= 100 {
+			return errors.New("percentage off must be between 0 and 100")
+		}
+		promotion.Value = models.Money{Currency: models.DefaultCurrency}
+	}
+
+	// Promotions without a start run from now on
+	if promotion.StartsAt.IsZero() {
+		promotion.StartsAt = time.Now().UTC()
+	}
+	if promotion.EndsAt != nil && !promotion.EndsAt.After(promotion.StartsAt) {
+		return errors.New("promotion must end after it starts")
+	}
+
+	var err error
+	switch promotion.Scope {
+	case models.PromotionScopeProduct:
+		_, err = s.productRepo.GetByID(promotion.TargetID)
+	case models.PromotionScopeCategory:
+		_, err = s.categoryRepo.GetByID(promotion.TargetID)
+	case models.PromotionScopeBrand:
+		_, err = s.brandRepo.GetByID(promotion.TargetID)
+	}
+	return err
+}
@@ -0,0 +1,133 @@
+// internal/service/purchase_order_service.go
+package service
+
+import (
+	"errors"
+	"fmt"
+
+	"phone-accessories/internal/models"
+	"phone-accessories/internal/repository"
+)
+
+type PurchaseOrderService interface {
+	CreatePurchaseOrder(order *models.PurchaseOrder) error
+	GetPurchaseOrderByID(id uint) (*models.PurchaseOrder, error)
+	UpdatePurchaseOrder(order *models.PurchaseOrder) error
+	ListPurchaseOrders(filter models.PurchaseOrderFilter) (*models.PaginatedResponse, error)
+	SendPurchaseOrder(id uint) (*models.PurchaseOrder, error)
+	CancelPurchaseOrder(id uint) (*models.PurchaseOrder, error)
+	ReceivePurchaseOrder(id uint, receipts []models.PurchaseOrderReceipt, actor string) (*models.PurchaseOrder, error)
+}
+
+type purchaseOrderService struct {
+	repo         repository.PurchaseOrderRepository
+	supplierRepo repository.SupplierRepository
+	productRepo  repository.ProductRepository
+	stockService ProductService
+}
+
+func NewPurchaseOrderService(repo repository.PurchaseOrderRepository, supplierRepo repository.SupplierRepository,
+	productRepo repository.ProductRepository, stockService ProductService) PurchaseOrderService {
+	return &purchaseOrderService{repo: repo, supplierRepo: supplierRepo, productRepo: productRepo, stockService: stockService}
+}
+
+func (s *purchaseOrderService) CreatePurchaseOrder(order *models.PurchaseOrder) error {
+	if err := s.validate(order); err != nil {
+		return err
+	}
+
+	order.Status = models.PurchaseOrderStatusDraft
+	order.SentAt = nil
+	for i := range order.Lines {
+		order.Lines[i].QuantityReceived = 0
+	}
+	return s.repo.Create(order)
+}
+
+func (s *purchaseOrderService) GetPurchaseOrderByID(id uint) (*models.PurchaseOrder, error) {
+	return s.repo.GetByID(id)
+}
+
+func (s *purchaseOrderService) UpdatePurchaseOrder(order *models.PurchaseOrder) error {
+	if err := s.validate(order); err != nil {
+		return err
+	}
+
+	return s.repo.Update(order)
+}
+
+func (s *purchaseOrderService) ListPurchaseOrders(filter models.PurchaseOrderFilter) (*models.PaginatedResponse, error) {
+	return s.repo.List(filter)
+}
+
+func (s *purchaseOrderService) SendPurchaseOrder(id uint) (*models.PurchaseOrder, error) {
+	return s.repo.UpdateStatus(id,
+		[]models.PurchaseOrderStatus{models.PurchaseOrderStatusDraft},
+		models.PurchaseOrderStatusSent)
+}
+
+func (s *purchaseOrderService) CancelPurchaseOrder(id uint) (*models.PurchaseOrder, error) {
+	// A partially received order can be cancelled to stop waiting for the rest
+	return s.repo.UpdateStatus(id,
+		[]models.PurchaseOrderStatus{
+			models.PurchaseOrderStatusDraft,
+			models.PurchaseOrderStatusSent,
+			models.PurchaseOrderStatusPartiallyReceived,
+		},
+		models.PurchaseOrderStatusCancelled)
+}
+
+func (s *purchaseOrderService) ReceivePurchaseOrder(id uint, receipts []models.PurchaseOrderReceipt, actor string) (*models.PurchaseOrder, error) {
+	if len(receipts) == 0 {
+		return nil, errors.New("at least one received line is required")
+	}
+	for _, receipt := range receipts {
+		if receipt.LineID == 0 {
+			return nil, errors.New("received line ID is required")
+		}
+		if receipt.Quantity <= 0 {
+			return nil, errors.New("received quantity must be greater than zero")
+		}
+	}
+
+	order, movements, err := s.repo.Receive(id, receipts, actor)
+	if err != nil {
+		return nil, err
+	}
+	// Received goods are stock changes like any other once committed
+	s.stockService.StockChanged(movements...)
+	return order, nil
+}
+
+func (s *purchaseOrderService) validate(order *models.PurchaseOrder) error {
+	if order.SupplierID == 0 {
+		return errors.New("purchase order supplier is required")
+	}
+	supplier, err := s.supplierRepo.GetByID(order.SupplierID)
+	if err != nil {
+		return fmt.Errorf("purchase order supplier %d: %w", order.SupplierID, err)
+	}
+	if !supplier.IsActive {
+		return errors.New("cannot order from an inactive supplier")
+	}
+
+	if len(order.Lines) == 0 {
+		return errors.New("purchase order must contain at least one line")
+	}
+	for _, line := range order.Lines {
+		if line.QuantityOrdered <= 0 {
+			return errors.New("purchase order line quantity must be greater than zero")
+		}
+		if line.UnitCost < 0 {
+			return errors.New("purchase order line unit cost must not be negative")
+		}
+		product, err := s.productRepo.GetByID(line.ProductID)
+		if err != nil {
+			return fmt.Errorf("purchase order line product %d: %w", line.ProductID, err)
+		}
+		if product.IsBundle {
+			return fmt.Errorf("purchase order line product %d: %w", line.ProductID, models.ErrProductIsBundle)
+		}
+	}
+	return nil
+}
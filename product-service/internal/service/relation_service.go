@@ -0,0 +1,116 @@
+// internal/service/relation_service.go
+package service
+
+import (
+	"errors"
+	"fmt"
+
+	"phone-accessories/internal/models"
+	"phone-accessories/internal/repository"
+)
+
+type RelationService interface {
+	CreateRelation(relation *models.ProductRelation) error
+	UpdateRelation(productID, id uint, update models.ProductRelationUpdate) (*models.ProductRelation, error)
+	DeleteRelation(productID, id uint) error
+	ListRelated(productID uint, filter models.RelatedProductFilter) ([]models.ProductRelation, error)
+}
+
+type relationService struct {
+	repo        repository.RelationRepository
+	productRepo repository.ProductRepository
+}
+
+func NewRelationService(repo repository.RelationRepository, productRepo repository.ProductRepository) RelationService {
+	return &relationService{repo: repo, productRepo: productRepo}
+}
+
+func (s *relationService) CreateRelation(relation *models.ProductRelation) error {
+	if !relation.Type.IsValid() {
+		return fmt.Errorf("invalid relation type %q", relation.Type)
+	}
+	if relation.TargetID == relation.ProductID {
+		return errors.New("a product cannot be related to itself")
+	}
+	if _, err := s.productRepo.GetByID(relation.ProductID); err != nil {
+		return err
+	}
+	if _, err := s.productRepo.GetByID(relation.TargetID); err != nil {
+		return fmt.Errorf("related product %d: %w", relation.TargetID, err)
+	}
+
+	return s.repo.Create(relation)
+}
+
+func (s *relationService) UpdateRelation(productID, id uint, update models.ProductRelationUpdate) (*models.ProductRelation, error) {
+	relation, err := s.repo.GetByID(productID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if update.Type != nil {
+		if !update.Type.IsValid() {
+			return nil, fmt.Errorf("invalid relation type %q", *update.Type)
+		}
+		relation.Type = *update.Type
+	}
+	if update.Position != nil {
+		if *update.Position < 0 {
+			return nil, errors.New("relation position must not be negative")
+		}
+		relation.Position = *update.Position
+	}
+
+	if err := s.repo.Update(relation); err != nil {
+		return nil, err
+	}
+	return relation, nil
+}
+
+func (s *relationService) DeleteRelation(productID, id uint) error {
+	return s.repo.Delete(productID, id)
+}
+
+// ListRelated returns the products related to a product, in order. Unless the
+// filter asks for them, targets that are inactive or cannot be sold are left out.
+func (s *relationService) ListRelated(productID uint, filter models.RelatedProductFilter) ([]models.ProductRelation, error) {
+	if filter.Type != "" && !filter.Type.IsValid() {
+		return nil, fmt.Errorf("invalid relation type %q", filter.Type)
+	}
+	if _, err := s.productRepo.GetByID(productID); err != nil {
+		return nil, err
+	}
+
+	relations, err := s.repo.List(productID, filter.Type)
+	if err != nil {
+		return nil, err
+	}
+	related := make([]models.ProductRelation, 0, len(relations))
+	for _, relation := range relations {
+		if relation.Target == nil {
+			continue
+		}
+		if !filter.IncludeUnavailable && !isSellable(relation.Target) {
+			continue
+		}
+		related = append(related, relation)
+	}
+	return related, nil
+}
+
+// isSellable reports whether a product is active and can be sold now, from
+// stock or ahead of it; a parent product can be sold while any variant can
+func isSellable(product *models.Product) bool {
+	if !product.IsActive {
+		return false
+	}
+	if len(product.Variants) == 0 {
+		return product.AvailabilityStatus != models.AvailabilityStatusOutOfStock
+	}
+	for i := range product.Variants {
+		if product.Variants[i].IsActive && product.Variants[i].AvailabilityStatus != models.AvailabilityStatusOutOfStock {
+			return true
+		}
+	}
+	return false
+}
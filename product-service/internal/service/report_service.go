@@ -0,0 +1,71 @@
+// internal/service/report_service.go
+package service
+
+import (
+	"time"
+
+	"phone-accessories/internal/models"
+	"phone-accessories/internal/repository"
+)
+
+type ReportService interface {
+	InventoryValuation(asOf time.Time) (*models.InventoryValuation, error)
+}
+
+type reportService struct {
+	repo repository.ReportRepository
+}
+
+func NewReportService(repo repository.ReportRepository) ReportService {
+	return &reportService{repo: repo}
+}
+
+// InventoryValuation returns the FIFO cost value of the stock on hand at the
+// end of the given day, per product and per category
+func (s *reportService) InventoryValuation(asOf time.Time) (*models.InventoryValuation, error) {
+	day := time.Date(asOf.Year(), asOf.Month(), asOf.Day(), 0, 0, 0, 0, time.UTC)
+	lines, err := s.repo.InventoryValuation(day.AddDate(0, 0, 1))
+	if err != nil {
+		return nil, err
+	}
+
+	valuation := &models.InventoryValuation{
+		AsOf:       day,
+		Products:   make([]models.InventoryValuationLine, 0, len(lines)),
+		Categories: []models.InventoryValuationCategory{},
+	}
+	categories := make(map[uint]int)
+	for _, line := range lines {
+		line.AverageUnitCost = averageCost(line.Value, line.Quantity)
+		valuation.Products = append(valuation.Products, line)
+
+		// Lines arrive ordered by category, so categories keep that order
+		i, ok := categories[line.CategoryID]
+		if !ok {
+			i = len(valuation.Categories)
+			categories[line.CategoryID] = i
+			valuation.Categories = append(valuation.Categories, models.InventoryValuationCategory{
+				CategoryID:   line.CategoryID,
+				CategoryName: line.CategoryName,
+			})
+		}
+		valuation.Categories[i].Quantity += line.Quantity
+		valuation.Categories[i].Value += line.Value
+
+		valuation.TotalQuantity += line.Quantity
+		valuation.TotalValue += line.Value
+	}
+	return valuation, nil
+}
+
+// averageCost divides a value over a quantity, rounding half a minor unit away from zero
+func averageCost(value models.Cost, quantity int) models.Cost {
+	if quantity == 0 {
+		return 0
+	}
+	average := value / models.Cost(quantity)
+	if remainder := value % models.Cost(quantity); remainder*2 >= models.Cost(quantity) {
+		average++
+	}
+	return average
+}
@@ -0,0 +1,84 @@
+// internal/service/reservation_service.go
+package service
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"phone-accessories/internal/models"
+	"phone-accessories/internal/repository"
+)
+
+type ReservationService interface {
+	CreateReservation(reference string, items []models.ReservationItem, ttl time.Duration) (*models.Reservation, error)
+	GetReservation(id uint) (*models.Reservation, error)
+	ConfirmReservation(id uint) (*models.Reservation, error)
+	ReleaseReservation(id uint) (*models.Reservation, error)
+	ReleaseExpired() (int, error)
+}
+
+type reservationService struct {
+	repo         repository.ReservationRepository
+	stockService ProductService
+	defaultTTL   time.Duration
+	maxTTL       time.Duration
+}
+
+func NewReservationService(repo repository.ReservationRepository, stockService ProductService,
+	defaultTTL, maxTTL time.Duration) ReservationService {
+	return &reservationService{repo: repo, stockService: stockService, defaultTTL: defaultTTL, maxTTL: maxTTL}
+}
+
+func (s *reservationService) CreateReservation(reference string, items []models.ReservationItem, ttl time.Duration) (*models.Reservation, error) {
+	if len(items) == 0 {
+		return nil, errors.New("reservation must contain at least one item")
+	}
+	for _, item := range items {
+		if item.ProductID == 0 {
+			return nil, errors.New("reservation item product ID is required")
+		}
+		if item.Quantity <= 0 {
+			return nil, errors.New("reservation item quantity must be greater than zero")
+		}
+	}
+
+	if ttl <= 0 {
+		ttl = s.defaultTTL
+	}
+	if ttl > s.maxTTL {
+		return nil, fmt.Errorf("reservation TTL cannot exceed %s", s.maxTTL)
+	}
+
+	reservation := &models.Reservation{
+		Reference: reference,
+		ExpiresAt: time.Now().UTC().Add(ttl),
+		Items:     items,
+	}
+	if err := s.repo.Create(reservation); err != nil {
+		return nil, err
+	}
+	return reservation, nil
+}
+
+func (s *reservationService) GetReservation(id uint) (*models.Reservation, error) {
+	return s.repo.GetByID(id)
+}
+
+func (s *reservationService) ConfirmReservation(id uint) (*models.Reservation, error) {
+	reservation, movements, err := s.repo.Confirm(id)
+	if err != nil {
+		return nil, err
+	}
+
+	s.stockService.StockChanged(movements...)
+	return reservation, nil
+}
+
+func (s *reservationService) ReleaseReservation(id uint) (*models.Reservation, error) {
+	return s.repo.Release(id)
+}
+
+func (s *reservationService) ReleaseExpired() (int, error) {
+	return s.repo.ReleaseExpired(time.Now().UTC())
+}
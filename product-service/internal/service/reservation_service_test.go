@@ -0,0 +1,123 @@
+// internal/service/reservation_service_test.go
+package service
+
+import (
+	"testing"
+	"time"
+
+	"phone-accessories/internal/models"
+	"phone-accessories/internal/repository"
+)
+
+// fakeReservationRepository keeps reservations in memory. Only the methods
+// the tests call are implemented; the others panic through the nil interface.
+type fakeReservationRepository struct {
+	repository.ReservationRepository
+	created   []*models.Reservation
+	confirmed []models.StockMovement
+}
+
+func (r *fakeReservationRepository) Create(reservation *models.Reservation) error {
+	reservation.ID = uint(len(r.created) + 1)
+	reservation.Status = models.ReservationStatusHeld
+	r.created = append(r.created, reservation)
+	return nil
+}
+
+func (r *fakeReservationRepository) Confirm(id uint) (*models.Reservation, []models.StockMovement, error) {
+	if r.confirmed == nil {
+		return nil, nil, models.ErrReservationNotHeld
+	}
+	return &models.Reservation{ID: id, Status: models.ReservationStatusConfirmed}, r.confirmed, nil
+}
+
+// fakeStockService records the stock movements it is told about
+type fakeStockService struct {
+	ProductService
+	changed []models.StockMovement
+}
+
+func (s *fakeStockService) StockChanged(movements ...models.StockMovement) {
+	s.changed = append(s.changed, movements...)
+}
+
+func TestCreateReservation(t *testing.T) {
+	item := models.ReservationItem{ProductID: 1, Quantity: 2}
+
+	tests := []struct {
+		name    string
+		items   []models.ReservationItem
+		ttl     time.Duration
+		wantTTL time.Duration
+		wantErr bool
+	}{
+		{name: "default TTL", items: []models.ReservationItem{item}, wantTTL: 15 * time.Minute},
+		{name: "negative TTL takes the default", items: []models.ReservationItem{item}, ttl: -time.Minute, wantTTL: 15 * time.Minute},
+		{name: "requested TTL", items: []models.ReservationItem{item}, ttl: time.Hour, wantTTL: time.Hour},
+		{name: "longest TTL", items: []models.ReservationItem{item}, ttl: 2 * time.Hour, wantTTL: 2 * time.Hour},
+		{name: "TTL above the maximum", items: []models.ReservationItem{item}, ttl: 3 * time.Hour, wantErr: true},
+		{name: "no items", wantErr: true},
+		{name: "missing product", items: []models.ReservationItem{{Quantity: 1}}, wantErr: true},
+		{name: "zero quantity", items: []models.ReservationItem{{ProductID: 1}}, wantErr: true},
+		{name: "negative quantity", items: []models.ReservationItem{{ProductID: 1, Quantity: -1}}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeReservationRepository{}
+			service := NewReservationService(repo, nil, 15*time.Minute, 2*time.Hour)
+
+			before := time.Now().UTC()
+			reservation, err := service.CreateReservation("order-1", tt.items, tt.ttl)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("CreateReservation() succeeded, want an error")
+				}
+				if len(repo.created) > 0 {
+					t.Errorf("CreateReservation() held stock for a rejected reservation")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("CreateReservation() returned error: %v", err)
+			}
+			if len(repo.created) != 1 || reservation.Status != models.ReservationStatusHeld {
+				t.Fatalf("CreateReservation() did not hold the reservation")
+			}
+			if ttl := reservation.ExpiresAt.Sub(before); ttl < tt.wantTTL || ttl > tt.wantTTL+time.Minute {
+				t.Errorf("reservation expires after %s, want %s", ttl, tt.wantTTL)
+			}
+		})
+	}
+}
+
+func TestConfirmReservation(t *testing.T) {
+	sold := []models.StockMovement{
+		{ProductID: 1, Delta: -2, ResultingLevel: 3, Reason: models.StockReasonSale},
+		{ProductID: 1, Delta: -1, ResultingLevel: 2, Reason: models.StockReasonSale},
+	}
+
+	t.Run("confirmed sales are passed on", func(t *testing.T) {
+		stock := &fakeStockService{}
+		service := NewReservationService(&fakeReservationRepository{confirmed: sold}, stock, time.Minute, time.Hour)
+
+		if _, err := service.ConfirmReservation(1); err != nil {
+			t.Fatalf("ConfirmReservation() error = %v", err)
+		}
+		if len(stock.changed) != len(sold) {
+			t.Errorf("StockChanged() got %d movements, want %d", len(stock.changed), len(sold))
+		}
+	})
+
+	t.Run("nothing is passed on when the confirmation fails", func(t *testing.T) {
+		stock := &fakeStockService{}
+		service := NewReservationService(&fakeReservationRepository{}, stock, time.Minute, time.Hour)
+
+		if _, err := service.ConfirmReservation(1); err == nil {
+			t.Fatal("ConfirmReservation() succeeded, want an error")
+		}
+		if len(stock.changed) != 0 {
+			t.Errorf("StockChanged() got %d movements, want none", len(stock.changed))
+		}
+	})
+}
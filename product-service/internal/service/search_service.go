@@ -7,17 +7,37 @@ import (
 )
 
 type SearchService interface {
-	Search(query string, page, pageSize int) (*models.PaginatedResponse, error)
+	Search(query string, page, pageSize int, priceQuery models.PriceQuery) (*models.PaginatedResponse, error)
 }
 
 type searchService struct {
-	repo repository.ProductRepository
+	repo          repository.ProductRepository
+	priceListRepo repository.PriceListRepository
 }
 
-func NewSearchService(repo repository.ProductRepository) SearchService {
-	return &searchService{repo: repo}
+func NewSearchService(repo repository.ProductRepository, priceListRepo repository.PriceListRepository) SearchService {
+	return &searchService{repo: repo, priceListRepo: priceListRepo}
 }
 
-func (s *searchService) Search(query string, page, pageSize int) (*models.PaginatedResponse, error) {
-	return s.repo.Search(query, page, pageSize)
+// Search finds products matching the query, resolving their prices in the
+// price list or currency asked for, if any, like the product list does
+func (s *searchService) Search(query string, page, pageSize int, priceQuery models.PriceQuery) (*models.PaginatedResponse, error) {
+	var pricing *models.PriceContext
+	if priceQuery.IsSet() {
+		var err error
+		if pricing, err = resolvePricing(s.priceListRepo, priceQuery); err != nil {
+			return nil, err
+		}
+	}
+
+	result, err := s.repo.Search(query, page, pageSize)
+	if err != nil {
+		return nil, err
+	}
+	if pricing != nil {
+		if err := s.repo.AttachPrices(result.Items.([]models.Product), pricing); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
 }
@@ -0,0 +1,83 @@
+// internal/service/stock_take_service.go
+package service
+
+import (
+	"errors"
+	"fmt"
+
+	"phone-accessories/internal/models"
+	"phone-accessories/internal/repository"
+)
+
+type StockTakeService interface {
+	OpenStockTake(take *models.StockTake) error
+	GetStockTake(id uint) (*models.StockTake, error)
+	ListStockTakes(filter models.StockTakeFilter) (*models.PaginatedResponse, error)
+	RecordCounts(id uint, counts []models.StockTakeCount, actor string) (*models.StockTake, error)
+	GetVariance(id uint) (*models.StockTakeVarianceReport, error)
+	CommitStockTake(id uint, actor string, force bool) (*models.StockTakeVarianceReport, error)
+	CancelStockTake(id uint) (*models.StockTake, error)
+}
+
+type stockTakeService struct {
+	repo         repository.StockTakeRepository
+	categoryRepo repository.CategoryRepository
+	stockService ProductService
+}
+
+func NewStockTakeService(repo repository.StockTakeRepository, categoryRepo repository.CategoryRepository,
+	stockService ProductService) StockTakeService {
+	return &stockTakeService{repo: repo, categoryRepo: categoryRepo, stockService: stockService}
+}
+
+func (s *stockTakeService) OpenStockTake(take *models.StockTake) error {
+	if take.CategoryID != nil {
+		if _, err := s.categoryRepo.GetByID(*take.CategoryID); err != nil {
+			return fmt.Errorf("stock take category %d: %v", *take.CategoryID, err)
+		}
+	}
+
+	return s.repo.Open(take)
+}
+
+func (s *stockTakeService) GetStockTake(id uint) (*models.StockTake, error) {
+	return s.repo.GetByID(id)
+}
+
+func (s *stockTakeService) ListStockTakes(filter models.StockTakeFilter) (*models.PaginatedResponse, error) {
+	return s.repo.List(filter)
+}
+
+func (s *stockTakeService) RecordCounts(id uint, counts []models.StockTakeCount, actor string) (*models.StockTake, error) {
+	if len(counts) == 0 {
+		return nil, errors.New("at least one counted quantity is required")
+	}
+	for _, count := range counts {
+		if count.ProductID == 0 && count.SKU == "" {
+			return nil, errors.New("counted product ID or SKU is required")
+		}
+		if count.Quantity < 0 {
+			return nil, errors.New("counted quantity must not be negative")
+		}
+	}
+
+	return s.repo.RecordCounts(id, counts, actor)
+}
+
+func (s *stockTakeService) GetVariance(id uint) (*models.StockTakeVarianceReport, error) {
+	return s.repo.Variance(id)
+}
+
+func (s *stockTakeService) CommitStockTake(id uint, actor string, force bool) (*models.StockTakeVarianceReport, error) {
+	report, movements, err := s.repo.Commit(id, actor, force)
+	if err != nil {
+		return nil, err
+	}
+
+	s.stockService.StockChanged(movements...)
+	return report, nil
+}
+
+func (s *stockTakeService) CancelStockTake(id uint) (*models.StockTake, error) {
+	return s.repo.Cancel(id)
+}
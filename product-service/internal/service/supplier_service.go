@@ -0,0 +1,53 @@
+// internal/service/supplier_service.go
+package service
+
+import (
+	"errors"
+
+	"phone-accessories/internal/models"
+	"phone-accessories/internal/repository"
+)
+
+type SupplierService interface {
+	CreateSupplier(supplier *models.Supplier) error
+	GetSupplierByID(id uint) (*models.Supplier, error)
+	UpdateSupplier(supplier *models.Supplier) error
+	DeleteSupplier(id uint) error
+	ListSuppliers() ([]models.Supplier, error)
+}
+
+type supplierService struct {
+	repo repository.SupplierRepository
+}
+
+func NewSupplierService(repo repository.SupplierRepository) SupplierService {
+	return &supplierService{repo: repo}
+}
+
+func (s *supplierService) CreateSupplier(supplier *models.Supplier) error {
+	if supplier.Name == "" {
+		return errors.New("supplier name is required")
+	}
+
+	return s.repo.Create(supplier)
+}
+
+func (s *supplierService) GetSupplierByID(id uint) (*models.Supplier, error) {
+	return s.repo.GetByID(id)
+}
+
+func (s *supplierService) UpdateSupplier(supplier *models.Supplier) error {
+	if supplier.Name == "" {
+		return errors.New("supplier name is required")
+	}
+
+	return s.repo.Update(supplier)
+}
+
+func (s *supplierService) DeleteSupplier(id uint) error {
+	return s.repo.Delete(id)
+}
+
+func (s *supplierService) ListSuppliers() ([]models.Supplier, error) {
+	return s.repo.List()
+}
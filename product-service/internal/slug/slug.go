@@ -0,0 +1,92 @@
+// internal/slug/slug.go
+package slug
+
+import (
+	"strconv"
+	"strings"
+)
+
+// maxLength leaves room for a collision suffix within the 255 character column
+const maxLength = 200
+
+// transliterations spells accented letters found in French (and a few
+// neighbouring languages) with plain ASCII
+var transliterations = map[rune]string{
+	'à': "a", 'â': "a", 'ä': "a", 'á': "a", 'ã': "a", 'å': "a",
+	'ç': "c",
+	'é': "e", 'è': "e", 'ê': "e", 'ë': "e",
+	'î': "i", 'ï': "i", 'í': "i", 'ì': "i",
+	'ñ': "n",
+	'ô': "o", 'ö': "o", 'ó': "o", 'ò': "o", 'õ': "o",
+	'ù': "u", 'û': "u", 'ü': "u", 'ú': "u",
+	'ÿ': "y", 'ý': "y",
+	'œ': "oe", 'æ': "ae", 'ß': "ss",
+}
+
+// Make turns text into a lowercase URL slug made of ASCII letters, digits and
+// single dashes, so "Chargeurs et câbles" becomes "chargeurs-et-cables"
+func Make(text string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(text) {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+			b.WriteRune(r)
+			dash = false
+		case transliterations[r] != "":
+			b.WriteString(transliterations[r])
+			dash = false
+		case !dash && b.Len() > 0:
+			b.WriteByte('-')
+			dash = true
+		}
+	}
+
+	slug := strings.TrimSuffix(b.String(), "-")
+	if len(slug) > maxLength {
+		slug = strings.TrimSuffix(slug[:maxLength], "-")
+	}
+	return slug
+}
+
+// Derive returns the slug a record should have. An explicitly requested slug
+// wins. Otherwise the current slug is kept until the record is renamed, at
+// which point the slug follows the new name; a slug that was already generated
+// from that name, with or without a collision suffix, is kept as is.
+func Derive(requested, name, current, currentName, fallback string) string {
+	if requested != "" && requested != current {
+		if slug := Make(requested); slug != "" {
+			return slug
+		}
+	}
+	if current != "" && name == currentName {
+		return current
+	}
+
+	base := Make(name)
+	if base == "" {
+		base = fallback
+	}
+	if current == base || hasSuffix(current, base) {
+		return current
+	}
+	return base
+}
+
+// WithSuffix returns the n-th candidate for a slug that is already taken
+func WithSuffix(base string, n int) string {
+	if n <= 1 {
+		return base
+	}
+	return base + "-" + strconv.Itoa(n)
+}
+
+// hasSuffix reports whether slug is base followed by a collision suffix
+func hasSuffix(slug, base string) bool {
+	suffix, ok := strings.CutPrefix(slug, base+"-")
+	if !ok || suffix == "" {
+		return false
+	}
+	_, err := strconv.Atoi(suffix)
+	return err == nil
+}
@@ -0,0 +1,104 @@
+// internal/slug/slug_test.go
+package slug
+
+import "testing"
+
+func TestMake(t *testing.T) {
+	tests := []struct {
+		text string
+		want string
+	}{
+		{text: "Chargeurs et câbles", want: "chargeurs-et-cables"},
+		{text: "  USB-C -- Cable  ", want: "usb-c-cable"},
+		{text: "Œuvre", want: "oeuvre"},
+		{text: "!!!", want: ""},
+	}
+
+	for _, tt := range tests {
+		if got := Make(tt.text); got != tt.want {
+			t.Errorf("Make(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestDerive(t *testing.T) {
+	tests := []struct {
+		name        string
+		requested   string
+		newName     string
+		current     string
+		currentName string
+		fallback    string
+		want        string
+	}{
+		{
+			name:    "new record from its name",
+			newName: "Étui Cuir",
+			want:    "etui-cuir",
+		},
+		{
+			name:      "requested slug wins",
+			requested: "My Case",
+			newName:   "Étui Cuir",
+			want:      "my-case",
+		},
+		{
+			name:        "requested slug that is the current one",
+			requested:   "old-slug",
+			newName:     "New Name",
+			current:     "old-slug",
+			currentName: "Old Name",
+			want:        "new-name",
+		},
+		{
+			name:      "requested slug with nothing usable falls back to the name",
+			requested: "!!!",
+			newName:   "Étui Cuir",
+			want:      "etui-cuir",
+		},
+		{
+			name:        "kept while the name is unchanged",
+			newName:     "Étui Cuir",
+			current:     "custom-slug",
+			currentName: "Étui Cuir",
+			want:        "custom-slug",
+		},
+		{
+			name:        "follows a rename",
+			newName:     "Étui Silicone",
+			current:     "etui-cuir",
+			currentName: "Étui Cuir",
+			want:        "etui-silicone",
+		},
+		{
+			name:        "keeps a collision suffix for the same name",
+			newName:     "Étui  cuir",
+			current:     "etui-cuir-2",
+			currentName: "Étui Cuir",
+			want:        "etui-cuir-2",
+		},
+		{
+			name:        "a suffix that is not a number is not kept",
+			newName:     "Etui",
+			current:     "etui-cuir",
+			currentName: "Étui Cuir",
+			want:        "etui",
+		},
+		{
+			name:     "fallback when the name makes no slug",
+			newName:  "???",
+			fallback: "product-42",
+			want:     "product-42",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Derive(tt.requested, tt.newName, tt.current, tt.currentName, tt.fallback)
+			if got != tt.want {
+				t.Errorf("Derive(%q, %q, %q, %q, %q) = %q, want %q",
+					tt.requested, tt.newName, tt.current, tt.currentName, tt.fallback, got, tt.want)
+			}
+		})
+	}
+}
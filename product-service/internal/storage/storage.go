@@ -0,0 +1,12 @@
+// internal/storage/storage.go
+package storage
+
+import "io"
+
+// Storage keeps uploaded files, such as product images, and serves them by URL
+type Storage interface {
+	// Save stores the content under the given key and returns its public URL
+	Save(key string, content io.Reader, contentType string) (string, error)
+	// Delete removes the content stored under the given key, if any
+	Delete(key string) error
+}
@@ -8,6 +8,7 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 	"time"
 
@@ -22,8 +23,10 @@ import (
 	"phone-accessories/internal/api"
 	"phone-accessories/internal/config"
 	"phone-accessories/internal/models"
+	"phone-accessories/internal/notification"
 	"phone-accessories/internal/repository"
 	"phone-accessories/internal/service"
+	"phone-accessories/internal/storage"
 )
 
 // @title           Phone Accessories Product Service API
@@ -60,24 +63,132 @@ func main() {
 	}
 
 	// Auto migrate database models
-	if err := db.AutoMigrate(&models.Product{}, &models.Category{}); err != nil {
+	if err := db.AutoMigrate(&models.Product{}, &models.ProductOption{}, &models.Category{},
+		&models.Reservation{}, &models.ReservationItem{}, &models.StockMovement{},
+		&models.Location{}, &models.LocationStock{}, &models.StockTransfer{},
+		&models.Supplier{}, &models.PurchaseOrder{}, &models.PurchaseOrderLine{},
+		&models.StockTake{}, &models.StockTakeLine{},
+		&models.CostLayer{}, &models.CostConsumption{}, &models.ProductMedia{},
+		&models.Brand{}, &models.DeviceModel{}, &models.SlugRedirect{},
+		&models.AttributeDefinition{}, &models.BundleComponent{},
+		&models.ProductRelation{}, &models.PriceList{}, &models.ProductPrice{},
+		&models.ExchangeRate{}, &models.Promotion{}, &models.PriceTier{},
+		&models.DiscountRule{}, &models.DiscountRedemption{},
+		&models.QuoteRedemption{}, &models.DataMigration{}); err != nil {
 		log.Fatalf("Failed to migrate database: %v", err)
 	}
 
 	// Initialize repositories
 	productRepo := repository.NewProductRepository(db)
 	categoryRepo := repository.NewCategoryRepository(db)
+	reservationRepo := repository.NewReservationRepository(db)
+	movementRepo := repository.NewStockMovementRepository(db)
+	locationRepo := repository.NewLocationRepository(db)
+	supplierRepo := repository.NewSupplierRepository(db)
+	purchaseOrderRepo := repository.NewPurchaseOrderRepository(db)
+	stockTakeRepo := repository.NewStockTakeRepository(db)
+	reportRepo := repository.NewReportRepository(db)
+	mediaRepo := repository.NewMediaRepository(db)
+	relationRepo := repository.NewRelationRepository(db)
+	brandRepo := repository.NewBrandRepository(db)
+	deviceRepo := repository.NewDeviceRepository(db)
+	attributeRepo := repository.NewAttributeRepository(db)
+	priceListRepo := repository.NewPriceListRepository(db)
+	promotionRepo := repository.NewPromotionRepository(db)
+	priceTierRepo := repository.NewPriceTierRepository(db)
+	discountRepo := repository.NewDiscountRepository(db)
+
+	// Make sure stock changes without an explicit location have somewhere to go
+	if err := locationRepo.EnsureDefault(); err != nil {
+		log.Fatalf("Failed to set up default stock location: %v", err)
+	}
+
+	// Move prices stored before they had a currency into minor units
+	if err := productRepo.MigratePrices(); err != nil {
+		log.Fatalf("Failed to migrate product prices: %v", err)
+	}
+
+	// Move unit costs into minor units and give stock from before cost
+	// layers existed an opening layer
+	if err := reportRepo.MigrateCosts(); err != nil {
+		log.Fatalf("Failed to migrate stock costs: %v", err)
+	}
+	drift, err := reportRepo.CostDrift()
+	if err != nil {
+		log.Printf("Failed to check stock costs: %v", err)
+	}
+	for _, product := range drift {
+		log.Printf("Cost layers of product %d (%s) hold %d units but its stock level is %d",
+			product.ProductID, product.SKU, product.Layered, product.StockLevel)
+	}
+
+	// Give records created before slugs existed a slug
+	if err := categoryRepo.EnsureSlugs(); err != nil {
+		log.Fatalf("Failed to generate category slugs: %v", err)
+	}
+	if err := productRepo.EnsureSlugs(); err != nil {
+		log.Fatalf("Failed to generate product slugs: %v", err)
+	}
+
+	// Initialize low-stock notification sinks
+	var sinks []notification.Sink
+	if cfg.LowStockLogEnabled {
+		sinks = append(sinks, notification.NewLogSink())
+	}
+	if cfg.LowStockWebhookURL != "" {
+		sinks = append(sinks, notification.NewWebhookSink(cfg.LowStockWebhookURL, cfg.LowStockWebhookTimeout))
+	}
+	notifier := notification.NewDispatcher(sinks...)
 
 	// Initialize services
-	productService := service.NewProductService(productRepo)
+	productService := service.NewProductService(productRepo, movementRepo, attributeRepo, brandRepo, priceListRepo, notifier)
 	categoryService := service.NewCategoryService(categoryRepo)
-	searchService := service.NewSearchService(productRepo)
+	searchService := service.NewSearchService(productRepo, priceListRepo)
+	reservationService := service.NewReservationService(reservationRepo, productService, cfg.ReservationTTL, cfg.ReservationMaxTTL)
+	locationService := service.NewLocationService(locationRepo)
+	inventoryService := service.NewInventoryService(productRepo, locationRepo, productService)
+	supplierService := service.NewSupplierService(supplierRepo)
+	purchaseOrderService := service.NewPurchaseOrderService(purchaseOrderRepo, supplierRepo, productRepo, productService)
+	stockTakeService := service.NewStockTakeService(stockTakeRepo, categoryRepo, productService)
+	reportService := service.NewReportService(reportRepo)
+	mediaStorage := storage.NewLocalStorage(cfg.MediaStorageDir, cfg.MediaBaseURL)
+	mediaService := service.NewMediaService(mediaRepo, productRepo, mediaStorage, cfg.MediaMaxUploadSize)
+	relationService := service.NewRelationService(relationRepo, productRepo)
+	brandService := service.NewBrandService(brandRepo)
+	deviceService := service.NewDeviceService(deviceRepo, brandRepo, productRepo, priceListRepo)
+	attributeService := service.NewAttributeService(attributeRepo, categoryRepo)
+	priceListService := service.NewPriceListService(priceListRepo, productRepo)
+	promotionService := service.NewPromotionService(promotionRepo, productRepo, categoryRepo, brandRepo)
+	pricingService := service.NewPricingService(priceTierRepo, productRepo, discountRepo, categoryRepo,
+		priceListRepo, cfg.DefaultTaxRate, cfg.PricesIncludeTax)
+	discountService := service.NewDiscountService(discountRepo, productRepo, categoryRepo, brandRepo)
+
+	// Link products to the devices named in their free-text compatible attribute
+	linked, err := deviceService.BackfillCompatibility()
+	if err != nil {
+		log.Fatalf("Failed to migrate product compatibility: %v", err)
+	}
+	if linked > 0 {
+		log.Printf("Linked %d products to compatible devices", linked)
+	}
+
+	// Return expired reservation holds to available stock in the background
+	sweepCtx, stopSweeper := context.WithCancel(context.Background())
+	go sweepExpiredReservations(sweepCtx, reservationService, cfg.ReservationSweepInterval)
 
 	// Initialize Gin router
 	router := gin.Default()
 
 	// Setup API routes
-	api.SetupRoutes(router, productService, categoryService, searchService)
+	api.SetupRoutes(router, productService, categoryService, searchService, reservationService,
+		locationService, inventoryService, supplierService, purchaseOrderService, stockTakeService, reportService, mediaService,
+		relationService, brandService, deviceService, attributeService, priceListService,
+		promotionService, pricingService, discountService)
+
+	// Serve uploaded media unless it is hosted elsewhere
+	if strings.HasPrefix(cfg.MediaBaseURL, "/") {
+		router.Static(cfg.MediaBaseURL, cfg.MediaStorageDir)
+	}
 
 	// Setup Swagger
 	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
@@ -101,6 +212,7 @@ func main() {
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 	<-quit
 	log.Println("Shutting down server...")
+	stopSweeper()
 
 	// Create context with timeout for graceful shutdown
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
@@ -136,3 +248,24 @@ func setupDatabase(cfg *config.Config) (*gorm.DB, error) {
 
 	return db, err
 }
+
+func sweepExpiredReservations(ctx context.Context, reservationService service.ReservationService, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			released, err := reservationService.ReleaseExpired()
+			if err != nil {
+				log.Printf("Failed to release expired reservations: %v", err)
+				continue
+			}
+			if released > 0 {
+				log.Printf("Released %d expired reservations", released)
+			}
+		}
+	}
+}
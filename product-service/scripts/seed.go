@@ -6,6 +6,7 @@ import (
 	"log"
 	"os"
 	"phone-accessories/internal/models"
+	"phone-accessories/internal/slug"
 
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
@@ -96,6 +97,7 @@ func main() {
 
 	// Insertion des catégories
 	for i := range categories {
+		categories[i].Slug = slug.Make(categories[i].Name)
 		result := db.Create(&categories[i])
 		if result.Error != nil {
 			log.Fatalf("Erreur lors de la création de la catégorie %s: %v", categories[i].Name, result.Error)
@@ -108,7 +110,7 @@ func main() {
 		{
 			Name:        "Coque Silicone Premium iPhone 15",
 			Description: "Coque en silicone de haute qualité pour iPhone 15, absorbe les chocs et protège votre téléphone des rayures",
-			Price:       29.99,
+			Price:       models.NewMoney(2999, "EUR"),
 			SKU:         "CASE-IP15-S001",
 			StockLevel:  150,
 			ImageURL:    "https://example.com/images/products/iphone15-case.jpg",
@@ -124,7 +126,7 @@ func main() {
 		{
 			Name:        "Film Protection Écran Samsung Galaxy S23",
 			Description: "Film protecteur d'écran en verre trempé 9H pour Samsung Galaxy S23, installation facile et sans bulles",
-			Price:       15.99,
+			Price:       models.NewMoney(1599, "EUR"),
 			SKU:         "SCRN-SG23-G001",
 			StockLevel:  200,
 			ImageURL:    "https://example.com/images/products/s23-screen-protector.jpg",
@@ -140,7 +142,7 @@ func main() {
 		{
 			Name:        "Chargeur Rapide USB-C 30W",
 			Description: "Chargeur mural USB-C avec technologie Power Delivery pour une charge rapide et efficace",
-			Price:       24.99,
+			Price:       models.NewMoney(2499, "EUR"),
 			SKU:         "CHRG-PD30-W001",
 			StockLevel:  120,
 			ImageURL:    "https://example.com/images/products/usbc-charger-30w.jpg",
@@ -158,7 +160,7 @@ func main() {
 		{
 			Name:        "Câble USB-C vers Lightning 2m",
 			Description: "Câble de charge et synchronisation tressé de 2 mètres pour appareils Apple",
-			Price:       19.99,
+			Price:       models.NewMoney(1999, "EUR"),
 			SKU:         "CABLE-CL2M-B001",
 			StockLevel:  180,
 			ImageURL:    "https://example.com/images/products/usbc-lightning-cable.jpg",
@@ -175,7 +177,7 @@ func main() {
 		{
 			Name:        "Écouteurs Bluetooth Sans Fil",
 			Description: "Écouteurs intra-auriculaires sans fil avec réduction de bruit active et autonomie de 6 heures",
-			Price:       89.99,
+			Price:       models.NewMoney(8999, "EUR"),
 			SKU:         "AUDIO-TWS-B001",
 			StockLevel:  75,
 			ImageURL:    "https://example.com/images/products/wireless-earbuds.jpg",
@@ -194,7 +196,7 @@ func main() {
 		{
 			Name:        "Support Voiture Magnétique",
 			Description: "Support téléphone magnétique pour tableau de bord ou grille d'aération",
-			Price:       15.99,
+			Price:       models.NewMoney(1599, "EUR"),
 			SKU:         "MOUNT-CAR-M001",
 			StockLevel:  120,
 			ImageURL:    "https://example.com/images/products/car-mount.jpg",
@@ -210,7 +212,7 @@ func main() {
 		{
 			Name:        "Batterie Externe 20000mAh",
 			Description: "Powerbank haute capacité avec charge rapide et deux ports USB",
-			Price:       45.99,
+			Price:       models.NewMoney(4599, "EUR"),
 			SKU:         "PWBNK-20K-B001",
 			StockLevel:  60,
 			ImageURL:    "https://example.com/images/products/powerbank.jpg",
@@ -229,7 +231,7 @@ func main() {
 		{
 			Name:        "Support Bureau Ajustable",
 			Description: "Support de bureau réglable pour smartphones et tablettes jusqu'à 10 pouces",
-			Price:       21.99,
+			Price:       models.NewMoney(2199, "EUR"),
 			SKU:         "MOUNT-DESK-A001",
 			StockLevel:  90,
 			ImageURL:    "https://example.com/images/products/desk-stand.jpg",
@@ -247,7 +249,7 @@ func main() {
 		{
 			Name:        "Casque Bluetooth Supra-Auriculaire",
 			Description: "Casque sans fil avec réduction de bruit active et autonomie de 30 heures",
-			Price:       129.99,
+			Price:       models.NewMoney(12999, "EUR"),
 			SKU:         "AUDIO-HDPHN-B001",
 			StockLevel:  40,
 			ImageURL:    "https://example.com/images/products/bluetooth-headphones.jpg",
@@ -266,7 +268,7 @@ func main() {
 		{
 			Name:        "Chargeur Sans Fil 15W",
 			Description: "Station de charge à induction rapide compatible Qi pour smartphones",
-			Price:       34.99,
+			Price:       models.NewMoney(3499, "EUR"),
 			SKU:         "CHRG-WIRL-15W",
 			StockLevel:  85,
 			ImageURL:    "https://example.com/images/products/wireless-charger.jpg",
@@ -285,6 +287,7 @@ func main() {
 
 	// Insertion des produits
 	for i := range products {
+		products[i].Slug = slug.Make(products[i].Name)
 		result := db.Create(&products[i])
 		if result.Error != nil {
 			log.Fatalf("Erreur lors de la création du produit %s: %v", products[i].Name, result.Error)